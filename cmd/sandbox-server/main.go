@@ -4,9 +4,11 @@ import (
 	"OJ-API/config"
 	"OJ-API/database"
 	"OJ-API/gitclone"
+	"OJ-API/metrics"
 	"OJ-API/models"
 	pb "OJ-API/proto"
 	"OJ-API/sandbox"
+	"OJ-API/tracing"
 	"OJ-API/utils"
 	"context"
 	"crypto/tls"
@@ -16,11 +18,17 @@ import (
 	"os/signal"
 	"runtime"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -28,6 +36,38 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// version 由建置時的 -ldflags "-X main.version=..." 設定
+var version = "dev"
+
+// nodeState 節點的管理狀態，重新連線時回報給調度器以保留 cordon/drain 設定
+var nodeState = struct {
+	cordoned atomic.Bool
+	draining atomic.Bool
+	stopping atomic.Bool // 已要求關機，不再解除排空
+	labels   map[string]string
+	shutdown chan string // 調度器要求關機
+}{
+	shutdown: make(chan string, 1),
+}
+
+// currentStream 目前與調度器的連線，斷線時為 nil
+var currentStream = struct {
+	sync.Mutex
+	stream pb.SchedulerService_SandboxStreamClient
+}{}
+
+// lockedStream 確保同一時間只有一個 goroutine 對 stream 呼叫 Send
+type lockedStream struct {
+	pb.SchedulerService_SandboxStreamClient
+	mu sync.Mutex
+}
+
+func (l *lockedStream) Send(msg *pb.SandboxMessage) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.SchedulerService_SandboxStreamClient.Send(msg)
+}
+
 func main() {
 	// 初始化日誌
 	utils.InitLog()
@@ -38,6 +78,13 @@ func main() {
 		utils.Info("No .env.local file found")
 	}
 
+	// 初始化追蹤
+	shutdownTracing, err := tracing.Init(context.Background(), "oj-sandbox-server", version)
+	if err != nil {
+		utils.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// 初始化數據庫連接
 	if err := database.Connect(); err != nil {
 		utils.Fatalf("Failed to connect to database: %v", err)
@@ -54,20 +101,46 @@ func main() {
 	sandboxInstance := sandbox.NewSandbox(sandboxCount)
 	defer sandboxInstance.Cleanup()
 
+	// 生成唯一的沙箱 ID
+	sandboxID := uuid.New().String()
+	nodeState.labels = parseLabels(config.Config("SANDBOX_LABELS"))
+
+	// Prometheus 指標
+	sandboxInstance.RegisterMetrics()
+	metricsPort := config.Config("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9091"
+	}
+	go metrics.Serve(":" + metricsPort)
+
+	// 任務結束時通知調度器
+	sandboxInstance.SetJobDoneHandler(func(key sandbox.JobKey) {
+		sendJobFinished(sandboxID, key.Kind, uint64(key.ID))
+	})
+
 	// 啟動工作循環
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go sandboxInstance.WorkerLoop(ctx)
 
-	// 生成唯一的沙箱 ID
-	sandboxID := uuid.New().String()
-
 	// 連接到 API Server 調度器
 	schedulerAddress := config.Config("SCHEDULER_ADDRESS")
 	if schedulerAddress == "" {
 		schedulerAddress = "localhost:3001"
 	}
 
+	// 透過 API Server 取得程式碼，沙箱不需直接連線到 Gitea
+	if config.Config("GIT_CLONE_MODE") == "proxy" {
+		proxyURL := config.Config("SCHEDULER_HTTP_URL")
+		if proxyURL == "" {
+			proxyURL = "http://" + schedulerAddress
+			if config.Config("USE_TLS") == "true" {
+				proxyURL = "https://" + schedulerAddress
+			}
+		}
+		gitclone.EnableProxy(proxyURL, sandboxID, config.Config("SANDBOX_TOKEN"), config.Config("TLS_SKIP_VERIFY") == "true")
+	}
+
 	// 優雅關機處理
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -108,8 +181,12 @@ func main() {
 		}
 	}()
 
-	<-sigChan
-	utils.Info("Shutting down sandbox server...")
+	select {
+	case <-sigChan:
+		utils.Info("Shutting down sandbox server...")
+	case reason := <-nodeState.shutdown:
+		utils.Infof("Shutting down sandbox server as requested by scheduler: %s", reason)
+	}
 	cancel() // 停止工作循環
 
 	// 等待所有任務完成，但設置超時限制
@@ -210,10 +287,11 @@ func handleConnection(ctx context.Context, conn *grpc.ClientConn, sandboxID stri
 	schedulerClient := pb.NewSchedulerServiceClient(conn)
 
 	// 建立雙向流連接
-	stream, err := schedulerClient.SandboxStream(ctx)
+	rawStream, err := schedulerClient.SandboxStream(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to create stream: %v", err)
 	}
+	stream := &lockedStream{SchedulerService_SandboxStreamClient: rawStream}
 
 	// 發送連接請求
 	connectMsg := &pb.SandboxMessage{
@@ -222,6 +300,10 @@ func handleConnection(ctx context.Context, conn *grpc.ClientConn, sandboxID stri
 			Connect: &pb.SandboxConnectRequest{
 				SandboxId: sandboxID,
 				Capacity:  int32(sandboxInstance.AvailableCount() + sandboxInstance.ProcessingCount()),
+				Labels:    nodeState.labels,
+				Version:   version,
+				Cordoned:  nodeState.cordoned.Load(),
+				Draining:  nodeState.draining.Load(),
 			},
 		},
 	}
@@ -230,6 +312,15 @@ func handleConnection(ctx context.Context, conn *grpc.ClientConn, sandboxID stri
 		return fmt.Errorf("failed to send connect message: %v", err)
 	}
 
+	currentStream.Lock()
+	currentStream.stream = stream
+	currentStream.Unlock()
+	defer func() {
+		currentStream.Lock()
+		currentStream.stream = nil
+		currentStream.Unlock()
+	}()
+
 	// 創建用於停止 goroutines 的 context
 	streamCtx, streamCancel := context.WithCancel(ctx)
 	defer streamCancel()
@@ -292,14 +383,18 @@ func handleSchedulerMessages(stream pb.SchedulerService_SandboxStreamClient, san
 		case *pb.SchedulerMessage_JobRequest:
 			// 處理任務請求
 			jobReq := msgType.JobRequest
-			utils.Debugf("Received job request for repo: %s, commit: %s", jobReq.GitFullName, jobReq.GitAfterHash)
+			jobCtx := utils.WithJobID(utils.WithRequestID(context.Background(), jobReq.RequestId), jobReq.TargetId)
+			jobCtx = tracing.Extract(jobCtx, jobReq.TraceContext)
+			utils.Ctx(jobCtx).Infof("Received job request for repo: %s, commit: %s", jobReq.GitFullName, jobReq.GitAfterHash)
 
 			// 異步處理任務
 			go func() {
 				// 發送任務響應
-				responseMsg, err := AddJob(sandboxInstance, context.Background(), jobReq)
+				responseMsg, err := AddJob(sandboxInstance, jobCtx, jobReq)
 				if err != nil {
-					utils.Errorf("Failed to add job: %v", err)
+					utils.Ctx(jobCtx).Errorf("Failed to add job: %v", err)
+					// 任務未進入佇列，直接通知調度器結束
+					sendJobFinished(msg.SandboxId, jobReq.Kind, jobReq.TargetId)
 					return
 				}
 
@@ -323,10 +418,71 @@ func handleSchedulerMessages(stream pb.SchedulerService_SandboxStreamClient, san
 			if err := sendCurrentStatus(stream, msg.SandboxId, sandboxInstance); err != nil {
 				utils.Debugf("Failed to send status response: %v", err)
 			}
+
+		case *pb.SchedulerMessage_Drain:
+			// 調度器已停止派發，已收到的任務照常完成
+			if !msgType.Drain.Draining && nodeState.stopping.Load() {
+				utils.Info("Sandbox is shutting down, ignoring undrain")
+				break
+			}
+			nodeState.draining.Store(msgType.Drain.Draining)
+			utils.Infof("Sandbox draining=%t", msgType.Drain.Draining)
+
+		case *pb.SchedulerMessage_Cordon:
+			nodeState.cordoned.Store(msgType.Cordon.Cordoned)
+			utils.Infof("Sandbox cordoned=%t", msgType.Cordon.Cordoned)
+
+		case *pb.SchedulerMessage_CancelJob:
+			cancelReq := msgType.CancelJob
+			utils.Infof("Received cancel request for %s job %d: %s", cancelReq.Kind, cancelReq.TargetId, cancelReq.Reason)
+			if !sandboxInstance.CancelJob(sandbox.JobKey{Kind: cancelReq.Kind, ID: uint(cancelReq.TargetId)}, cancelReq.Reason) {
+				utils.Debugf("%s job %d is not queued or running here, nothing to cancel", cancelReq.Kind, cancelReq.TargetId)
+			}
+
+		case *pb.SchedulerMessage_Shutdown:
+			nodeState.stopping.Store(true)
+			nodeState.draining.Store(true)
+			select {
+			case nodeState.shutdown <- msgType.Shutdown.Reason:
+			default: // 已經在關機中
+			}
 		}
 	}
 }
 
+// sendJobFinished 通知調度器任務已結束，未連線時略過（重新連線後調度器會重建狀態）
+func sendJobFinished(sandboxID string, kind pb.JobKind, targetID uint64) {
+	currentStream.Lock()
+	stream := currentStream.stream
+	currentStream.Unlock()
+	if stream == nil {
+		return
+	}
+
+	msg := &pb.SandboxMessage{
+		SandboxId: sandboxID,
+		MessageType: &pb.SandboxMessage_JobFinished{
+			JobFinished: &pb.JobFinished{TargetId: targetID, Kind: kind},
+		},
+	}
+	if err := stream.Send(msg); err != nil {
+		utils.Debugf("Failed to send job finished for %s job %d: %v", kind, targetID, err)
+	}
+}
+
+// parseLabels 解析 "key=value,key2=value2" 格式的節點標籤
+func parseLabels(raw string) map[string]string {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+		if key == "" {
+			continue
+		}
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return labels
+}
+
 // sendStatusUpdates 定期發送狀態更新
 func sendStatusUpdates(ctx context.Context, stream pb.SchedulerService_SandboxStreamClient, sandboxID string, sandboxInstance *sandbox.Sandbox) error {
 	ticker := time.NewTicker(300 * time.Millisecond)
@@ -412,27 +568,66 @@ func sendCurrentStatus(stream pb.SchedulerService_SandboxStreamClient, sandboxID
 }
 
 // AddJob 添加任務到隊列
-func AddJob(sandboxInstance *sandbox.Sandbox, ctx context.Context, req *pb.AddJobRequest) (*pb.AddJobResponse, error) {
+func AddJob(sandboxInstance *sandbox.Sandbox, ctx context.Context, req *pb.AddJobRequest) (resp *pb.AddJobResponse, err error) {
+	ctx, span := tracing.Start(ctx, "sandbox.AddJob", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("oj.job_kind", req.Kind.String()),
+			attribute.Int64("oj.target_id", int64(req.TargetId)),
+			attribute.String("oj.repository", req.GitFullName),
+		))
+	// 複製 repository 期間收到的取消請求也需保留到任務開始
+	key := sandbox.JobKey{Kind: req.Kind, ID: uint(req.TargetId)}
+	sandboxInstance.AcceptJob(key)
+	defer func() {
+		if err != nil {
+			sandboxInstance.DropJob(key)
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	sandboxInstance.SubtractAvailableCount()
 	defer sandboxInstance.AddAvailableCount()
 	// 從數據庫獲取完整的 UserQuestionTable 模型，包含關聯的 UQR 和 Question
 	var uqr models.UserQuestionTable
-	if err := database.DBConn.Preload("UQR.Question").First(&uqr, req.UserQuestionTableId).Error; err != nil {
+	if req.Kind == pb.JobKind_JOB_KIND_PRACTICE {
+		// 練習評測沿用同一套流程，結果另外寫回 practice_runs
+		var run models.PracticeRun
+		if err := database.DBConn.Preload("UQR.Question").First(&run, req.TargetId).Error; err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get practice run: %v", err)
+		}
+		uqr = models.UserQuestionTable{
+			ID:        run.ID,
+			UQRID:     run.UQRID,
+			UQR:       run.UQR,
+			Commit:    run.Commit,
+			RequestID: run.RequestID,
+		}
+	} else if err := database.DBConn.Preload("UQR.Question").First(&uqr, req.TargetId).Error; err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get user question table: %v", err)
 	}
 
-	codePath, err := gitclone.CloneRepository(req.GitFullName, req.GitRepoUrl, req.GitAfterHash, req.GitUsername, req.GitToken)
+	_, cloneSpan := tracing.Start(ctx, "gitclone.FetchRepository",
+		trace.WithAttributes(attribute.String("oj.commit", req.GitAfterHash)))
+	codePath, err := gitclone.FetchRepository(req.GitFullName, req.GitRepoUrl, req.GitAfterHash, req.GitUsername, req.GitToken)
+	if err != nil {
+		cloneSpan.RecordError(err)
+		cloneSpan.SetStatus(otelcodes.Error, err.Error())
+	}
+	cloneSpan.End()
 
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to clone repository: %v", err)
 	}
 
 	// 添加任務到隊列
-	sandboxInstance.ReserveJob(req.ParentGitFullName, []byte(codePath), uqr)
+	utils.Ctx(ctx).Debugf("Repository %s fetched, queueing job", req.GitFullName)
+	sandboxInstance.ReserveJob(ctx, req.ParentGitFullName, []byte(codePath), uqr, req.Kind)
 
 	return &pb.AddJobResponse{
 		Success: true,
 		Message: "Job added to queue successfully",
-		JobId:   fmt.Sprintf("job_%d_%s", req.UserQuestionTableId, req.GitFullName),
+		JobId:   fmt.Sprintf("job_%s_%d_%s", req.Kind, req.TargetId, req.GitFullName),
 	}, nil
 }
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -62,6 +64,28 @@ func GetFrontendURL() string {
 	return frontendURL
 }
 
+// GetResultPageURL returns the frontend page of a submission's result, linked from Gitea commit statuses.
+// RESULT_PAGE_URL may contain {id} for the submission ID.
+func GetResultPageURL(uqtID uint) string {
+	pageURL := Config("RESULT_PAGE_URL")
+	if pageURL == "" {
+		pageURL = GetFrontendURL() + "/submissions/{id}" // Default to the frontend's submission page if not provided
+	}
+	return strings.ReplaceAll(pageURL, "{id}", strconv.FormatUint(uint64(uqtID), 10))
+}
+
+// GetTrustedProxies returns the proxies (IPs or CIDRs) whose forwarding headers are trusted for the client IP.
+// TRUSTED_PROXIES is a comma-separated list; when empty no forwarding header is trusted.
+func GetTrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(Config("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 func GetIsolatePath() string {
 	isolatePath := Config("ISOLATE_PATH")
 	if isolatePath == "" {
@@ -14,16 +14,25 @@ import (
 // DBConn is a pointer to gorm.DB
 var DBConn *gorm.DB
 
-// Connect creates a connection to database
-func Connect() (err error) {
+// DSN returns the connection string of the Postgres database
+func DSN() (string, error) {
 	p := config.Config("DB_PORT")
 	port, err := strconv.ParseUint(p, 10, 32)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Connection URL to connect to Postgres Database
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", config.Config("DB_HOST"), port, config.Config("DB_USER"), config.Config("DB_PASSWORD"), config.Config("DB_NAME"))
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", config.Config("DB_HOST"), port, config.Config("DB_USER"), config.Config("DB_PASSWORD"), config.Config("DB_NAME")), nil
+}
+
+// Connect creates a connection to database
+func Connect() (err error) {
+	dsn, err := DSN()
+	if err != nil {
+		return err
+	}
+
 	// Connect to the DB and initialize the DB variable
 	DBConn, err = gorm.Open(postgres.Open(dsn))
 	if err != nil {
@@ -22,7 +22,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Export question score to CSV, XLSX, or JSON",
+                "description": "Export question score to CSV, XLSX, or JSON. Questions in a course require the export permission in that course and only include its students.",
                 "consumes": [
                     "application/json"
                 ],
@@ -396,6 +396,79 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/admin/{id}/user/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign the instructor, ta or student role to a user. The admin role follows the Gitea site admin flag and cannot be assigned here. The new role takes effect when the user's access token is refreshed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update user role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateUserRoleDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
         "/api/auth/login": {
             "post": {
                 "description": "Use basic authentication or token to login and get access token and refresh token",
@@ -563,21 +636,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/exams": {
+        "/api/courses": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of all exams",
+                "description": "List the courses the user is enrolled in, with the user's role in each. Users who can manage every course see all courses.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exam"
+                    "Course"
                 ],
-                "summary": "List all exams",
+                "summary": "List courses",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -592,7 +665,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/handlers.ExamListData"
+                                                "$ref": "#/definitions/handlers.CourseListData"
                                             }
                                         }
                                     }
@@ -600,6 +673,9 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -609,14 +685,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/exams/admin": {
+        "/api/courses/admin": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new exam with the provided details",
+                "description": "Create a course. The creator is enrolled as the course's instructor.",
                 "consumes": [
                     "application/json"
                 ],
@@ -624,17 +700,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exam"
+                    "Course"
                 ],
-                "summary": "Create a new exam",
+                "summary": "Create a course",
                 "parameters": [
                     {
-                        "description": "Exam details",
-                        "name": "exam",
+                        "description": "Course details",
+                        "name": "course",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExamRequest"
+                            "$ref": "#/definitions/handlers.CourseRequest"
                         }
                     }
                 ],
@@ -650,7 +726,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Exam"
+                                            "$ref": "#/definitions/models.Course"
                                         }
                                     }
                                 }
@@ -658,7 +734,10 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized"
@@ -667,31 +746,34 @@ const docTemplate = `{
                         "description": "Forbidden"
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/api/exams/admin/{id}/exam": {
-            "get": {
+        "/api/courses/admin/{course_id}/course": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve an exam's details using its ID",
+                "description": "Delete a course and its enrollments. A course that still owns questions or exams cannot be deleted; move or delete them first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exam"
+                    "Course"
                 ],
-                "summary": "Get an exam by ID",
+                "summary": "Delete a course",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Exam ID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "Course ID",
+                        "name": "course_id",
                         "in": "path",
                         "required": true
                     }
@@ -700,36 +782,42 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handlers.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.Exam"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the details of an existing exam by ID",
+                "description": "Update a course's code, name or description. Empty fields are left unchanged.",
                 "consumes": [
                     "application/json"
                 ],
@@ -737,24 +825,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exam"
+                    "Course"
                 ],
-                "summary": "Update an existing exam",
+                "summary": "Update a course",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Exam ID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "Course ID",
+                        "name": "course_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated exam details",
-                        "name": "exam",
+                        "description": "Updated course details",
+                        "name": "course",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.UpdateExamRequest"
+                            "$ref": "#/definitions/handlers.CourseRequest"
                         }
                     }
                 ],
@@ -770,7 +858,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.UpdateExamRequest"
+                                            "$ref": "#/definitions/models.Course"
                                         }
                                     }
                                 }
@@ -783,6 +871,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -796,37 +890,75 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/courses/admin/{course_id}/members": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an exam using its ID",
+                "description": "Enroll users in a course with the given course role (student by default). Users already enrolled have their role updated.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exam"
+                    "Course"
                 ],
-                "summary": "Delete an exam by ID",
+                "summary": "Add course members",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Exam ID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "Course ID",
+                        "name": "course_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Users to enroll",
+                        "name": "members",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddCourseMembersRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.AddCourseMembersResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -842,47 +974,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/exams/admin/{id}/questions/{question_id}/question": {
-            "put": {
+        "/api/courses/admin/{course_id}/members/{user_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the score associated with a question in a specific exam",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Remove a user's enrollment from a course",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exam"
+                    "Course"
                 ],
-                "summary": "Update a question's score in an exam",
+                "summary": "Remove a course member",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Exam ID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "Course ID",
+                        "name": "course_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Question ID",
-                        "name": "question_id",
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated score for the question",
-                        "name": "point",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.point"
-                        }
                     }
                 ],
                 "responses": {
@@ -892,6 +1012,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -905,112 +1031,125 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/courses/{course_id}/course": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Associate a question with a specific exam",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve a course the user is enrolled in",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exam"
+                    "Course"
                 ],
-                "summary": "Add a question to an exam",
+                "summary": "Get a course",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Exam ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Question ID",
-                        "name": "question_id",
+                        "type": "integer",
+                        "description": "Course ID",
+                        "name": "course_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Score for the question",
-                        "name": "point",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.point"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.CourseListData"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ResponseHTTP"
-                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/courses/{course_id}/members": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Disassociate a question from a specific exam",
+                "description": "List the users enrolled in a course and their course roles",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exam"
+                    "Course"
                 ],
-                "summary": "Remove a question from an exam",
+                "summary": "List course members",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Exam ID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "Course ID",
+                        "name": "course_id",
                         "in": "path",
                         "required": true
                     },
                     {
+                        "enum": [
+                            "instructor",
+                            "ta",
+                            "student"
+                        ],
                         "type": "string",
-                        "description": "Question ID",
-                        "name": "question_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only list members with this course role",
+                        "name": "role",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handlers.CourseMemberData"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ResponseHTTP"
-                        }
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -1021,28 +1160,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/exams/{id}/exam": {
+        "/api/exams": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve basic information about an exam, including title, description, start and end times",
+                "description": "Retrieve a list of all exams outside any course or in the user's courses",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Exam"
                 ],
-                "summary": "Get basic information about an exam",
+                "summary": "List all exams",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Exam ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Only list exams of this course",
+                        "name": "course_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1057,15 +1195,18 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.ExamInfoResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handlers.ExamListData"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
@@ -1079,14 +1220,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/exams/{id}/leaderboard": {
-            "get": {
+        "/api/exams/admin": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve the leaderboard for a specific exam (Optional Authentication if Admin will show all users, otherwise only public users)",
+                "description": "Create a new exam with the provided details",
                 "consumes": [
                     "application/json"
                 ],
@@ -1096,26 +1237,16 @@ const docTemplate = `{
                 "tags": [
                     "Exam"
                 ],
-                "summary": "Get the leaderboard for an exam",
+                "summary": "Create a new exam",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Exam ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number of results to return (1-based)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size of results. Default is 10.",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Exam details",
+                        "name": "exam",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExamRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1130,7 +1261,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.EnhancedGetLeaderboardResponseData"
+                                            "$ref": "#/definitions/models.Exam"
                                         }
                                     }
                                 }
@@ -1143,23 +1274,23 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized"
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "403": {
+                        "description": "Forbidden"
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "500": {
+                        "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/api/exams/{id}/questions": {
-            "get": {
+        "/api/exams/admin/{id}/access": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all questions associated with a specific exam with user's question status and top scores (if authenticated)",
+                "description": "Set the client networks (CIDRs or single addresses) the exam may be taken from and whether a one-time access code is required. An empty list allows every network.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1169,7 +1300,7 @@ const docTemplate = `{
                 "tags": [
                     "Exam"
                 ],
-                "summary": "Get questions for an exam",
+                "summary": "Update exam access restrictions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1179,16 +1310,13 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "page number of results to return (1-based)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size of results. Default is 10.",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Access restrictions",
+                        "name": "access",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateExamAccessRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1203,13 +1331,28 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.ExamQuestionsResponse"
+                                            "$ref": "#/definitions/models.Exam"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1225,21 +1368,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/exams/{id}/score/top": {
+        "/api/exams/admin/{id}/access_codes": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve the top scores for each question in a specific exam for a user",
+                "description": "List the exam's one-time access codes and who used them",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Exam"
                 ],
-                "summary": "Get top scores for each question in an exam",
+                "summary": "List exam access codes",
                 "parameters": [
                     {
                         "type": "string",
@@ -1247,18 +1390,6 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page number of results to return (1-based)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size of results. Default is 10.",
-                        "name": "limit",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1273,31 +1404,46 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.GetTopExamScoreResponseData"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ExamAccessCode"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
-            }
-        },
-        "/api/gitea": {
+            },
             "post": {
-                "description": "Receive Gitea hook",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate one-time access codes for the exam. Each code can start one user's exam session.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1305,17 +1451,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Gitea"
+                    "Exam"
                 ],
-                "summary": "Receive Gitea hook",
+                "summary": "Generate exam access codes",
                 "parameters": [
                     {
-                        "description": "Gitea Hook",
-                        "name": "hook",
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Number of codes",
+                        "name": "codes",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.WebhookPayload"
+                            "$ref": "#/definitions/handlers.GenerateExamAccessCodesRequest"
                         }
                     }
                 ],
@@ -1330,28 +1483,40 @@ const docTemplate = `{
                                 {
                                     "type": "object",
                                     "properties": {
-                                        "type": {
-                                            "$ref": "#/definitions/handlers.WebhookPayload"
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ExamAccessCode"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
-                    "410": {
-                        "description": "Gone",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
@@ -1359,38 +1524,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/gitea/admin/user/bulk": {
-            "post": {
+        "/api/exams/admin/{id}/exam": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Bulk create User",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve an exam's details using its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Gitea"
+                    "Exam"
                 ],
-                "summary": "Bulk create User",
+                "summary": "Get an exam by ID",
                 "parameters": [
                     {
-                        "description": "Username + Email Domain =\u003e username1@example.com",
-                        "name": "Usernames",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.BulkCreateUser"
-                        }
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Return successful and failed users",
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -1400,36 +1560,34 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.BulkCreateUserResponse"
+                                            "$ref": "#/definitions/models.Exam"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
-                    "401": {
-                        "description": "Unauthorized"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "404": {
-                        "description": "Not Found"
-                    },
-                    "503": {
-                        "description": "Service Unavailable"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
-            }
-        },
-        "/api/gitea/admin/user/bulk_v2": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Bulk create User v2",
+                "description": "Update the details of an existing exam by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1437,23 +1595,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Gitea"
+                    "Exam"
                 ],
-                "summary": "Bulk create User v2",
+                "summary": "Update an existing exam",
                 "parameters": [
                     {
-                        "description": "User Email, Username, Password",
-                        "name": "BulkCreateUserRequest",
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated exam details",
+                        "name": "exam",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.BulkCreateUserRequest"
+                            "$ref": "#/definitions/handlers.UpdateExamRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Return successful and failed users",
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -1463,7 +1628,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.BulkCreateUserResponse"
+                                            "$ref": "#/definitions/handlers.UpdateExamRequest"
                                         }
                                     }
                                 }
@@ -1471,28 +1636,90 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "401": {
-                        "description": "Unauthorized"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an exam using its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Delete an exam by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/api/gitea/user": {
-            "get": {
+        "/api/exams/admin/{id}/freeze": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get User Profile",
+                "description": "Set the time the public exam leaderboard freezes. From then on it shows scores as of the freeze time, while judging continues and staff still see live results. Setting a freeze time again refreezes an unfrozen exam.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1500,9 +1727,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Gitea"
+                    "Exam"
+                ],
+                "summary": "Freeze an exam leaderboard",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Freeze time",
+                        "name": "freeze",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FreezeExamRequest"
+                        }
+                    }
                 ],
-                "summary": "Get User Profile",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1515,7 +1760,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/gitea.User"
+                                            "$ref": "#/definitions/models.Exam"
                                         }
                                     }
                                 }
@@ -1523,38 +1768,59 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/api/gitea/user/keys": {
+        "/api/exams/admin/{id}/overrides": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "List all public keys",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the users with a personal exam window (extra time or make-up times)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Gitea"
+                    "Exam"
+                ],
+                "summary": "List exam overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List all public keys",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1569,7 +1835,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/gitea.PublicKey"
+                                                "$ref": "#/definitions/handlers.ExamOverrideData"
                                             }
                                         }
                                     }
@@ -1577,27 +1843,38 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/exams/admin/{id}/overrides/{user_id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a public key in Gitea",
+                "description": "Give a user a personal exam window. start_time and end_time replace the exam's times (make-up exams); extra_percent and extra_minutes lengthen the exam, or each user's time on timed exams (accommodations).",
                 "consumes": [
                     "application/json"
                 ],
@@ -1605,17 +1882,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Gitea"
+                    "Exam"
                 ],
-                "summary": "Create a public key in Gitea",
+                "summary": "Set an exam override",
                 "parameters": [
                     {
-                        "description": "Public Key",
-                        "name": "CreatePublicKey",
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Override",
+                        "name": "override",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreatePublicKey"
+                            "$ref": "#/definitions/handlers.ExamOverrideRequest"
                         }
                     }
                 ],
@@ -1623,96 +1914,76 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ExamUserOverride"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized"
                     },
-                    "404": {
-                        "description": "Not Found"
-                    },
-                    "503": {
-                        "description": "Service Unavailable"
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete a public key",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Gitea"
-                ],
-                "summary": "Delete a public key",
-                "parameters": [
-                    {
-                        "description": "Public Key ID",
-                        "name": "DeletePublicKey",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.DeletePublicKey"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
-                    "401": {
-                        "description": "Unauthorized"
-                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
-            }
-        },
-        "/api/gitea/{question_id}/question": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Take a question and migrate it to a private repository in Gitea (migrates the entire repo content to a new private repo)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Remove a user's personal exam window so the exam's own times apply again",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Gitea"
+                    "Exam"
                 ],
-                "summary": "Take a question and migrate it to a private repository in Gitea",
+                "summary": "Delete an exam override",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "Question ID",
-                        "name": "question_id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
@@ -1724,57 +1995,52 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/api/questions": {
+        "/api/exams/admin/{id}/participants": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of questions. Authentication is optional - if authenticated, shows user's question status and top score.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the users allowed to take the exam and their session status. An empty list means the exam is open to everyone who can see it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Question"
+                    "Exam"
                 ],
-                "summary": "Get a list of questions [Optional Authentication]",
+                "summary": "List exam participants",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "page number of results to return (1-based)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size of results. Default is 10.",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Filter by question status: 'all', 'active', or 'expired'. Default is 'all'.",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1791,7 +2057,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/handlers.GetQuestionListResponseData"
+                                                "$ref": "#/definitions/handlers.ExamParticipantData"
                                             }
                                         }
                                     }
@@ -1799,23 +2065,36 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
-            }
-        },
-        "/api/questions/admin/question": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add a question",
+                "description": "Add users to the exam's participant list. Once the list is not empty, only listed users can take the exam.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1823,17 +2102,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Question"
+                    "Exam"
                 ],
-                "summary": "Add a question",
+                "summary": "Add exam participants",
                 "parameters": [
                     {
-                        "description": "Question object",
-                        "name": "question",
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Users to add",
+                        "name": "participants",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.AddQuestionRequest"
+                            "$ref": "#/definitions/handlers.AddExamParticipantsRequest"
                         }
                     }
                 ],
@@ -1849,7 +2135,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.AddQuestionResponse"
+                                            "$ref": "#/definitions/handlers.AddCourseMembersResponse"
                                         }
                                     }
                                 }
@@ -1857,43 +2143,62 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/api/questions/admin/{ID}/question": {
+        "/api/exams/admin/{id}/participants/{user_id}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a question",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Remove a user from the exam's participant list",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Question"
+                    "Exam"
                 ],
-                "summary": "Delete a question",
+                "summary": "Remove an exam participant",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "ID of the Question to delete",
-                        "name": "ID",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
@@ -1902,42 +2207,2128 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handlers.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.Question"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
                         "description": "Unauthorized"
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/exams/admin/{id}/questions/{question_id}/question": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the score associated with a question in a specific exam",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Update a question's score in an exam",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated score for the question",
+                        "name": "point",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.point"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             },
-            "patch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Associate a question with a specific exam",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Add a question to an exam",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Score for the question",
+                        "name": "point",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.point"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disassociate a question from a specific exam",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Remove a question from an exam",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/exams/admin/{id}/sessions/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clear a user's exam session so they can start again, for example from another machine. The access code they used stays consumed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Reset an exam session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/exams/admin/{id}/unfreeze": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reveal the live exam leaderboard, including the submissions made since the freeze",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Unfreeze an exam leaderboard",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Exam"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/exams/{id}/exam": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve basic information about an exam, including title, description, start and end times",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Get basic information about an exam",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.ExamInfoResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/exams/{id}/leaderboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the leaderboard for a specific exam (Optional Authentication if Admin will show all users, otherwise only public users). ICPC mode exams rank by problems solved at full score, then penalty minutes, and show per-problem attempts and solve times. Once the exam's freeze time passes, only staff see live results; everyone else sees scores as of the freeze until the exam is unfrozen.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Get the leaderboard for an exam",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number of results to return (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size of results. Default is 10.",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.EnhancedGetLeaderboardResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/exams/{id}/questions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all questions associated with a specific exam with user's question status and top scores (if authenticated)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Get questions for an exam",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number of results to return (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size of results. Default is 10.",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.ExamQuestionsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/exams/{id}/score/top": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the top scores for each question in a specific exam for a user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Get top scores for each question in an exam",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number of results to return (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size of results. Default is 10.",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GetTopExamScoreResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/exams/{id}/session": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check the exam's participant list, allowed networks and access code, and start the user's session. A one-time access code is only needed the first time; later calls just record the user's current address. Submissions to a network-restricted exam are only accepted within 15 minutes of the user last opening it from an allowed network. On timed exams the user's time starts with their first session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exam"
+                ],
+                "summary": "Start an exam session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Access code",
+                        "name": "session",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StartExamSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ExamSession"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/gitea": {
+            "post": {
+                "description": "Receive Gitea hook. Only pushes to the question's trigger branches (main and master by default) that change files under its path filters are judged; pushes to practice/* branches start practice runs. Other pushes are answered with the reason and recorded as skipped. Exam restrictions and late policies apply to the repository owner, whoever pushed. Events must be signed by Gitea with the repository's webhook secret. A repeated X-Gitea-Delivery or an already submitted commit returns the ID, score and status of the existing submission instead of judging again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gitea"
+                ],
+                "summary": "Receive Gitea hook",
+                "parameters": [
+                    {
+                        "description": "Gitea Hook",
+                        "name": "hook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookPayload"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "hex HMAC-SHA256 of the body keyed with the repository's webhook secret",
+                        "name": "X-Gitea-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "delivery ID, the same across retries",
+                        "name": "X-Gitea-Delivery",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "type": {
+                                            "$ref": "#/definitions/handlers.WebhookPayload"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/gitea/admin/user/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bulk create User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gitea"
+                ],
+                "summary": "Bulk create User",
+                "parameters": [
+                    {
+                        "description": "Username + Email Domain =\u003e username1@example.com",
+                        "name": "Usernames",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BulkCreateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Return successful and failed users",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.BulkCreateUserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/gitea/admin/user/bulk_v2": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bulk create User v2",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gitea"
+                ],
+                "summary": "Bulk create User v2",
+                "parameters": [
+                    {
+                        "description": "User Email, Username, Password",
+                        "name": "BulkCreateUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BulkCreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Return successful and failed users",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.BulkCreateUserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/gitea/user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get User Profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gitea"
+                ],
+                "summary": "Get User Profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gitea.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/gitea/user/keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List all public keys",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gitea"
+                ],
+                "summary": "List all public keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gitea.PublicKey"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a public key in Gitea",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gitea"
+                ],
+                "summary": "Create a public key in Gitea",
+                "parameters": [
+                    {
+                        "description": "Public Key",
+                        "name": "CreatePublicKey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreatePublicKey"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a public key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gitea"
+                ],
+                "summary": "Delete a public key",
+                "parameters": [
+                    {
+                        "description": "Public Key ID",
+                        "name": "DeletePublicKey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DeletePublicKey"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/gitea/{question_id}/question": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take a question and migrate it to a private repository in Gitea (migrates the entire repo content to a new private repo)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gitea"
+                ],
+                "summary": "Take a question and migrate it to a private repository in Gitea",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/practice/{question_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the user's practice runs of a question, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Practice"
+                ],
+                "summary": "List practice runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number of results to return (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size of results. Default is 10.",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GetPracticeRunsResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Judge the user's repository against the public test suites only. Only targets whose score map task lists a public suite are compiled and run, with GTEST_FILTER and OJ_TEST_SUITES set to those suites. Practice runs are stored apart from graded submissions and never count towards scores, leaderboards or exports. Pushing to a practice/* branch starts one as well.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Practice"
+                ],
+                "summary": "Start a practice run",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Commit to judge",
+                        "name": "run",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PracticeRunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PracticeRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/questions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of questions. Authentication is optional - if authenticated, shows user's question status and top score.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question"
+                ],
+                "summary": "Get a list of questions [Optional Authentication]",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page number of results to return (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size of results. Default is 10.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by question status: 'all', 'active', or 'expired'. Default is 'all'.",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only list questions of this course",
+                        "name": "course_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handlers.GetQuestionListResponseData"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/questions/admin/question": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a question",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question"
+                ],
+                "summary": "Add a question",
+                "parameters": [
+                    {
+                        "description": "Question object",
+                        "name": "question",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.AddQuestionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/questions/admin/{ID}/question": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a question",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question"
+                ],
+                "summary": "Delete a question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the Question to delete",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Question"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a question",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question"
+                ],
+                "summary": "Update a question",
+                "parameters": [
+                    {
+                        "description": "Question object",
+                        "name": "question",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PatchQuestionRequest"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the Question to update",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Question"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/questions/admin/{ID}/question_limit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve only public questions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question"
+                ],
+                "summary": "Get a question limitation by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the Question to get",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GetQuestionResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/questions/admin/{ID}/scripts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the scripts for a question. Including compile script,execute script and score script.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question"
+                ],
+                "summary": "Get the scripts for a question.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the Question to get the scripts for",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.QuestionScripts"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/questions/user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of questions by user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question"
+                ],
+                "summary": "Get a list of questions by user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page number of results to return (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size of results. Default is 10.",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handlers.GetUsersQuestionsResponseData"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/questions/user/{ID}/question": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific question associated with a user by its Question ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question"
+                ],
+                "summary": "Get a user's question by Question ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the Question to get",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GetUserQuestionResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/questions/{ID}/question": {
+            "get": {
+                "description": "Retrieve only public questions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question"
+                ],
+                "summary": "Get a question by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the Question to get",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.GetQuestionResponseData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/sandbox/admin/instances": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every sandbox connected to any API replica with its capacity, load, labels, version and the jobs it is running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sandbox"
+                ],
+                "summary": "List connected sandbox instances",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/services.SandboxInstanceInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sandbox/admin/instances/{id}/cordon": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark the sandbox as unschedulable so no new jobs are dispatched to it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sandbox"
+                ],
+                "summary": "Cordon a sandbox instance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sandbox ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sandbox/admin/instances/{id}/drain": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop dispatching new jobs to the sandbox while letting its running jobs finish",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sandbox"
+                ],
+                "summary": "Drain a sandbox instance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sandbox ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sandbox/admin/instances/{id}/shutdown": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a question",
+                "description": "Drain the sandbox and ask it to exit once its running jobs have finished",
                 "consumes": [
                     "application/json"
                 ],
@@ -1945,84 +4336,80 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Question"
+                    "Sandbox"
                 ],
-                "summary": "Update a question",
+                "summary": "Gracefully shut down a sandbox instance",
                 "parameters": [
                     {
-                        "description": "Question object",
-                        "name": "question",
+                        "type": "string",
+                        "description": "Sandbox ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shutdown reason",
+                        "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.PatchQuestionRequest"
+                            "$ref": "#/definitions/handlers.SandboxShutdownRequest"
                         }
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID of the Question to update",
-                        "name": "ID",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handlers.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.Question"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
-                        "description": "Unauthorized"
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "503": {
-                        "description": "Service Unavailable"
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/api/questions/admin/{ID}/question_limit": {
-            "get": {
+        "/api/sandbox/admin/instances/{id}/uncordon": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve only public questions",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Mark the sandbox as schedulable again",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Question"
+                    "Sandbox"
                 ],
-                "summary": "Get a question limitation by ID",
+                "summary": "Uncordon a sandbox instance",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID of the Question to get",
-                        "name": "ID",
+                        "type": "string",
+                        "description": "Sandbox ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2031,53 +4418,56 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handlers.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handlers.GetQuestionResponseData"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "503": {
-                        "description": "Service Unavailable"
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/api/questions/admin/{ID}/scripts": {
-            "get": {
+        "/api/sandbox/admin/instances/{id}/undrain": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the scripts for a question. Including compile script,execute script and score script.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Dispatch new jobs to a drained sandbox again. A sandbox that is shutting down stays drained.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Question"
+                    "Sandbox"
                 ],
-                "summary": "Get the scripts for a question.",
+                "summary": "Undrain a sandbox instance",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID of the Question to get the scripts for",
-                        "name": "ID",
+                        "type": "string",
+                        "description": "Sandbox ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2086,44 +4476,44 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handlers.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handlers.QuestionScripts"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
-                        "description": "Unauthorized"
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "503": {
-                        "description": "Service Unavailable"
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/api/questions/user": {
-            "get": {
+        "/api/sandbox/admin/sandbox_cmd": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of questions by user",
+                "description": "Specify the shell commands and limitation for the corresponding repo",
                 "consumes": [
                     "application/json"
                 ],
@@ -2131,21 +4521,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Question"
+                    "Sandbox"
                 ],
-                "summary": "Get a list of questions by user",
+                "summary": "Specify the shell commands and limitation for the corresponding repo",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "page number of results to return (1-based)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size of results. Default is 10.",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Shell command",
+                        "name": "cmd",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Sandbox"
+                        }
                     }
                 ],
                 "responses": {
@@ -2160,10 +4547,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/handlers.GetUsersQuestionsResponseData"
-                                            }
+                                            "$ref": "#/definitions/models.QuestionTestScript"
                                         }
                                     }
                                 }
@@ -2176,6 +4560,9 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "403": {
+                        "description": "Forbidden"
+                    },
                     "404": {
                         "description": "Not Found"
                     },
@@ -2185,29 +4572,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/questions/user/{ID}/question": {
+        "/api/sandbox/jobs/{UQT_ID}/artifacts": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a specific question associated with a user by its Question ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download the archived raw output of a submission (compile logs, execute stdout/stderr, score script output, gtest JSON and isolate meta) as a tar.gz bundle. Requires the view artifacts permission in the question's course.",
                 "produces": [
-                    "application/json"
+                    "application/gzip"
                 ],
                 "tags": [
-                    "Question"
+                    "Sandbox"
                 ],
-                "summary": "Get a user's question by Question ID",
+                "summary": "Download the artifacts of a judge run",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID of the Question to get",
-                        "name": "ID",
+                        "description": "User question table ID",
+                        "name": "UQT_ID",
                         "in": "path",
                         "required": true
                     }
@@ -2216,54 +4600,56 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handlers.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handlers.GetUserQuestionResponseData"
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "file"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
-                        "description": "Unauthorized"
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "503": {
-                        "description": "Service Unavailable"
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sandbox/jobs/{UQT_ID}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                }
-            }
-        },
-        "/api/questions/{ID}/question": {
-            "get": {
-                "description": "Retrieve only public questions",
-                "consumes": [
-                    "application/json"
                 ],
+                "description": "Cancel a submission that is waiting or being judged. Staff of the question's course can cancel any of its jobs, students only their own. A running job is killed on its sandbox and recorded as CANCELLED.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Question"
+                    "Sandbox"
                 ],
-                "summary": "Get a question by ID",
+                "summary": "Cancel a judge job",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID of the Question to get",
-                        "name": "ID",
+                        "description": "User question table ID",
+                        "name": "UQT_ID",
                         "in": "path",
                         "required": true
                     }
@@ -2272,89 +4658,103 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handlers.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handlers.GetQuestionResponseData"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "503": {
-                        "description": "Service Unavailable"
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/api/sandbox/admin/sandbox_cmd": {
-            "post": {
+        "/api/sandbox/repo": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Specify the shell commands and limitation for the corresponding repo",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Clone a repository at a specific commit on the API server and stream it as a tar.gz archive. Only registered sandboxes holding the SANDBOX_TOKEN can call this endpoint.",
                 "produces": [
-                    "application/json"
+                    "application/gzip"
                 ],
                 "tags": [
                     "Sandbox"
                 ],
-                "summary": "Specify the shell commands and limitation for the corresponding repo",
+                "summary": "Download a repository snapshot for a sandbox",
                 "parameters": [
                     {
-                        "description": "Shell command",
-                        "name": "cmd",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.Sandbox"
-                        }
+                        "type": "string",
+                        "description": "Repository full name (owner/repo)",
+                        "name": "repo",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Commit hash, empty for HEAD",
+                        "name": "commit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sandbox ID",
+                        "name": "X-Sandbox-ID",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handlers.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.QuestionTestScript"
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "file"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
-                        "description": "Unauthorized"
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     },
                     "503": {
-                        "description": "Service Unavailable"
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
@@ -2404,7 +4804,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a score by repo",
+                "description": "Get a score by repo. Test suites with summary visibility omit failure messages and hidden suites are omitted until the question stops accepting submissions, including late and extended exam submissions, unless the caller can view scores in the course.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2477,6 +4877,79 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/score/admin/uqt/{UQT_ID}/rejudge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Judge the commit of a submission again in place. The submission keeps its ID, submission time and lateness, so scoring policies, ICPC attempts and frozen leaderboards see it where it was. Pushes of a commit that was already submitted are not judged again, so this is how a commit is forced through another judge.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Score"
+                ],
+                "summary": "Re-judge a submission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User question table ID",
+                        "name": "UQT_ID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserQuestionTable"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
         "/api/score/admin/{question_id}/question/rescore": {
             "post": {
                 "security": [
@@ -2517,6 +4990,9 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "403": {
+                        "description": "Forbidden"
+                    },
                     "404": {
                         "description": "Not Found"
                     },
@@ -2599,7 +5075,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the leaderboard (Optional Authentication if Admin will show all users, otherwise only public users)",
+                "description": "Get the leaderboard (Optional Authentication if Admin will show all users, otherwise only public users). Without course_id only questions outside any course are ranked; with course_id the course's questions are ranked among its students.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2622,6 +5098,12 @@ const docTemplate = `{
                         "description": "page size of results. Default is 10.",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "rank the questions and students of this course",
+                        "name": "course_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2731,7 +5213,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a score by UQR ID",
+                "description": "Get a score by UQR ID. Test suites with summary visibility omit failure messages and hidden suites are omitted until the question stops accepting submissions, including late and extended exam submissions, unless the caller can view scores in the course.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2797,24 +5279,94 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/score/{question_id}/question": {
+        "/api/score/{question_id}/question": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a score by question ID. Test suites with summary visibility omit failure messages and hidden suites are omitted until the question stops accepting submissions, including late and extended exam submissions, unless the caller can view scores in the course.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Score"
+                ],
+                "summary": "Get a score by question ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number of results to return (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size of results. Default is 10.",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handlers.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.Score"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "503": {
+                        "description": "Service Unavailable"
+                    }
+                }
+            }
+        },
+        "/api/score/{question_id}/question/skipped": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a score by question ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the user's pushes of a question that were received but not judged, newest first, with the reason they were skipped",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Score"
+                    "Gitea"
                 ],
-                "summary": "Get a score by question ID",
+                "summary": "List skipped pushes",
                 "parameters": [
                     {
                         "type": "integer",
@@ -2848,24 +5400,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handlers.Score"
+                                            "$ref": "#/definitions/handlers.GetSkippedPushesResponseData"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
                         "description": "Unauthorized"
                     },
-                    "404": {
-                        "description": "Not Found"
-                    },
                     "503": {
-                        "description": "Service Unavailable"
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResponseHTTP"
+                        }
                     }
                 }
             }
@@ -2910,9 +5459,15 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "403": {
+                        "description": "Forbidden"
+                    },
                     "404": {
                         "description": "Not Found"
                     },
+                    "410": {
+                        "description": "Gone"
+                    },
                     "503": {
                         "description": "Service Unavailable"
                     }
@@ -3246,97 +5801,6 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "gitea.Commit": {
-            "type": "object",
-            "properties": {
-                "author": {
-                    "$ref": "#/definitions/gitea.User"
-                },
-                "commit": {
-                    "$ref": "#/definitions/gitea.RepoCommit"
-                },
-                "committer": {
-                    "$ref": "#/definitions/gitea.User"
-                },
-                "created": {
-                    "type": "string"
-                },
-                "files": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/gitea.CommitAffectedFiles"
-                    }
-                },
-                "html_url": {
-                    "type": "string"
-                },
-                "parents": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/gitea.CommitMeta"
-                    }
-                },
-                "sha": {
-                    "type": "string"
-                },
-                "stats": {
-                    "$ref": "#/definitions/gitea.CommitStats"
-                },
-                "url": {
-                    "type": "string"
-                }
-            }
-        },
-        "gitea.CommitAffectedFiles": {
-            "type": "object",
-            "properties": {
-                "filename": {
-                    "type": "string"
-                }
-            }
-        },
-        "gitea.CommitMeta": {
-            "type": "object",
-            "properties": {
-                "created": {
-                    "type": "string"
-                },
-                "sha": {
-                    "type": "string"
-                },
-                "url": {
-                    "type": "string"
-                }
-            }
-        },
-        "gitea.CommitStats": {
-            "type": "object",
-            "properties": {
-                "additions": {
-                    "type": "integer"
-                },
-                "deletions": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "gitea.CommitUser": {
-            "type": "object",
-            "properties": {
-                "date": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
         "gitea.ExternalTracker": {
             "type": "object",
             "properties": {
@@ -3395,6 +5859,51 @@ const docTemplate = `{
                 "MergeStyleSquash"
             ]
         },
+        "gitea.PayloadCommit": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "author": {
+                    "$ref": "#/definitions/gitea.PayloadUser"
+                },
+                "committer": {
+                    "$ref": "#/definitions/gitea.PayloadUser"
+                },
+                "id": {
+                    "description": "sha1 hash of the commit",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "modified": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "verification": {
+                    "$ref": "#/definitions/gitea.PayloadCommitVerification"
+                }
+            }
+        },
         "gitea.PayloadCommitVerification": {
             "type": "object",
             "properties": {
@@ -3412,6 +5921,21 @@ const docTemplate = `{
                 }
             }
         },
+        "gitea.PayloadUser": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Full name of the commit author",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "gitea.Permission": {
             "type": "object",
             "properties": {
@@ -3471,29 +5995,6 @@ const docTemplate = `{
                 }
             }
         },
-        "gitea.RepoCommit": {
-            "type": "object",
-            "properties": {
-                "author": {
-                    "$ref": "#/definitions/gitea.CommitUser"
-                },
-                "committer": {
-                    "$ref": "#/definitions/gitea.CommitUser"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "tree": {
-                    "$ref": "#/definitions/gitea.CommitMeta"
-                },
-                "url": {
-                    "type": "string"
-                },
-                "verification": {
-                    "$ref": "#/definitions/gitea.PayloadCommitVerification"
-                }
-            }
-        },
         "gitea.Repository": {
             "type": "object",
             "properties": {
@@ -3758,6 +6259,74 @@ const docTemplate = `{
                 "VisibleTypePrivate"
             ]
         },
+        "handlers.AddCourseMembersRequest": {
+            "type": "object",
+            "required": [
+                "user_names"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "instructor",
+                        "ta",
+                        "student"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    ],
+                    "example": "student"
+                },
+                "user_names": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "student1",
+                        "student2"
+                    ]
+                }
+            }
+        },
+        "handlers.AddCourseMembersResponse": {
+            "type": "object",
+            "properties": {
+                "enrolled": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "not_found": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.AddExamParticipantsRequest": {
+            "type": "object",
+            "required": [
+                "user_names"
+            ],
+            "properties": {
+                "user_names": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "student1",
+                        "student2"
+                    ]
+                }
+            }
+        },
         "handlers.AddQuestionRequest": {
             "type": "object",
             "required": [
@@ -3770,6 +6339,11 @@ const docTemplate = `{
                     "type": "string",
                     "example": "script example"
                 },
+                "course_id": {
+                    "description": "omit for a question open to every user",
+                    "type": "integer",
+                    "example": 1
+                },
                 "description": {
                     "type": "string",
                     "example": "Question Description"
@@ -3794,6 +6368,14 @@ const docTemplate = `{
                     "type": "boolean",
                     "example": true
                 },
+                "late_policy": {
+                    "description": "Omit to reject pushes after end_time",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LatePolicy"
+                        }
+                    ]
+                },
                 "memory": {
                     "type": "integer",
                     "example": 262144
@@ -3802,6 +6384,17 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 64
                 },
+                "path_filters": {
+                    "description": "Only judge pushes that change files under these paths; omit to judge any change",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "src",
+                        "include/*.h"
+                    ]
+                },
                 "processes": {
                     "type": "integer",
                     "example": 10
@@ -3814,6 +6407,21 @@ const docTemplate = `{
                     "type": "string",
                     "example": "script example"
                 },
+                "scoring_policy": {
+                    "description": "Which submission counts; omit to follow the exam's policy, or best",
+                    "type": "string",
+                    "enum": [
+                        "best",
+                        "last",
+                        "last_before_deadline",
+                        "top_k_average"
+                    ],
+                    "example": "best"
+                },
+                "scoring_top_k": {
+                    "type": "integer",
+                    "example": 3
+                },
                 "stack_memory": {
                     "type": "integer",
                     "example": 8192
@@ -3830,6 +6438,17 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Question Title"
                 },
+                "trigger_branches": {
+                    "description": "Branch name patterns whose pushes are judged; omit for main and master",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "main",
+                        "release/*"
+                    ]
+                },
                 "wall_time": {
                     "type": "integer",
                     "example": 3000
@@ -3844,6 +6463,10 @@ const docTemplate = `{
                 "title"
             ],
             "properties": {
+                "course_id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "description": {
                     "type": "string",
                     "example": "Question Description"
@@ -3864,6 +6487,27 @@ const docTemplate = `{
                     "type": "boolean",
                     "example": true
                 },
+                "late_policy": {
+                    "$ref": "#/definitions/models.LatePolicy"
+                },
+                "path_filters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "src",
+                        "include/*.h"
+                    ]
+                },
+                "scoring_policy": {
+                    "type": "string",
+                    "example": "best"
+                },
+                "scoring_top_k": {
+                    "type": "integer",
+                    "example": 3
+                },
                 "start_time": {
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z"
@@ -3871,6 +6515,16 @@ const docTemplate = `{
                 "title": {
                     "type": "string",
                     "example": "Question Title"
+                },
+                "trigger_branches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "main",
+                        "release/*"
+                    ]
                 }
             }
         },
@@ -3987,6 +6641,80 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.CourseListData": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "caller's role in the course, empty if not enrolled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    ],
+                    "example": "student"
+                }
+            }
+        },
+        "handlers.CourseMemberData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    ],
+                    "example": "student"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CourseRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "CS101-2025F"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Introduction to Programming"
+                }
+            }
+        },
         "handlers.CreatePublicKey": {
             "type": "object",
             "required": [
@@ -4027,6 +6755,23 @@ const docTemplate = `{
                 "count": {
                     "type": "integer"
                 },
+                "freeze_time": {
+                    "description": "set while frozen",
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "frozen": {
+                    "description": "scores are as of freeze_time",
+                    "type": "boolean"
+                },
+                "ranking_mode": {
+                    "type": "string",
+                    "enum": [
+                        "score",
+                        "icpc"
+                    ],
+                    "example": "score"
+                },
                 "scores": {
                     "type": "array",
                     "items": {
@@ -4038,12 +6783,21 @@ const docTemplate = `{
         "handlers.EnhancedLeaderboardScore": {
             "type": "object",
             "properties": {
+                "penalty_minutes": {
+                    "type": "integer",
+                    "example": 187
+                },
                 "question_scores": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/handlers.EnhancedQuestionScore"
                     }
                 },
+                "solved": {
+                    "description": "ICPC mode only",
+                    "type": "integer",
+                    "example": 4
+                },
                 "total_score": {
                     "type": "number"
                 },
@@ -4055,6 +6809,11 @@ const docTemplate = `{
         "handlers.EnhancedQuestionScore": {
             "type": "object",
             "properties": {
+                "attempts": {
+                    "description": "ICPC mode only",
+                    "type": "integer",
+                    "example": 3
+                },
                 "git_user_repo_url": {
                     "type": "string"
                 },
@@ -4067,6 +6826,15 @@ const docTemplate = `{
                 "score": {
                     "type": "number"
                 },
+                "solve_minutes": {
+                    "description": "minutes from the user's start of the exam",
+                    "type": "integer",
+                    "example": 42
+                },
+                "solved_at": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z"
+                },
                 "weighted_score": {
                     "type": "number"
                 }
@@ -4075,6 +6843,10 @@ const docTemplate = `{
         "handlers.ExamInfoResponse": {
             "type": "object",
             "properties": {
+                "duration_minutes": {
+                    "type": "integer",
+                    "example": 90
+                },
                 "exam_description": {
                     "type": "string"
                 },
@@ -4088,6 +6860,19 @@ const docTemplate = `{
                 },
                 "exam_title": {
                     "type": "string"
+                },
+                "require_access_code": {
+                    "description": "Whether the user must start a session with an access code (POST /api/exams/{id}/session) before viewing questions",
+                    "type": "boolean"
+                },
+                "user_end_time": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "user_start_time": {
+                    "description": "The caller's own window, after their overrides and, on timed exams, the time left since they first opened it",
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z"
                 }
             }
         },
@@ -4097,6 +6882,10 @@ const docTemplate = `{
                 "title"
             ],
             "properties": {
+                "course_id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "description": {
                     "type": "string"
                 },
@@ -4116,6 +6905,95 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.ExamOverrideData": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "description": "replaces the exam's end time when set",
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z07:00"
+                },
+                "exam_id": {
+                    "type": "integer"
+                },
+                "extra_minutes": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "extra_percent": {
+                    "description": "extra time as a percentage of the exam's length",
+                    "type": "integer",
+                    "example": 50
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "replaces the exam's start time when set",
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z07:00"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExamOverrideRequest": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "extra_minutes": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "extra_percent": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0,
+                    "example": 50
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Accommodation"
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z"
+                }
+            }
+        },
+        "handlers.ExamParticipantData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "description": "nil until the participant starts an exam session",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
         "handlers.ExamQuestionData": {
             "type": "object",
             "properties": {
@@ -4153,13 +7031,61 @@ const docTemplate = `{
                 "title"
             ],
             "properties": {
+                "course_id": {
+                    "description": "omit for an exam open to every user",
+                    "type": "integer",
+                    "example": 1
+                },
                 "description": {
                     "type": "string"
                 },
+                "duration_minutes": {
+                    "description": "Minutes each user gets from first opening the exam; omit or 0 for a fixed window",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 90
+                },
                 "end_time": {
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z"
                 },
+                "late_policy": {
+                    "description": "Omit to reject submissions after a user's exam window",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LatePolicy"
+                        }
+                    ]
+                },
+                "penalty_minutes": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 20
+                },
+                "ranking_mode": {
+                    "description": "Leaderboard ranking; omit for score. penalty_minutes defaults to 20.",
+                    "type": "string",
+                    "enum": [
+                        "score",
+                        "icpc"
+                    ],
+                    "example": "icpc"
+                },
+                "scoring_policy": {
+                    "description": "Which submission counts for questions without their own policy; omit for best",
+                    "type": "string",
+                    "enum": [
+                        "best",
+                        "last",
+                        "last_before_deadline",
+                        "top_k_average"
+                    ],
+                    "example": "last"
+                },
+                "scoring_top_k": {
+                    "type": "integer",
+                    "example": 3
+                },
                 "start_time": {
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z"
@@ -4181,6 +7107,32 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.FreezeExamRequest": {
+            "type": "object",
+            "required": [
+                "freeze_time"
+            ],
+            "properties": {
+                "freeze_time": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z"
+                }
+            }
+        },
+        "handlers.GenerateExamAccessCodesRequest": {
+            "type": "object",
+            "required": [
+                "count"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 30
+                }
+            }
+        },
         "handlers.GetAllUserInfoResponse": {
             "type": "object",
             "properties": {
@@ -4213,6 +7165,24 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.GetPracticeRunsResponseData": {
+            "type": "object",
+            "required": [
+                "runs",
+                "runs_count"
+            ],
+            "properties": {
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PracticeRun"
+                    }
+                },
+                "runs_count": {
+                    "type": "integer"
+                }
+            }
+        },
         "handlers.GetQuestionListResponseData": {
             "type": "object",
             "required": [
@@ -4276,6 +7246,24 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.GetSkippedPushesResponseData": {
+            "type": "object",
+            "required": [
+                "pushes",
+                "pushes_count"
+            ],
+            "properties": {
+                "pushes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SkippedPush"
+                    }
+                },
+                "pushes_count": {
+                    "type": "integer"
+                }
+            }
+        },
         "handlers.GetTopExamScoreResponseData": {
             "type": "object",
             "required": [
@@ -4333,6 +7321,20 @@ const docTemplate = `{
                 "is_public": {
                     "type": "boolean"
                 },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Permission"
+                    }
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    ],
+                    "example": "student"
+                },
                 "user_name": {
                     "type": "string"
                 }
@@ -4458,6 +7460,11 @@ const docTemplate = `{
                     "type": "string",
                     "example": "script example"
                 },
+                "course_id": {
+                    "description": "0 moves the question out of its course",
+                    "type": "integer",
+                    "example": 1
+                },
                 "description": {
                     "type": "string",
                     "example": "Question Description"
@@ -4482,6 +7489,14 @@ const docTemplate = `{
                     "type": "boolean",
                     "example": true
                 },
+                "late_policy": {
+                    "description": "Omit to leave unchanged; an empty object removes the policy",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LatePolicy"
+                        }
+                    ]
+                },
                 "memory": {
                     "type": "integer",
                     "example": 262144
@@ -4490,6 +7505,17 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 64
                 },
+                "path_filters": {
+                    "description": "Omit to leave unchanged; an empty list judges any change",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "src",
+                        "include/*.h"
+                    ]
+                },
                 "processes": {
                     "type": "integer",
                     "example": 10
@@ -4502,6 +7528,15 @@ const docTemplate = `{
                     "type": "string",
                     "example": "script example"
                 },
+                "scoring_policy": {
+                    "description": "Omit to leave unchanged; an empty string follows the exam's policy, or best",
+                    "type": "string",
+                    "example": "last"
+                },
+                "scoring_top_k": {
+                    "type": "integer",
+                    "example": 3
+                },
                 "stack_memory": {
                     "type": "integer",
                     "example": 8192
@@ -4518,12 +7553,34 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Question Title"
                 },
+                "trigger_branches": {
+                    "description": "Omit to leave unchanged; an empty list restores main and master",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "main",
+                        "release/*"
+                    ]
+                },
                 "wall_time": {
                     "type": "integer",
                     "example": 3000
                 }
             }
         },
+        "handlers.PracticeRunRequest": {
+            "type": "object",
+            "properties": {
+                "commit": {
+                    "description": "HEAD of the default branch if empty",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "3f786850e387550fdab836ed7e6dc881de23001b"
+                }
+            }
+        },
         "handlers.QuestionScore": {
             "type": "object",
             "required": [
@@ -4630,7 +7687,16 @@ const docTemplate = `{
                 },
                 "source_git_url": {
                     "type": "string",
-                    "example": "user_name/repo_name"
+                    "example": "user_name/repo_name"
+                }
+            }
+        },
+        "handlers.SandboxShutdownRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "node maintenance"
                 }
             }
         },
@@ -4646,13 +7712,32 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z07:00"
                 },
+                "late_penalty": {
+                    "description": "percentage deducted for a late submission",
+                    "type": "number",
+                    "example": 10
+                },
                 "message": {
                     "type": "string",
                     "example": "Scored successfully"
                 },
-                "score": {
+                "raw_score": {
+                    "description": "before the late penalty",
                     "type": "number",
                     "example": 100
+                },
+                "score": {
+                    "type": "number",
+                    "example": 90
+                }
+            }
+        },
+        "handlers.StartExamSessionRequest": {
+            "type": "object",
+            "properties": {
+                "access_code": {
+                    "type": "string",
+                    "example": "K7M2QX9P"
                 }
             }
         },
@@ -4744,6 +7829,23 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.UpdateExamAccessRequest": {
+            "type": "object",
+            "properties": {
+                "allowed_cidrs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "140.124.0.0/16"
+                    ]
+                },
+                "require_access_code": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.UpdateExamRequest": {
             "type": "object",
             "required": [
@@ -4753,10 +7855,47 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "duration_minutes": {
+                    "description": "Minutes each user gets from first opening the exam, 0 for a fixed window; omit to leave unchanged",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 90
+                },
                 "end_time": {
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z"
                 },
+                "late_policy": {
+                    "description": "Omit to leave unchanged; an empty object removes the policy",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LatePolicy"
+                        }
+                    ]
+                },
+                "penalty_minutes": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 20
+                },
+                "ranking_mode": {
+                    "description": "Omit to leave unchanged",
+                    "type": "string",
+                    "enum": [
+                        "score",
+                        "icpc"
+                    ],
+                    "example": "icpc"
+                },
+                "scoring_policy": {
+                    "description": "Omit to leave unchanged; an empty string means best",
+                    "type": "string",
+                    "example": "last"
+                },
+                "scoring_top_k": {
+                    "type": "integer",
+                    "example": 3
+                },
                 "start_time": {
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z"
@@ -4777,6 +7916,27 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.UpdateUserRoleDTO": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "instructor",
+                        "ta",
+                        "student"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    ],
+                    "example": "ta"
+                }
+            }
+        },
         "handlers.WebhookPayload": {
             "type": "object",
             "properties": {
@@ -4789,7 +7949,7 @@ const docTemplate = `{
                 "commits": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/gitea.Commit"
+                        "$ref": "#/definitions/gitea.PayloadCommit"
                     }
                 },
                 "compare_url": {
@@ -4806,113 +7966,489 @@ const docTemplate = `{
                 },
                 "sender": {
                     "$ref": "#/definitions/gitea.User"
+                },
+                "total_commits": {
+                    "description": "Gitea truncates commits in large pushes",
+                    "type": "integer"
                 }
             }
         },
         "handlers._GetQuestionListQuestionData": {
             "type": "object",
             "properties": {
+                "course_id": {
+                    "description": "nil for questions open to every user",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z07:00"
+                },
+                "git_repo_url": {
+                    "type": "string"
+                },
+                "has_question": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "late_policy": {
+                    "description": "LatePolicy accepts pushes after EndTime with a penalty; nil rejects them. For exam questions\nit takes precedence over the exam's policy.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LatePolicy"
+                        }
+                    ]
+                },
+                "path_filters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "src",
+                        "include/*.h"
+                    ]
+                },
+                "scoring_policy": {
+                    "description": "ScoringPolicy picks the submission that counts; empty falls back to the exam's policy, then best.\nScoringTopK is the K of top_k_average.",
+                    "type": "string",
+                    "enum": [
+                        "best",
+                        "last",
+                        "last_before_deadline",
+                        "top_k_average"
+                    ],
+                    "example": "best"
+                },
+                "scoring_top_k": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z07:00"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "top_score": {
+                    "type": "number"
+                },
+                "trigger_branches": {
+                    "description": "TriggerBranches are the branch name patterns whose pushes are judged; empty means main and master.\nPathFilters limit judging to pushes that change files under these paths; empty means any change.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "main",
+                        "release/*"
+                    ]
+                }
+            }
+        },
+        "handlers._GetUsersQuestionsResponseData": {
+            "type": "object",
+            "required": [
+                "description",
+                "git_repo_url",
+                "parent_git_repo_url",
+                "q_id",
+                "title",
+                "uqr_id"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "git_repo_url": {
+                    "type": "string"
+                },
+                "parent_git_repo_url": {
+                    "type": "string"
+                },
+                "q_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "uqr_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.point": {
+            "type": "object",
+            "properties": {
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Course": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Exam": {
+            "type": "object",
+            "properties": {
+                "allowed_cidrs": {
+                    "description": "Access restrictions for proctored exams; see ExamParticipant, ExamAccessCode and ExamSession",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "140.124.0.0/16"
+                    ]
+                },
+                "course_id": {
+                    "description": "nil for exams open to every user",
+                    "type": "integer"
+                },
                 "description": {
                     "type": "string"
                 },
+                "duration_minutes": {
+                    "description": "DurationMinutes makes the exam timed: each user gets this many minutes from first opening it,\nwithin StartTime and EndTime. Zero means everyone shares the StartTime to EndTime window.",
+                    "type": "integer",
+                    "example": 90
+                },
+                "end_time": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z07:00"
+                },
+                "freeze_time": {
+                    "description": "FreezeTime freezes the public leaderboard: from then on it only counts submissions made before\nit, until the exam is unfrozen. Judging and staff views are not affected.",
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z07:00"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "late_policy": {
+                    "description": "LatePolicy accepts submissions after the user's exam window with a penalty; nil rejects them",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LatePolicy"
+                        }
+                    ]
+                },
+                "owner": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "penalty_minutes": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "ranking_mode": {
+                    "description": "RankingMode is how the leaderboard ranks users; PenaltyMinutes is the ICPC penalty per rejected\nattempt before a problem is solved",
+                    "type": "string",
+                    "enum": [
+                        "score",
+                        "icpc"
+                    ],
+                    "example": "score"
+                },
+                "require_access_code": {
+                    "type": "boolean"
+                },
+                "scoring_policy": {
+                    "description": "ScoringPolicy applies to the exam's questions without their own; empty means best",
+                    "type": "string",
+                    "enum": [
+                        "best",
+                        "last",
+                        "last_before_deadline",
+                        "top_k_average"
+                    ],
+                    "example": "last"
+                },
+                "scoring_top_k": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z07:00"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unfrozen": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ExamAccessCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "exam_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "used_at": {
+                    "type": "string"
+                },
+                "used_by_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExamSession": {
+            "type": "object",
+            "properties": {
+                "access_code_id": {
+                    "type": "integer"
+                },
+                "exam_id": {
+                    "type": "integer"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExamUserOverride": {
+            "type": "object",
+            "properties": {
                 "end_time": {
+                    "description": "replaces the exam's end time when set",
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z07:00"
                 },
-                "git_repo_url": {
-                    "type": "string"
+                "exam_id": {
+                    "type": "integer"
                 },
-                "has_question": {
-                    "type": "boolean"
+                "extra_minutes": {
+                    "type": "integer",
+                    "example": 0
                 },
-                "id": {
-                    "type": "integer"
+                "extra_percent": {
+                    "description": "extra time as a percentage of the exam's length",
+                    "type": "integer",
+                    "example": 50
                 },
-                "is_active": {
-                    "type": "boolean"
+                "reason": {
+                    "type": "string"
                 },
                 "start_time": {
+                    "description": "replaces the exam's start time when set",
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z07:00"
                 },
-                "title": {
+                "updated_at": {
                     "type": "string"
                 },
-                "top_score": {
-                    "type": "number"
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers._GetUsersQuestionsResponseData": {
+        "models.LatePolicy": {
             "type": "object",
-            "required": [
-                "description",
-                "git_repo_url",
-                "parent_git_repo_url",
-                "q_id",
-                "title",
-                "uqr_id"
-            ],
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "git_repo_url": {
-                    "type": "string"
+                "cutoff_minutes": {
+                    "description": "late submissions are rejected after this; 0 means after the grace period",
+                    "type": "integer",
+                    "example": 4320
                 },
-                "parent_git_repo_url": {
-                    "type": "string"
+                "grace_minutes": {
+                    "description": "late by at most this much costs nothing",
+                    "type": "integer",
+                    "example": 10
                 },
-                "q_id": {
-                    "type": "integer"
+                "interval_minutes": {
+                    "description": "60 for per hour, 1440 for per day",
+                    "type": "integer",
+                    "example": 1440
                 },
-                "title": {
-                    "type": "string"
+                "max_penalty_percent": {
+                    "description": "0 means up to 100",
+                    "type": "number",
+                    "example": 50
                 },
-                "uqr_id": {
-                    "type": "integer"
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "linear",
+                        "step"
+                    ],
+                    "example": "linear"
+                },
+                "penalty_percent": {
+                    "description": "deducted per interval after the grace period",
+                    "type": "number",
+                    "example": 10
                 }
             }
         },
-        "handlers.point": {
-            "type": "object",
-            "properties": {
-                "score": {
-                    "type": "integer"
-                }
-            }
+        "models.Permission": {
+            "type": "string",
+            "enum": [
+                "users:view",
+                "users:manage",
+                "users:reset_password",
+                "users:manage_roles",
+                "questions:view_all",
+                "questions:manage",
+                "questions:delete",
+                "exams:view_all",
+                "exams:manage",
+                "exams:manage_all",
+                "scores:view",
+                "scores:rescore",
+                "scores:export",
+                "sandbox:cancel_jobs",
+                "sandbox:artifacts",
+                "sandbox:manage",
+                "courses:manage",
+                "courses:manage_all",
+                "courses:manage_members"
+            ],
+            "x-enum-comments": {
+                "PermCancelJobs": "cancel other users' judge jobs",
+                "PermManageCourses": "create courses and manage the courses they teach",
+                "PermManageExams": "create exams and manage the exams they own",
+                "PermManageMembers": "enroll and remove course members",
+                "PermManageQuestions": "create and update questions",
+                "PermManageRoles": "assign roles",
+                "PermManageUsers": "update users, change emails, bulk create accounts",
+                "PermResetPasswords": "reset other users' passwords",
+                "PermViewAllExams": "see unpublished exams and their questions",
+                "PermViewAllQuestions": "see unpublished questions, limits and scripts",
+                "PermViewArtifacts": "download judge artifacts",
+                "PermViewScores": "view other users' scores and full leaderboards",
+                "PermViewUsers": "view user details"
+            },
+            "x-enum-descriptions": [
+                "view user details",
+                "update users, change emails, bulk create accounts",
+                "reset other users' passwords",
+                "assign roles",
+                "see unpublished questions, limits and scripts",
+                "create and update questions",
+                "see unpublished exams and their questions",
+                "create exams and manage the exams they own",
+                "view other users' scores and full leaderboards",
+                "cancel other users' judge jobs",
+                "download judge artifacts",
+                "create courses and manage the courses they teach",
+                "enroll and remove course members"
+            ],
+            "x-enum-varnames": [
+                "PermViewUsers",
+                "PermManageUsers",
+                "PermResetPasswords",
+                "PermManageRoles",
+                "PermViewAllQuestions",
+                "PermManageQuestions",
+                "PermDeleteQuestions",
+                "PermViewAllExams",
+                "PermManageExams",
+                "PermManageAllExams",
+                "PermViewScores",
+                "PermRescore",
+                "PermExportScores",
+                "PermCancelJobs",
+                "PermViewArtifacts",
+                "PermManageSandboxes",
+                "PermManageCourses",
+                "PermManageAllCourses",
+                "PermManageMembers"
+            ]
         },
-        "models.Exam": {
+        "models.PracticeRun": {
             "type": "object",
             "properties": {
-                "description": {
+                "commit": {
                     "type": "string"
                 },
-                "end_time": {
-                    "type": "string",
-                    "example": "2006-01-02T15:04:05Z07:00"
-                },
-                "id": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "owner": {
-                    "$ref": "#/definitions/models.User"
+                "delivery_id": {
+                    "description": "X-Gitea-Delivery of the push, empty when started from the API",
+                    "type": "string"
                 },
-                "owner_id": {
+                "id": {
                     "type": "integer"
                 },
-                "start_time": {
+                "judge_time": {
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z07:00"
                 },
-                "title": {
+                "message": {
+                    "type": "string"
+                },
+                "ref": {
+                    "description": "pushed ref, empty when started from the API",
+                    "type": "string"
+                },
+                "request_id": {
                     "type": "string"
+                },
+                "score": {
+                    "description": "over the public suites only",
+                    "type": "number"
+                },
+                "uqr_id": {
+                    "type": "integer"
                 }
             }
         },
         "models.Question": {
             "type": "object",
             "properties": {
+                "course_id": {
+                    "description": "nil for questions open to every user",
+                    "type": "integer"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -4929,12 +8465,56 @@ const docTemplate = `{
                 "is_active": {
                     "type": "boolean"
                 },
+                "late_policy": {
+                    "description": "LatePolicy accepts pushes after EndTime with a penalty; nil rejects them. For exam questions\nit takes precedence over the exam's policy.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LatePolicy"
+                        }
+                    ]
+                },
+                "path_filters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "src",
+                        "include/*.h"
+                    ]
+                },
+                "scoring_policy": {
+                    "description": "ScoringPolicy picks the submission that counts; empty falls back to the exam's policy, then best.\nScoringTopK is the K of top_k_average.",
+                    "type": "string",
+                    "enum": [
+                        "best",
+                        "last",
+                        "last_before_deadline",
+                        "top_k_average"
+                    ],
+                    "example": "best"
+                },
+                "scoring_top_k": {
+                    "type": "integer",
+                    "example": 3
+                },
                 "start_time": {
                     "type": "string",
                     "example": "2006-01-02T15:04:05Z07:00"
                 },
                 "title": {
                     "type": "string"
+                },
+                "trigger_branches": {
+                    "description": "TriggerBranches are the branch name patterns whose pushes are judged; empty means main and master.\nPathFilters limit judging to pushes that change files under these paths; empty means any change.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "main",
+                        "release/*"
+                    ]
                 }
             }
         },
@@ -4985,6 +8565,51 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Role": {
+            "type": "string",
+            "enum": [
+                "admin",
+                "instructor",
+                "ta",
+                "student"
+            ],
+            "x-enum-varnames": [
+                "RoleAdmin",
+                "RoleInstructor",
+                "RoleTA",
+                "RoleStudent"
+            ]
+        },
+        "models.SkippedPush": {
+            "type": "object",
+            "properties": {
+                "commit": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "description": "X-Gitea-Delivery of the push",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "ref": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "uqr_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.User": {
             "type": "object",
             "properties": {
@@ -5018,11 +8643,148 @@ const docTemplate = `{
                 "reset_password": {
                     "type": "boolean"
                 },
+                "role": {
+                    "$ref": "#/definitions/models.Role"
+                },
                 "user_name": {
                     "type": "string"
                 }
             }
         },
+        "models.UserQuestionRelation": {
+            "type": "object",
+            "properties": {
+                "git_user_repo_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "question": {
+                    "$ref": "#/definitions/models.Question"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UserQuestionTable": {
+            "type": "object",
+            "properties": {
+                "commit": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "description": "X-Gitea-Delivery of the push, empty when not from a webhook",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "judge_time": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z07:00"
+                },
+                "late": {
+                    "description": "submitted after the deadline",
+                    "type": "boolean"
+                },
+                "late_penalty": {
+                    "description": "percentage deducted for a late submission",
+                    "type": "number"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "raw_score": {
+                    "description": "before the late penalty",
+                    "type": "number"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "after the late penalty",
+                    "type": "number"
+                },
+                "uqr": {
+                    "$ref": "#/definitions/models.UserQuestionRelation"
+                },
+                "uqr_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "services.SandboxInstanceInfo": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "available_count": {
+                    "type": "integer"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "connected_at": {
+                    "type": "string"
+                },
+                "cordoned": {
+                    "type": "boolean"
+                },
+                "current_jobs": {
+                    "description": "UserQuestionTable IDs being judged",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "current_practice": {
+                    "description": "PracticeRun IDs being judged",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "draining": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "last_seen": {
+                    "type": "string"
+                },
+                "processing_count": {
+                    "type": "integer"
+                },
+                "replica_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                },
+                "waiting_count": {
+                    "type": "integer"
+                }
+            }
+        },
         "utils.ExportQuestionScoreResponse": {
             "type": "object",
             "properties": {
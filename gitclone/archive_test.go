@@ -0,0 +1,70 @@
+package gitclone
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractArchiveMasksMode(t *testing.T) {
+	tests := []struct {
+		name string
+		mode int64
+		want os.FileMode
+	}{
+		{"regular file", 0o644, 0o644},
+		{"executable", 0o755, 0o755},
+		{"setuid", 0o4755, 0o755},
+		{"setgid", 0o2755, 0o755},
+		{"sticky", 0o1755, 0o755},
+		{"world writable", 0o777, 0o755},
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, tt := range tests {
+		content := []byte(tt.name)
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     tt.name,
+			Typeflag: tar.TypeReg,
+			Mode:     tt.mode,
+			Size:     int64(len(content)),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+	if err := ExtractArchive(&buf, dest); err != nil {
+		t.Fatalf("ExtractArchive: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := os.Stat(filepath.Join(dest, tt.name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if special := info.Mode() & (os.ModeSetuid | os.ModeSetgid | os.ModeSticky); special != 0 {
+				t.Errorf("mode %v keeps %v", info.Mode(), special)
+			}
+			// umask 只會再移除位元，所以實際權限不應超出預期
+			if perm := info.Mode().Perm(); perm&^tt.want != 0 {
+				t.Errorf("perm = %v, want within %v", perm, tt.want)
+			}
+		})
+	}
+}
@@ -23,7 +23,7 @@ func CloneRepository(GitFullName, GitRepoURL, GitAfterHash, GitUsername, GitToke
 	utils.Debugf("%s", GitToken)
 
 	// 生成唯一的代碼路徑
-	codePath := fmt.Sprintf("%s/%s", config.Config("REPO_FOLDER"), GitFullName+"/"+uuid.New().String())
+	codePath := newCodePath(GitFullName)
 
 	// 配置 clone 選項
 	cloneOptions := &git.CloneOptions{
@@ -66,8 +66,20 @@ func CloneRepository(GitFullName, GitRepoURL, GitAfterHash, GitUsername, GitToke
 		utils.Debugf("Successfully cloned %s to %s (using HEAD)", GitFullName, codePath)
 	}
 
+	setPermissions(codePath)
+
+	return codePath, nil
+}
+
+// newCodePath 生成唯一的代碼路徑
+func newCodePath(GitFullName string) string {
+	return fmt.Sprintf("%s/%s", config.Config("REPO_FOLDER"), GitFullName+"/"+uuid.New().String())
+}
+
+// setPermissions 設置代碼目錄權限，讓 isolate 內的使用者可以存取
+func setPermissions(codePath string) {
 	// 設置目錄權限為 777 (讀寫執行權限)
-	err = os.Chmod(codePath, 0777)
+	err := os.Chmod(codePath, 0777)
 	if err != nil {
 		utils.Warnf("Warning: failed to set permissions for %s: %v", codePath, err)
 	}
@@ -85,6 +97,4 @@ func CloneRepository(GitFullName, GitRepoURL, GitAfterHash, GitUsername, GitToke
 	if err != nil {
 		utils.Warnf("Warning: failed to set recursive permissions for %s: %v", codePath, err)
 	}
-
-	return codePath, nil
 }
@@ -0,0 +1,18 @@
+package gitclone
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	cloneDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "oj_git_clone_duration_seconds",
+		Help:    "Duration of fetching a repository, by clone mode.",
+		Buckets: []float64{.25, .5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+	}, []string{"mode"})
+	cloneFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "oj_git_clone_failures_total",
+		Help: "Failed repository fetches, by clone mode.",
+	}, []string{"mode"})
+)
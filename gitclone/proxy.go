@@ -0,0 +1,102 @@
+package gitclone
+
+import (
+	"OJ-API/utils"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// proxyConfig 經由 API Server 取得程式碼所需的設定
+type proxyConfig struct {
+	baseURL   string
+	sandboxID string
+	token     string
+	client    *http.Client
+}
+
+var proxy *proxyConfig
+
+// EnableProxy 啟用 clone proxy，之後 FetchRepository 會向 API Server 下載程式碼，
+// 沙箱節點不再需要直接連線到 Gitea
+func EnableProxy(baseURL, sandboxID, token string, skipVerify bool) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if skipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	proxy = &proxyConfig{
+		baseURL:   baseURL,
+		sandboxID: sandboxID,
+		token:     token,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   5 * time.Minute,
+		},
+	}
+	utils.Infof("Git clone proxy enabled via %s", baseURL)
+}
+
+// FetchRepository 取得指定 commit 的程式碼，未啟用 proxy 時直接從 Gitea clone
+func FetchRepository(GitFullName, GitRepoURL, GitAfterHash, GitUsername, GitToken string) (string, error) {
+	mode := "direct"
+	if proxy != nil {
+		mode = "proxy"
+	}
+	start := time.Now()
+
+	var codePath string
+	var err error
+	if proxy == nil {
+		codePath, err = CloneRepository(GitFullName, GitRepoURL, GitAfterHash, GitUsername, GitToken)
+	} else {
+		codePath, err = proxy.fetch(GitFullName, GitAfterHash)
+	}
+
+	cloneDuration.WithLabelValues(mode).Observe(time.Since(start).Seconds())
+	if err != nil {
+		cloneFailures.WithLabelValues(mode).Inc()
+	}
+	return codePath, err
+}
+
+// fetch 從 API Server 下載 tar.gz 並解壓縮到新的代碼路徑
+func (p *proxyConfig) fetch(GitFullName, GitAfterHash string) (string, error) {
+	query := url.Values{}
+	query.Set("repo", GitFullName)
+	query.Set("commit", GitAfterHash)
+
+	req, err := http.NewRequest(http.MethodGet, p.baseURL+"/api/sandbox/repo?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create proxy request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("X-Sandbox-ID", p.sandboxID)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch repository from proxy: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("proxy returned %s for %s", resp.Status, GitFullName)
+	}
+
+	codePath := newCodePath(GitFullName)
+	if err := os.MkdirAll(codePath, 0777); err != nil {
+		return "", fmt.Errorf("failed to create code path: %v", err)
+	}
+	if err := ExtractArchive(resp.Body, codePath); err != nil {
+		os.RemoveAll(codePath)
+		return "", err
+	}
+
+	setPermissions(codePath)
+	utils.Debugf("Successfully fetched %s to %s at commit %s via proxy", GitFullName, codePath, GitAfterHash)
+
+	return codePath, nil
+}
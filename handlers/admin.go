@@ -10,6 +10,7 @@ import (
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"OJ-API/config"
 	"OJ-API/database"
@@ -37,13 +38,6 @@ type ResetUserPasswordDTO struct {
 // @Security BearerAuth
 func ResetUserPassword(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 	db := database.DBConn
 	id := c.Param("id")
 	userID, err := strconv.ParseUint(id, 10, 32)
@@ -136,14 +130,6 @@ func ResetUserPassword(c *gin.Context) {
 // @Router /api/admin/{id}/user [get]
 // @Security BearerAuth
 func GetUserInfo(c *gin.Context) {
-	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 	db := database.DBConn
 	id := c.Param("id")
 	var user models.User
@@ -186,14 +172,6 @@ type GetAllUserInfoResponse struct {
 // @Router /api/admin/user [get]
 // @Security BearerAuth
 func GetAllUserInfo(c *gin.Context) {
-	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 	db := database.DBConn
 	var users []models.User
 	var total int64
@@ -258,13 +236,6 @@ type UpdateUserInfoDTO struct {
 // @Security BearerAuth
 func UpdateUserInfo(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 	db := database.DBConn
 	id := c.Param("id")
 	var user models.User
@@ -336,7 +307,7 @@ func UpdateUserInfo(c *gin.Context) {
 
 // Export Question Score
 // @Summary Export question score
-// @Description Export question score to CSV, XLSX, or JSON
+// @Description Export question score to CSV, XLSX, or JSON. Questions in a course require the export permission in that course and only include its students.
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -352,15 +323,8 @@ func UpdateUserInfo(c *gin.Context) {
 // @Router /api/admin/questions/{id}/export [get]
 // @Security BearerAuth
 func ExportQuestionScore(c *gin.Context) {
-	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 	db := database.DBConn
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
 	id := c.Param("id")
 	format := c.DefaultQuery("format", "json")
 
@@ -381,16 +345,29 @@ func ExportQuestionScore(c *gin.Context) {
 		})
 		return
 	}
+	if !CanInCourse(jwtClaims, question.CourseID, models.PermExportScores) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
-	// Fetch question scores with earliest submit time for highest score
+	// Fetch question scores under the question's scoring policy, with the time of the counted submission
 	var scores []utils.ExportQuestionScoreResponse
-	if err := db.Table("user_question_relations UQR").
-		Select("U.user_name as user_name, UQR.git_user_repo_url as git_user_repo_url, COALESCE(MAX(UQT.score), 0) AS score, MIN(CASE WHEN UQT.score = (SELECT MAX(score) FROM user_question_tables WHERE uqr_id = UQR.id) THEN UQT.created_at END) AS earliest_best_submit_time").
+	counted := countedScores(db, nil, func(q *gorm.DB) *gorm.DB {
+		return q.Where("UQR.question_id = ?", question.ID)
+	})
+	query := db.Table("user_question_relations UQR").
+		Select("U.user_name as user_name, UQR.git_user_repo_url as git_user_repo_url, COALESCE(CS.score, 0) AS score, CS.submitted_at AS earliest_best_submit_time").
 		Where("UQR.question_id = ? AND U.is_admin = false", question.ID).
 		Joins("JOIN users U ON U.id = UQR.user_id").
-		Joins("LEFT JOIN user_question_tables UQT ON UQT.uqr_id = UQR.id").
-		Group("U.user_name, UQR.git_user_repo_url").
-		Find(&scores).Error; err != nil {
+		Joins("LEFT JOIN (?) CS ON CS.uqr_id = UQR.id", counted)
+	if question.CourseID != nil {
+		// Course questions only export the course's students
+		query = query.Joins("JOIN course_enrollments CE ON CE.user_id = U.id AND CE.course_id = ? AND CE.role = ?", *question.CourseID, models.RoleStudent)
+	}
+	if err := query.Find(&scores).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, ResponseHTTP{
 			Success: false,
 			Message: "Failed to fetch question scores",
@@ -466,13 +443,6 @@ type ChangeUserEmailDTO struct {
 // @Security BearerAuth
 func ChangeUserEmail(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 	db := database.DBConn
 	id := c.Param("id")
 	var user models.User
@@ -555,3 +525,76 @@ func ChangeUserEmail(c *gin.Context) {
 		Message: "User email updated successfully",
 	})
 }
+
+type UpdateUserRoleDTO struct {
+	Role models.Role `json:"role" example:"ta" validate:"required" enums:"instructor,ta,student"`
+}
+
+// UpdateUserRole assigns a role to a user
+// @Summary Update user role
+// @Description Assign the instructor, ta or student role to a user. The admin role follows the Gitea site admin flag and cannot be assigned here. The new role takes effect when the user's access token is refreshed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role body UpdateUserRoleDTO true "Role"
+// @Success      200 {object} ResponseHTTP{data=models.User}
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router /api/admin/{id}/user/role [put]
+// @Security BearerAuth
+func UpdateUserRole(c *gin.Context) {
+	db := database.DBConn
+	var updateUserRoleDTO UpdateUserRoleDTO
+	if err := c.ShouldBindJSON(&updateUserRoleDTO); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Invalid request body",
+		})
+		return
+	}
+	if !updateUserRoleDTO.Role.Valid() || updateUserRoleDTO.Role == models.RoleAdmin {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Role must be one of instructor, ta or student",
+		})
+		return
+	}
+
+	var user models.User
+	if err := db.First(&user, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+	if user.IsAdmin {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Gitea site admins are always admins",
+		})
+		return
+	}
+
+	if err := db.Model(&user).Update("role", updateUserRoleDTO.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to update user role",
+		})
+		return
+	}
+
+	// Remove sensitive gitea_token and refresh_token field
+	user.GiteaToken = ""
+	user.RefreshToken = ""
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    user,
+		Message: "User role updated successfully",
+	})
+}
@@ -185,7 +185,8 @@ func AuthBasic(c *gin.Context) {
 		}
 	}
 
-	accessToken, refreshToken, err := utils.GenerateTokens(existingUser.ID, existingUser.UserName, giteaUser.IsAdmin)
+	role := roleAfterLogin(existingUser.Role, giteaUser.IsAdmin)
+	accessToken, refreshToken, err := utils.GenerateTokens(existingUser.ID, existingUser.UserName, role)
 	if err != nil {
 		c.JSON(503, ResponseHTTP{
 			Success: false,
@@ -195,9 +196,10 @@ func AuthBasic(c *gin.Context) {
 	}
 
 	// Store refresh token in database
-	db.Model(&existingUser).Updates(models.User{
-		IsAdmin:      giteaUser.IsAdmin,
-		RefreshToken: refreshToken,
+	db.Model(&existingUser).Updates(map[string]interface{}{
+		"is_admin":      giteaUser.IsAdmin,
+		"role":          role,
+		"refresh_token": refreshToken,
 	})
 
 	// Set both tokens as cookies with proper CORS configuration
@@ -278,7 +280,7 @@ func RefreshToken(c *gin.Context) {
 	}
 
 	// Generate new access token
-	accessToken, err := utils.GenerateAccessToken(user.ID, user.UserName, user.IsAdmin)
+	accessToken, err := utils.GenerateAccessToken(user.ID, user.UserName, roleAfterLogin(user.Role, user.IsAdmin))
 	if err != nil {
 		c.JSON(503, ResponseHTTP{
 			Success: false,
@@ -502,7 +504,8 @@ func OAuthCallback(c *gin.Context) {
 	}
 
 	// Generate JWT tokens
-	accessToken, refreshToken, err := utils.GenerateTokens(existingUser.ID, existingUser.UserName, giteaUser.IsAdmin)
+	role := roleAfterLogin(existingUser.Role, giteaUser.IsAdmin)
+	accessToken, refreshToken, err := utils.GenerateTokens(existingUser.ID, existingUser.UserName, role)
 	if err != nil {
 		c.JSON(500, ResponseHTTP{
 			Success: false,
@@ -511,8 +514,12 @@ func OAuthCallback(c *gin.Context) {
 		return
 	}
 
-	// Update refresh token in database
-	db.Model(&existingUser).Update("refresh_token", refreshToken)
+	// Update role and refresh token in database
+	db.Model(&existingUser).Updates(map[string]interface{}{
+		"is_admin":      giteaUser.IsAdmin,
+		"role":          role,
+		"refresh_token": refreshToken,
+	})
 
 	// Set tokens as cookies with enhanced security
 	setCrossDomainCookie(c, "access_token", accessToken, 15*60)       // 15 minutes
@@ -523,3 +530,15 @@ func OAuthCallback(c *gin.Context) {
 		Data:    giteaUser.IsAdmin,
 	})
 }
+
+// roleAfterLogin keeps the role assigned in OJ, except that Gitea site admins are always admins
+// and users who are no longer site admins in Gitea lose the admin role
+func roleAfterLogin(current models.Role, isSiteAdmin bool) models.Role {
+	if isSiteAdmin {
+		return models.RoleAdmin
+	}
+	if current == models.RoleAdmin || !current.Valid() {
+		return models.RoleStudent
+	}
+	return current
+}
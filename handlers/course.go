@@ -0,0 +1,558 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"OJ-API/database"
+	"OJ-API/models"
+	"OJ-API/utils"
+)
+
+// CourseRole returns the user's role in the course, or false if the user is not enrolled
+func CourseRole(userID, courseID uint) (models.Role, bool) {
+	var enrollment models.CourseEnrollment
+	if err := database.DBConn.Where("course_id = ? AND user_id = ?", courseID, userID).
+		Limit(1).Find(&enrollment).Error; err != nil || enrollment.CourseID == 0 {
+		return "", false
+	}
+	return enrollment.Role, true
+}
+
+// CanInCourse reports whether the user holds the permission on a resource of the course.
+// Resources outside any course use the user's global role; course resources use the role
+// the user is enrolled with, unless the user can manage every course.
+func CanInCourse(jwtClaims *utils.JWTClaims, courseID *uint, p models.Permission) bool {
+	if jwtClaims == nil {
+		return false
+	}
+	if courseID == nil {
+		return jwtClaims.Can(p)
+	}
+	if jwtClaims.Can(models.PermManageAllCourses) {
+		return true
+	}
+	role, ok := CourseRole(jwtClaims.UserID, *courseID)
+	return ok && role.Can(p)
+}
+
+// canViewCourse reports whether the user may see the resources of the course
+func canViewCourse(jwtClaims *utils.JWTClaims, courseID *uint) bool {
+	if courseID == nil || jwtClaims.Can(models.PermManageAllCourses) {
+		return true
+	}
+	if jwtClaims == nil {
+		return false
+	}
+	_, ok := CourseRole(jwtClaims.UserID, *courseID)
+	return ok
+}
+
+// scopeToCourses restricts the query to rows whose course column is empty or one of the user's courses
+func scopeToCourses(query *gorm.DB, jwtClaims *utils.JWTClaims, column string) *gorm.DB {
+	if jwtClaims.Can(models.PermManageAllCourses) {
+		return query
+	}
+	if jwtClaims == nil {
+		return query.Where(column + " IS NULL")
+	}
+	enrolled := database.DBConn.Model(&models.CourseEnrollment{}).
+		Select("course_id").
+		Where("user_id = ?", jwtClaims.UserID)
+	return query.Where("("+column+" IS NULL OR "+column+" IN (?))", enrolled)
+}
+
+// parseCourseID parses an optional course ID, returning nil when the value is empty
+func parseCourseID(value string) (*uint, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	courseID := uint(id)
+	return &courseID, nil
+}
+
+type CourseRequest struct {
+	Code        string `json:"code" binding:"required" example:"CS101-2025F"`
+	Name        string `json:"name" binding:"required" example:"Introduction to Programming"`
+	Description string `json:"description"`
+}
+
+type CourseListData struct {
+	models.Course
+	Role models.Role `json:"role" example:"student"` // caller's role in the course, empty if not enrolled
+}
+
+// CreateCourse creates a course and enrolls the creator as its instructor
+// @Summary      Create a course
+// @Description  Create a course. The creator is enrolled as the course's instructor.
+// @Tags         Course
+// @Accept       json
+// @Produce      json
+// @Param        course body CourseRequest true "Course details"
+// @Success      200 {object} ResponseHTTP{data=models.Course}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/courses/admin [post]
+// @Security BearerAuth
+func CreateCourse(c *gin.Context) {
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	var req CourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Invalid input: " + err.Error(),
+		})
+		return
+	}
+
+	db := database.DBConn
+	var count int64
+	db.Model(&models.Course{}).Where("code = ?", req.Code).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Course with this code already exists",
+		})
+		return
+	}
+
+	course := models.Course{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&course).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.CourseEnrollment{
+			CourseID: course.ID,
+			UserID:   jwtClaims.UserID,
+			Role:     models.RoleInstructor,
+		}).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to create course",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    course,
+	})
+}
+
+// ListCourses lists the courses the user is enrolled in
+// @Summary      List courses
+// @Description  List the courses the user is enrolled in, with the user's role in each. Users who can manage every course see all courses.
+// @Tags         Course
+// @Produce      json
+// @Success      200 {object} ResponseHTTP{data=[]CourseListData}
+// @Failure      401
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/courses [get]
+// @Security BearerAuth
+func ListCourses(c *gin.Context) {
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	db := database.DBConn
+	query := db.Table("courses").
+		Select("courses.*, course_enrollments.role AS role").
+		Joins("LEFT JOIN course_enrollments ON course_enrollments.course_id = courses.id AND course_enrollments.user_id = ?", jwtClaims.UserID)
+	if !jwtClaims.Can(models.PermManageAllCourses) {
+		query = query.Where("course_enrollments.user_id IS NOT NULL")
+	}
+
+	courses := []CourseListData{}
+	if err := query.Order("courses.id DESC").Scan(&courses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to retrieve courses",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    courses,
+	})
+}
+
+// GetCourse retrieves a course by ID
+// @Summary      Get a course
+// @Description  Retrieve a course the user is enrolled in
+// @Tags         Course
+// @Produce      json
+// @Param        course_id path int true "Course ID"
+// @Success      200 {object} ResponseHTTP{data=CourseListData}
+// @Failure      401
+// @Failure      404 {object} ResponseHTTP{}
+// @Router       /api/courses/{course_id}/course [get]
+// @Security BearerAuth
+func GetCourse(c *gin.Context) {
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	var course models.Course
+	if err := database.DBConn.First(&course, c.Param("course_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Course not found",
+		})
+		return
+	}
+
+	role, enrolled := CourseRole(jwtClaims.UserID, course.ID)
+	if !enrolled && !jwtClaims.Can(models.PermManageAllCourses) {
+		// 未選課的使用者視為課程不存在
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Course not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    CourseListData{Course: course, Role: role},
+	})
+}
+
+// UpdateCourse updates a course
+// @Summary      Update a course
+// @Description  Update a course's code, name or description. Empty fields are left unchanged.
+// @Tags         Course
+// @Accept       json
+// @Produce      json
+// @Param        course_id path int true "Course ID"
+// @Param        course body CourseRequest true "Updated course details"
+// @Success      200 {object} ResponseHTTP{data=models.Course}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/courses/admin/{course_id}/course [patch]
+// @Security BearerAuth
+func UpdateCourse(c *gin.Context) {
+	db := database.DBConn
+	var course models.Course
+	if err := db.First(&course, c.Param("course_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Course not found",
+		})
+		return
+	}
+
+	var req struct {
+		Code        string `json:"code"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.Code != "" && req.Code != course.Code {
+		var count int64
+		db.Model(&models.Course{}).Where("code = ?", req.Code).Count(&count)
+		if count > 0 {
+			c.JSON(http.StatusBadRequest, ResponseHTTP{
+				Success: false,
+				Message: "Course with this code already exists",
+			})
+			return
+		}
+		course.Code = req.Code
+	}
+	if req.Name != "" {
+		course.Name = req.Name
+	}
+	if req.Description != "" {
+		course.Description = req.Description
+	}
+
+	if err := db.Save(&course).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to update course",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    course,
+	})
+}
+
+// DeleteCourse deletes a course
+// @Summary      Delete a course
+// @Description  Delete a course and its enrollments. A course that still owns questions or exams cannot be deleted; move or delete them first.
+// @Tags         Course
+// @Produce      json
+// @Param        course_id path int true "Course ID"
+// @Success      200 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      409 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/courses/admin/{course_id}/course [delete]
+// @Security BearerAuth
+func DeleteCourse(c *gin.Context) {
+	db := database.DBConn
+	var course models.Course
+	if err := db.First(&course, c.Param("course_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Course not found",
+		})
+		return
+	}
+
+	// Questions and exams of the course would otherwise become visible to every user
+	inUse := false
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&course, course.ID).Error; err != nil {
+			return err
+		}
+		var questions, exams int64
+		if err := tx.Model(&models.Question{}).Where("course_id = ?", course.ID).Count(&questions).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Exam{}).Where("course_id = ?", course.ID).Count(&exams).Error; err != nil {
+			return err
+		}
+		if questions > 0 || exams > 0 {
+			inUse = true
+			return nil
+		}
+		if err := tx.Where("course_id = ?", course.ID).Delete(&models.CourseEnrollment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&course).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to delete course",
+		})
+		return
+	}
+	if inUse {
+		c.JSON(http.StatusConflict, ResponseHTTP{
+			Success: false,
+			Message: "Course still has questions or exams",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Message: "Course deleted successfully",
+	})
+}
+
+type CourseMemberData struct {
+	UserID    uint        `json:"user_id"`
+	UserName  string      `json:"user_name"`
+	Email     string      `json:"email"`
+	Role      models.Role `json:"role" example:"student"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// ListCourseMembers lists the members of a course
+// @Summary      List course members
+// @Description  List the users enrolled in a course and their course roles
+// @Tags         Course
+// @Produce      json
+// @Param        course_id path int true "Course ID"
+// @Param        role query string false "Only list members with this course role" Enums(instructor, ta, student)
+// @Success      200 {object} ResponseHTTP{data=[]CourseMemberData}
+// @Failure      401
+// @Failure      403
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/courses/{course_id}/members [get]
+// @Security BearerAuth
+func ListCourseMembers(c *gin.Context) {
+	query := database.DBConn.Table("course_enrollments").
+		Select("users.id AS user_id, users.user_name, users.email, course_enrollments.role, course_enrollments.created_at").
+		Joins("JOIN users ON users.id = course_enrollments.user_id").
+		Where("course_enrollments.course_id = ?", c.Param("course_id"))
+	if role := c.Query("role"); role != "" {
+		query = query.Where("course_enrollments.role = ?", role)
+	}
+
+	members := []CourseMemberData{}
+	if err := query.Order("users.user_name").Scan(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to retrieve course members",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    members,
+	})
+}
+
+type AddCourseMembersRequest struct {
+	UserNames []string    `json:"user_names" binding:"required,min=1" example:"student1,student2"`
+	Role      models.Role `json:"role" example:"student" enums:"instructor,ta,student"`
+}
+
+type AddCourseMembersResponse struct {
+	Enrolled []string `json:"enrolled"`
+	NotFound []string `json:"not_found"`
+}
+
+// AddCourseMembers enrolls users in a course
+// @Summary      Add course members
+// @Description  Enroll users in a course with the given course role (student by default). Users already enrolled have their role updated.
+// @Tags         Course
+// @Accept       json
+// @Produce      json
+// @Param        course_id path int true "Course ID"
+// @Param        members body AddCourseMembersRequest true "Users to enroll"
+// @Success      200 {object} ResponseHTTP{data=AddCourseMembersResponse}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/courses/admin/{course_id}/members [post]
+// @Security BearerAuth
+func AddCourseMembers(c *gin.Context) {
+	db := database.DBConn
+	var course models.Course
+	if err := db.First(&course, c.Param("course_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Course not found",
+		})
+		return
+	}
+
+	var req AddCourseMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Invalid input: " + err.Error(),
+		})
+		return
+	}
+	if req.Role == "" {
+		req.Role = models.RoleStudent
+	}
+	if !req.Role.Valid() || req.Role == models.RoleAdmin {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Role must be one of instructor, ta or student",
+		})
+		return
+	}
+
+	var users []models.User
+	if err := db.Where("user_name IN ?", req.UserNames).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to look up users",
+		})
+		return
+	}
+
+	found := make(map[string]bool, len(users))
+	enrollments := make([]models.CourseEnrollment, 0, len(users))
+	response := AddCourseMembersResponse{Enrolled: []string{}, NotFound: []string{}}
+	for _, user := range users {
+		found[user.UserName] = true
+		enrollments = append(enrollments, models.CourseEnrollment{
+			CourseID: course.ID,
+			UserID:   user.ID,
+			Role:     req.Role,
+		})
+		response.Enrolled = append(response.Enrolled, user.UserName)
+	}
+	for _, name := range req.UserNames {
+		if !found[name] {
+			response.NotFound = append(response.NotFound, name)
+		}
+	}
+
+	if len(enrollments) > 0 {
+		if err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "course_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role"}),
+		}).Create(&enrollments).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ResponseHTTP{
+				Success: false,
+				Message: "Failed to enroll users",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// RemoveCourseMember removes a user from a course
+// @Summary      Remove a course member
+// @Description  Remove a user's enrollment from a course
+// @Tags         Course
+// @Produce      json
+// @Param        course_id path int true "Course ID"
+// @Param        user_id path int true "User ID"
+// @Success      200 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/courses/admin/{course_id}/members/{user_id} [delete]
+// @Security BearerAuth
+func RemoveCourseMember(c *gin.Context) {
+	result := database.DBConn.
+		Where("course_id = ? AND user_id = ?", c.Param("course_id"), c.Param("user_id")).
+		Delete(&models.CourseEnrollment{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to remove course member",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Course member not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Message: "Course member removed successfully",
+	})
+}
@@ -18,6 +18,17 @@ type ExamRequest struct {
 	Description string    `json:"description"`
 	StartTime   time.Time `json:"start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
 	EndTime     time.Time `json:"end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	CourseID    *uint     `json:"course_id" example:"1"` // omit for an exam open to every user
+	// Minutes each user gets from first opening the exam; omit or 0 for a fixed window
+	DurationMinutes int `json:"duration_minutes" binding:"min=0" example:"90"`
+	// Omit to reject submissions after a user's exam window
+	LatePolicy *models.LatePolicy `json:"late_policy"`
+	// Which submission counts for questions without their own policy; omit for best
+	ScoringPolicy string `json:"scoring_policy" example:"last" enums:"best,last,last_before_deadline,top_k_average"`
+	ScoringTopK   int    `json:"scoring_top_k" example:"3"`
+	// Leaderboard ranking; omit for score. penalty_minutes defaults to 20.
+	RankingMode    string `json:"ranking_mode" binding:"omitempty,oneof=score icpc" example:"icpc"`
+	PenaltyMinutes *int   `json:"penalty_minutes" binding:"omitempty,min=0" example:"20"`
 }
 
 // CreateExam handles the creation of a new exam
@@ -36,13 +47,6 @@ type ExamRequest struct {
 // @Security BearerAuth
 func CreateExam(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 
 	var exam ExamRequest
 	if err := c.ShouldBindJSON(&exam); err != nil {
@@ -62,13 +66,46 @@ func CreateExam(c *gin.Context) {
 		return
 	}
 
+	latePolicy, err := normalizeLatePolicy(exam.LatePolicy)
+	if err == nil {
+		err = validateScoringPolicy(exam.ScoringPolicy, exam.ScoringTopK)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !CanInCourse(jwtClaims, exam.CourseID, models.PermManageExams) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
+
 	db := database.DBConn
 	newExam := models.Exam{
-		Title:       exam.Title,
-		Description: exam.Description,
-		StartTime:   exam.StartTime,
-		EndTime:     exam.EndTime,
-		OwnerID:     jwtClaims.UserID,
+		Title:           exam.Title,
+		Description:     exam.Description,
+		StartTime:       exam.StartTime,
+		EndTime:         exam.EndTime,
+		OwnerID:         jwtClaims.UserID,
+		CourseID:        exam.CourseID,
+		DurationMinutes: exam.DurationMinutes,
+		LatePolicy:      latePolicy,
+		ScoringPolicy:   exam.ScoringPolicy,
+		ScoringTopK:     exam.ScoringTopK,
+		RankingMode:     models.RankingScore,
+		PenaltyMinutes:  20,
+	}
+	if exam.RankingMode != "" {
+		newExam.RankingMode = exam.RankingMode
+	}
+	if exam.PenaltyMinutes != nil {
+		newExam.PenaltyMinutes = *exam.PenaltyMinutes
 	}
 	if err := db.Create(&newExam).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, ResponseHTTP{
@@ -91,18 +128,12 @@ func CreateExam(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "Exam ID"
 // @Success      200 {object} ResponseHTTP{data=models.Exam}
+// @Failure      403 {object} ResponseHTTP{}
 // @Failure      404 {object} ResponseHTTP{}
 // @Router       /api/exams/admin/{id}/exam [get]
 // @Security BearerAuth
 func GetExam(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 
 	id := c.Param("id")
 	var exam models.Exam
@@ -115,6 +146,13 @@ func GetExam(c *gin.Context) {
 		})
 		return
 	}
+	if !CanInCourse(jwtClaims, exam.CourseID, models.PermViewAllExams) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, ResponseHTTP{
 		Success: true,
@@ -127,6 +165,16 @@ type UpdateExamRequest struct {
 	Description string    `json:"description"`
 	StartTime   time.Time `json:"start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
 	EndTime     time.Time `json:"end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	// Minutes each user gets from first opening the exam, 0 for a fixed window; omit to leave unchanged
+	DurationMinutes *int `json:"duration_minutes" binding:"omitempty,min=0" example:"90"`
+	// Omit to leave unchanged; an empty object removes the policy
+	LatePolicy *models.LatePolicy `json:"late_policy"`
+	// Omit to leave unchanged; an empty string means best
+	ScoringPolicy *string `json:"scoring_policy" example:"last"`
+	ScoringTopK   *int    `json:"scoring_top_k" example:"3"`
+	// Omit to leave unchanged
+	RankingMode    string `json:"ranking_mode" binding:"omitempty,oneof=score icpc" example:"icpc"`
+	PenaltyMinutes *int   `json:"penalty_minutes" binding:"omitempty,min=0" example:"20"`
 }
 
 // UpdateExam updates an existing exam
@@ -139,19 +187,13 @@ type UpdateExamRequest struct {
 // @Param        exam body UpdateExamRequest true "Updated exam details"
 // @Success      200 {object} ResponseHTTP{data=UpdateExamRequest}
 // @Failure      400 {object} ResponseHTTP{}
+// @Failure      403 {object} ResponseHTTP{}
 // @Failure      404 {object} ResponseHTTP{}
 // @Failure      500 {object} ResponseHTTP{}
 // @Router       /api/exams/admin/{id}/exam [put]
 // @Security BearerAuth
 func UpdateExam(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 
 	id := c.Param("id")
 	var exam UpdateExamRequest
@@ -165,6 +207,13 @@ func UpdateExam(c *gin.Context) {
 		})
 		return
 	}
+	if !canManageExam(jwtClaims, existingExam) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
 	if err := c.ShouldBindJSON(&exam); err != nil {
 		c.JSON(http.StatusBadRequest, ResponseHTTP{
@@ -193,6 +242,39 @@ func UpdateExam(c *gin.Context) {
 	if !exam.EndTime.IsZero() {
 		existingExam.EndTime = exam.EndTime
 	}
+	if exam.DurationMinutes != nil {
+		existingExam.DurationMinutes = *exam.DurationMinutes
+	}
+	if exam.LatePolicy != nil {
+		latePolicy, err := normalizeLatePolicy(exam.LatePolicy)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+		existingExam.LatePolicy = latePolicy
+	}
+	if exam.ScoringPolicy != nil {
+		existingExam.ScoringPolicy = *exam.ScoringPolicy
+	}
+	if exam.ScoringTopK != nil {
+		existingExam.ScoringTopK = *exam.ScoringTopK
+	}
+	if exam.RankingMode != "" {
+		existingExam.RankingMode = exam.RankingMode
+	}
+	if exam.PenaltyMinutes != nil {
+		existingExam.PenaltyMinutes = *exam.PenaltyMinutes
+	}
+	if err := validateScoringPolicy(existingExam.ScoringPolicy, existingExam.ScoringTopK); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
 
 	if err := db.Save(&existingExam).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, ResponseHTTP{
@@ -231,19 +313,13 @@ func UpdateExam(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "Exam ID"
 // @Success      200 {object} ResponseHTTP{}
+// @Failure      403 {object} ResponseHTTP{}
 // @Failure      404 {object} ResponseHTTP{}
 // @Failure      500 {object} ResponseHTTP{}
 // @Router       /api/exams/admin/{id}/exam [delete]
 // @Security BearerAuth
 func DeleteExam(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 
 	id := c.Param("id")
 	var exam models.Exam
@@ -256,6 +332,13 @@ func DeleteExam(c *gin.Context) {
 		})
 		return
 	}
+	if !canManageExam(jwtClaims, exam) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
 	if err := db.Delete(&exam).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, ResponseHTTP{
@@ -271,20 +354,33 @@ func DeleteExam(c *gin.Context) {
 	})
 }
 
+// canManageExam reports whether the user may modify the exam: its owner, the
+// staff of its course, or anyone allowed to manage every exam.
+func canManageExam(jwtClaims *utils.JWTClaims, exam models.Exam) bool {
+	if exam.CourseID != nil {
+		return jwtClaims.Can(models.PermManageAllExams) || CanInCourse(jwtClaims, exam.CourseID, models.PermManageExams)
+	}
+	return jwtClaims.Can(models.PermManageAllExams) ||
+		(jwtClaims.Can(models.PermManageExams) && exam.OwnerID == jwtClaims.UserID)
+}
+
 type ExamListData struct {
 	ID          uint      `json:"id"`
 	Title       string    `json:"title" binding:"required"`
 	Description string    `json:"description"`
 	StartTime   time.Time `json:"start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
 	EndTime     time.Time `json:"end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	CourseID    *uint     `json:"course_id" example:"1"`
 }
 
 // ListExams retrieves all exams
 // @Summary      List all exams
-// @Description  Retrieve a list of all exams
+// @Description  Retrieve a list of all exams outside any course or in the user's courses
 // @Tags         Exam
 // @Produce      json
+// @Param        course_id query int false "Only list exams of this course"
 // @Success      200 {object} ResponseHTTP{data=[]ExamListData}
+// @Failure      400 {object} ResponseHTTP{}
 // @Failure      500 {object} ResponseHTTP{}
 // @Router       /api/exams [get]
 // @Security     BearerAuth
@@ -293,17 +389,30 @@ func ListExams(c *gin.Context) {
 
 	db := database.DBConn
 
-	// Check if user is admin
+	// Check if user can view unpublished exams
 	jwtClaims, _ := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	isAdmin := jwtClaims != nil && jwtClaims.IsAdmin
+	viewAll := jwtClaims.Can(models.PermViewAllExams)
 
-	var query *gorm.DB
-	if isAdmin {
-		// Admin can see all exams
-		query = db.Find(&exams)
+	courseID, err := parseCourseID(c.Query("course_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Invalid course ID",
+		})
+		return
+	}
+
+	// Only exams outside any course or in the user's courses are listed
+	query := scopeToCourses(db.Model(&models.Exam{}), jwtClaims, "course_id")
+	if courseID != nil {
+		query = query.Where("course_id = ?", *courseID)
+	}
+	if viewAll {
+		// Staff can see all exams
+		query = query.Find(&exams)
 	} else {
-		// Non-admin users only see ongoing exams
-		query = db.Where("start_time < ? AND end_time > ?", time.Now(), time.Now()).Find(&exams)
+		// Other users only see ongoing exams
+		query = query.Where("start_time < ? AND end_time > ?", time.Now(), time.Now()).Find(&exams)
 	}
 
 	if err := query.Error; err != nil {
@@ -322,6 +431,7 @@ func ListExams(c *gin.Context) {
 			Description: exam.Description,
 			StartTime:   exam.StartTime,
 			EndTime:     exam.EndTime,
+			CourseID:    exam.CourseID,
 		}
 	}
 
@@ -353,6 +463,8 @@ type ExamQuestionData struct {
 // @Param        page query int false "page number of results to return (1-based)"
 // @Param        limit query int false "page size of results. Default is 10."
 // @Success      200 {object} ResponseHTTP{data=ExamQuestionsResponse}
+// @Failure      401 {object} ResponseHTTP{}
+// @Failure      403 {object} ResponseHTTP{}
 // @Failure      404 {object} ResponseHTTP{}
 // @Failure      500 {object} ResponseHTTP{}
 // @Router       /api/exams/{id}/questions [get]
@@ -368,14 +480,29 @@ func GetExamQuestions(c *gin.Context) {
 	offset := (page - 1) * limit
 
 	db := database.DBConn
+	var exam models.Exam
+	if err := db.First(&exam, examID).Error; err != nil || !canViewCourse(jwtClaims, exam.CourseID) {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Exam not found",
+		})
+		return
+	}
+	if status, message := checkExamAccess(jwtClaims, exam, utils.GetClientIP(c)); status != 0 {
+		c.JSON(status, ResponseHTTP{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
 	// Get total count of questions in this exam
 	var totalQuestions int64
 	countQuery := db.Model(&models.ExamQuestion{}).
 		Joins("Question").
 		Where("exam_questions.exam_id = ?", examID)
 
-	// Non-admin users can only see active questions
-	if !isAuthenticated || !jwtClaims.IsAdmin {
+	// Users without view-all permission can only see active questions
+	if !jwtClaims.Can(models.PermViewAllExams) {
 		countQuery = countQuery.Where("\"Question\".is_active = ?", true)
 	}
 
@@ -397,7 +524,7 @@ func GetExamQuestions(c *gin.Context) {
 		Offset(offset).
 		Limit(limit)
 
-	if !isAuthenticated || !jwtClaims.IsAdmin {
+	if !jwtClaims.Can(models.PermViewAllExams) {
 		questionQuery = questionQuery.Where("\"Question\".is_active = ?", true)
 	}
 
@@ -444,20 +571,14 @@ func GetExamQuestions(c *gin.Context) {
 			TopScore   *float64 `json:"top_score"`
 		}
 
-		err := db.Raw(`
-			SELECT 
-				q.id as question_id,
-				CASE 
-					WHEN MAX(uqt.score) IS NULL THEN NULL
-					WHEN MAX(uqt.score) < 0 THEN 0
-					ELSE MAX(uqt.score)
-				END as top_score
-			FROM questions q
-			LEFT JOIN user_question_relations uqr ON q.id = uqr.question_id AND uqr.user_id = ?
-			LEFT JOIN user_question_tables uqt ON uqr.id = uqt.uqr_id
-			WHERE q.id IN ?
-			GROUP BY q.id
-		`, jwtClaims.UserID, questionIDs).Scan(&topScores).Error
+		counted := countedScores(db, &exam, func(q *gorm.DB) *gorm.DB {
+			return q.Where("UQR.user_id = ? AND UQR.question_id IN ?", jwtClaims.UserID, questionIDs)
+		})
+		err := db.Table("questions q").
+			Select("q.id AS question_id, cs.score AS top_score").
+			Joins("LEFT JOIN (?) cs ON cs.question_id = q.id", counted).
+			Where("q.id IN ?", questionIDs).
+			Scan(&topScores).Error
 
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, ResponseHTTP{
@@ -513,6 +634,12 @@ type ExamInfoResponse struct {
 	ExamDescription string    `json:"exam_description"`
 	ExamStartTime   time.Time `json:"exam_start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
 	ExamEndTime     time.Time `json:"exam_end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	// The caller's own window, after their overrides and, on timed exams, the time left since they first opened it
+	UserStartTime   time.Time `json:"user_start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	UserEndTime     time.Time `json:"user_end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	DurationMinutes int       `json:"duration_minutes" example:"90"`
+	// Whether the user must start a session with an access code (POST /api/exams/{id}/session) before viewing questions
+	RequireAccessCode bool `json:"require_access_code"`
 }
 
 // GetExamInfo retrieves basic information about an exam
@@ -541,12 +668,24 @@ func GetExamInfo(c *gin.Context) {
 
 	// Check if user is authenticated (optional)
 	jwtClaims, _ := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	isAdmin := jwtClaims != nil && jwtClaims.IsAdmin
+	if !canViewCourse(jwtClaims, exam.CourseID) {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Exam not found",
+		})
+		return
+	}
+	viewAll := CanInCourse(jwtClaims, exam.CourseID, models.PermViewAllExams)
 
-	// Non-admin users can only view ongoing exams
-	if !isAdmin {
+	// Other users can only view exams within their own window
+	var userID uint
+	if jwtClaims != nil {
+		userID = jwtClaims.UserID
+	}
+	userStart, userEnd := userExamWindow(exam, userID)
+	if !viewAll {
 		now := time.Now()
-		if now.Before(exam.StartTime) || now.After(exam.EndTime) {
+		if now.Before(userStart) || now.After(userEnd) {
 			c.JSON(http.StatusForbidden, ResponseHTTP{
 				Success: false,
 				Message: "Exam is not currently available",
@@ -558,10 +697,14 @@ func GetExamInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, ResponseHTTP{
 		Success: true,
 		Data: ExamInfoResponse{
-			ExamTitle:       exam.Title,
-			ExamDescription: exam.Description,
-			ExamStartTime:   exam.StartTime,
-			ExamEndTime:     exam.EndTime,
+			ExamTitle:         exam.Title,
+			ExamDescription:   exam.Description,
+			ExamStartTime:     exam.StartTime,
+			ExamEndTime:       exam.EndTime,
+			UserStartTime:     userStart,
+			UserEndTime:       userEnd,
+			DurationMinutes:   exam.DurationMinutes,
+			RequireAccessCode: exam.RequireAccessCode,
 		},
 	})
 }
@@ -580,19 +723,13 @@ type point struct {
 // @Param        question_id path string true "Question ID"
 // @Param		 point body point true "Score for the question"
 // @Success      200 {object} ResponseHTTP{}
+// @Failure      403 {object} ResponseHTTP{}
 // @Failure      404 {object} ResponseHTTP{}
 // @Failure      500 {object} ResponseHTTP{}
 // @Router       /api/exams/admin/{id}/questions/{question_id}/question [post]
 // @Security BearerAuth
 func AddQuestionToExam(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 
 	id := c.Param("id")
 	var exam models.Exam
@@ -605,6 +742,13 @@ func AddQuestionToExam(c *gin.Context) {
 		})
 		return
 	}
+	if !canManageExam(jwtClaims, exam) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
 	questionID := c.Param("question_id")
 	var question models.Question
@@ -669,19 +813,13 @@ func AddQuestionToExam(c *gin.Context) {
 // @Param        question_id path string true "Question ID"
 // @Param		 point body point true "Updated score for the question"
 // @Success      200 {object} ResponseHTTP{}
+// @Failure      403 {object} ResponseHTTP{}
 // @Failure      404 {object} ResponseHTTP{}
 // @Failure      500 {object} ResponseHTTP{}
 // @Router       /api/exams/admin/{id}/questions/{question_id}/question [put]
 // @Security BearerAuth
 func UpdateQuestionInExam(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 	id := c.Param("id")
 	var exam models.Exam
 	db := database.DBConn
@@ -692,6 +830,13 @@ func UpdateQuestionInExam(c *gin.Context) {
 		})
 		return
 	}
+	if !canManageExam(jwtClaims, exam) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 	questionID := c.Param("question_id")
 	var question models.Question
 	if err := db.First(&question, questionID).Error; err != nil {
@@ -747,19 +892,13 @@ func UpdateQuestionInExam(c *gin.Context) {
 // @Param        id path string true "Exam ID"
 // @Param        question_id path string true "Question ID"
 // @Success      200 {object} ResponseHTTP{}
+// @Failure      403 {object} ResponseHTTP{}
 // @Failure      404 {object} ResponseHTTP{}
 // @Failure      500 {object} ResponseHTTP{}
 // @Router       /api/exams/admin/{id}/questions/{question_id}/question [delete]
 // @Security BearerAuth
 func RemoveQuestionFromExam(c *gin.Context) {
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 
 	id := c.Param("id")
 	var exam models.Exam
@@ -772,6 +911,13 @@ func RemoveQuestionFromExam(c *gin.Context) {
 		})
 		return
 	}
+	if !canManageExam(jwtClaims, exam) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
 	questionID := c.Param("question_id")
 	var question models.Question
@@ -843,15 +989,24 @@ func GetTopExamScore(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	offset := (page - 1) * limit
 
-	var totalCount int64
+	var exam models.Exam
+	if err := db.First(&exam, id).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Exam not found",
+		})
+		return
+	}
+
+	// The score of each question follows its scoring policy, or the exam's
+	counted := countedScores(db, &exam, func(q *gorm.DB) *gorm.DB {
+		return q.Where("Q.is_active = ?", true).
+			Where("UQR.question_id IN (SELECT question_id FROM exam_questions WHERE exam_id = ?)", exam.ID).
+			Where("UQR.user_id = ?", jwtClaims.UserID)
+	})
 
-	if err := db.Model(&models.UserQuestionTable{}).
-		Select("DISTINCT question_id").
-		Joins("JOIN user_question_relations UQR ON user_question_tables.uqr_id = UQR.id").
-		Joins("JOIN questions Q ON UQR.question_id = Q.id").
-		Where("Q.is_active = ?", true).
-		Where("question_id IN (SELECT question_id FROM exam_questions WHERE exam_id = ?)", id).
-		Where("UQR.user_id = ?", jwtClaims.UserID).
+	var totalCount int64
+	if err := db.Table("(?) AS cs", counted).
 		Count(&totalCount).Error; err != nil {
 		c.JSON(503, ResponseHTTP{
 			Success: false,
@@ -861,15 +1016,11 @@ func GetTopExamScore(c *gin.Context) {
 	}
 
 	var scores []TopExamScore
-	if err := db.Model(&models.UserQuestionTable{}).
-		Joins("JOIN user_question_relations UQR ON user_question_tables.uqr_id = UQR.id").
-		Joins("JOIN exam_questions EQ ON UQR.question_id = EQ.question_id").
-		Joins("JOIN questions Q ON UQR.question_id = Q.id").
-		Select("DISTINCT ON (UQR.question_id) UQR.question_id, git_user_repo_url, score, message, judge_time, EQ.point").
-		Where("Q.is_active = ?", true).
-		Where("UQR.user_id = ?", jwtClaims.UserID).
-		Where("EQ.exam_id = ?", id).
-		Order("UQR.question_id ASC, score DESC, judge_time DESC").
+	if err := db.Table("(?) AS cs", counted).
+		Joins("JOIN exam_questions EQ ON cs.question_id = EQ.question_id AND EQ.exam_id = ?", exam.ID).
+		Joins("LEFT JOIN user_question_tables uqt ON cs.submission_id = uqt.id").
+		Select("cs.question_id, cs.git_user_repo_url, cs.score, COALESCE(uqt.message, '') message, uqt.judge_time, EQ.point").
+		Order("cs.question_id ASC").
 		Offset(offset).
 		Limit(limit).
 		Find(&scores).Error; err != nil {
@@ -911,22 +1062,32 @@ type EnhancedQuestionScore struct {
 	GitUserRepoURL string  `json:"git_user_repo_url"`
 	Score          float64 `json:"score"`
 	WeightedScore  float64 `json:"weighted_score"`
+	// ICPC mode only
+	Attempts     int        `json:"attempts,omitempty" example:"3"` // judged submissions up to and including the first solve
+	SolvedAt     *time.Time `json:"solved_at,omitempty" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	SolveMinutes *int       `json:"solve_minutes,omitempty" example:"42"` // minutes from the user's start of the exam
 }
 
 type EnhancedLeaderboardScore struct {
 	UserName       string                  `json:"user_name"`
 	TotalScore     float64                 `json:"total_score"`
 	QuestionScores []EnhancedQuestionScore `json:"question_scores"`
+	// ICPC mode only
+	Solved         int `json:"solved,omitempty" example:"4"`
+	PenaltyMinutes int `json:"penalty_minutes,omitempty" example:"187"`
 }
 
 type EnhancedGetLeaderboardResponseData struct {
-	Count  int                        `json:"count"`
-	Scores []EnhancedLeaderboardScore `json:"scores"`
+	Count       int                        `json:"count"`
+	RankingMode string                     `json:"ranking_mode" example:"score" enums:"score,icpc"`
+	Scores      []EnhancedLeaderboardScore `json:"scores"`
+	Frozen      bool                       `json:"frozen"`                                                                     // scores are as of freeze_time
+	FreezeTime  *time.Time                 `json:"freeze_time,omitempty" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"` // set while frozen
 }
 
 // GetExamLeaderboard retrieves the leaderboard for an exam
 // @Summary      	Get the leaderboard for an exam
-// @Description  	Retrieve the leaderboard for a specific exam (Optional Authentication if Admin will show all users, otherwise only public users)
+// @Description  	Retrieve the leaderboard for a specific exam (Optional Authentication if Admin will show all users, otherwise only public users). ICPC mode exams rank by problems solved at full score, then penalty minutes, and show per-problem attempts and solve times. Once the exam's freeze time passes, only staff see live results; everyone else sees scores as of the freeze until the exam is unfrozen.
 // @Tags         	Exam
 // @Accept			json
 // @Produce		json
@@ -943,7 +1104,6 @@ type EnhancedGetLeaderboardResponseData struct {
 func GetExamLeaderboard(c *gin.Context) {
 	// Check if user is authenticated (optional)
 	jwtClaims, _ := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	isAuthenticated := jwtClaims != nil
 
 	db := database.DBConn
 
@@ -954,7 +1114,7 @@ func GetExamLeaderboard(c *gin.Context) {
 
 	// Check if exam exists
 	var exam models.Exam
-	if err := db.First(&exam, id).Error; err != nil {
+	if err := db.First(&exam, id).Error; err != nil || !canViewCourse(jwtClaims, exam.CourseID) {
 		c.JSON(404, ResponseHTTP{
 			Success: false,
 			Message: "Exam not found",
@@ -962,31 +1122,34 @@ func GetExamLeaderboard(c *gin.Context) {
 		return
 	}
 
+	// A frozen leaderboard only counts submissions made before the freeze, except for staff
+	freezeTime := leaderboardFreezeTime(exam, time.Now().UTC())
+	if freezeTime != nil && CanInCourse(jwtClaims, exam.CourseID, models.PermViewScores) {
+		freezeTime = nil
+	}
+	frozen := func(q *gorm.DB) *gorm.DB {
+		if freezeTime == nil {
+			return q
+		}
+		return q.Where("uqt.created_at < ?", *freezeTime)
+	}
+
+	if exam.RankingMode == models.RankingICPC {
+		getICPCExamLeaderboard(c, exam, jwtClaims, frozen, freezeTime, offset, limit)
+		return
+	}
+
 	// Get total count of users who have scores for this exam
 	var totalCount int64
-	subquery := db.Raw(`
-	SELECT DISTINCT ON (UQR.user_id, UQR.question_id)
-           UQR.user_id AS user_id,
-           UQR.question_id,
-           uqt.created_at,
-           GREATEST(uqt.score, 0) AS max_score
-    FROM user_question_tables uqt
-    JOIN user_question_relations UQR ON uqt.uqr_id = UQR.id
-    JOIN questions Q ON UQR.question_id = Q.id
-    JOIN users ON users.id = UQR.user_id
-    WHERE Q.is_active = TRUE
-      AND UQR.question_id IN (
-			SELECT eq.question_id FROM exam_questions eq WHERE eq.exam_id = ?
-		)
-      AND users.is_admin = FALSE
-    ORDER BY UQR.user_id, UQR.question_id, uqt.score DESC, uqt.created_at ASC
-	`, id)
-	if err := db.Table("(SELECT DISTINCT user_id FROM user_question_relations "+
-		"JOIN user_question_tables ON user_question_relations.id = user_question_tables.uqr_id "+
-		"JOIN exam_questions ON user_question_relations.question_id = exam_questions.question_id "+
-		"JOIN users ON user_question_relations.user_id = users.id "+
-		"WHERE exam_questions.exam_id = ? AND users.is_admin = false ) AS t", id).
-		Count(&totalCount).Error; err != nil {
+	subquery := countedScores(db, &exam, func(q *gorm.DB) *gorm.DB {
+		return frozen(q).Joins("JOIN users ON users.id = UQR.user_id").
+			Where("Q.is_active = ?", true).
+			Where("UQR.question_id IN (SELECT eq.question_id FROM exam_questions eq WHERE eq.exam_id = ?)", exam.ID).
+			Where("users.is_admin = ?", false)
+	})
+	if err := db.Table("(?) AS t", subquery).
+		Select("COUNT(DISTINCT user_id)").
+		Scan(&totalCount).Error; err != nil {
 		c.JSON(503, ResponseHTTP{
 			Success: false,
 			Message: "Failed to count users with scores",
@@ -1005,9 +1168,9 @@ func GetExamLeaderboard(c *gin.Context) {
 	var usersWithScores []UserWithTotalScore
 	if err := db.Table("(?) AS subquery", subquery).
 		Joins("JOIN users ON users.id = subquery.user_id").
-		Select("users.id AS user_id, users.user_name, users.is_public, SUM(max_score) AS total_score").
+		Select("users.id AS user_id, users.user_name, users.is_public, SUM(subquery.score) AS total_score").
 		Group("users.id, users.user_name, users.is_public").
-		Order("total_score DESC, MAX(subquery.created_at) ASC").
+		Order("total_score DESC, MAX(subquery.submitted_at) ASC").
 		Offset(offset).
 		Limit(limit).
 		Find(&usersWithScores).Error; err != nil {
@@ -1044,19 +1207,16 @@ func GetExamLeaderboard(c *gin.Context) {
 	}
 
 	var questionScores []QuestionScoreDetail
-	subquery2 := db.Model(&models.UserQuestionTable{}).
-		Select("UQR.user_id, UQR.question_id, GREATEST(MAX(user_question_tables.score), 0) AS score, MAX(UQR.git_user_repo_url) AS git_user_repo_url, MAX(EQ.point) AS point").
-		Joins("JOIN user_question_relations UQR ON user_question_tables.uqr_id = UQR.id").
-		Joins("JOIN exam_questions EQ ON UQR.question_id = EQ.question_id").
-		Joins("JOIN questions Q ON UQR.question_id = Q.id").
-		Where("Q.is_active = ?", true).
-		Where("UQR.user_id IN ?", userIDs).
-		Where("EQ.exam_id = ?", id).
-		Group("UQR.user_id, UQR.question_id")
+	subquery2 := countedScores(db, &exam, func(q *gorm.DB) *gorm.DB {
+		return frozen(q).Where("Q.is_active = ?", true).
+			Where("UQR.user_id IN ?", userIDs).
+			Where("UQR.question_id IN (SELECT eq.question_id FROM exam_questions eq WHERE eq.exam_id = ?)", exam.ID)
+	})
 
 	if err := db.Table("(?) AS sq", subquery2).
 		Joins("JOIN questions ON questions.id = sq.question_id").
-		Select("sq.user_id, sq.question_id, questions.title AS question_title, sq.git_user_repo_url, sq.score, sq.point, (sq.score / 100 * sq.point) AS weighted_score").
+		Joins("JOIN exam_questions EQ ON EQ.question_id = sq.question_id AND EQ.exam_id = ?", exam.ID).
+		Select("sq.user_id, sq.question_id, questions.title AS question_title, sq.git_user_repo_url, sq.score, EQ.point, (sq.score / 100 * EQ.point) AS weighted_score").
 		Find(&questionScores).Error; err != nil {
 		c.JSON(503, ResponseHTTP{
 			Success: false,
@@ -1079,18 +1239,8 @@ func GetExamLeaderboard(c *gin.Context) {
 	// Assemble the final leaderboard response with the enhanced structure
 	var leaderboardScores []EnhancedLeaderboardScore
 	for _, user := range usersWithScores {
-		userName := user.UserName
-		if !user.IsPublic {
-			if !isAuthenticated || !jwtClaims.IsAdmin {
-				hash := utils.HashUserID(user.UserID)
-				userName = hash[len(hash)-9:]
-			} else {
-				userName = user.UserName + " (Private)"
-			}
-		}
-
 		leaderboardScores = append(leaderboardScores, EnhancedLeaderboardScore{
-			UserName:       userName,
+			UserName:       examLeaderboardName(jwtClaims, exam, user.UserID, user.UserName, user.IsPublic),
 			TotalScore:     user.TotalScore,
 			QuestionScores: userQuestionScores[user.UserID],
 		})
@@ -1100,8 +1250,257 @@ func GetExamLeaderboard(c *gin.Context) {
 		Success: true,
 		Message: "Successfully retrieved exam leaderboard",
 		Data: EnhancedGetLeaderboardResponseData{
-			Count:  int(totalCount),
-			Scores: leaderboardScores,
+			Count:       int(totalCount),
+			RankingMode: models.RankingScore,
+			Scores:      leaderboardScores,
+			Frozen:      freezeTime != nil,
+			FreezeTime:  freezeTime,
+		},
+	})
+}
+
+// examLeaderboardName hides the names of private users from viewers who may not see scores
+func examLeaderboardName(jwtClaims *utils.JWTClaims, exam models.Exam, userID uint, userName string, isPublic bool) string {
+	if isPublic {
+		return userName
+	}
+	if !CanInCourse(jwtClaims, exam.CourseID, models.PermViewScores) {
+		hash := utils.HashUserID(userID)
+		return hash[len(hash)-9:]
+	}
+	return userName + " (Private)"
+}
+
+// getICPCExamLeaderboard ranks an ICPC mode exam by problems solved at full score, then by penalty
+// minutes: the solve time of each solved problem plus the exam's penalty per rejected attempt before it
+func getICPCExamLeaderboard(c *gin.Context, exam models.Exam, jwtClaims *utils.JWTClaims, frozen func(*gorm.DB) *gorm.DB, freezeTime *time.Time, offset, limit int) {
+	db := database.DBConn
+
+	var totalCount int64
+	results := icpcResults(db, exam, func(q *gorm.DB) *gorm.DB {
+		return frozen(q).Joins("JOIN users ON users.id = UQR.user_id").
+			Where("Q.is_active = ?", true).
+			Where("UQR.question_id IN (SELECT eq.question_id FROM exam_questions eq WHERE eq.exam_id = ?)", exam.ID).
+			Where("users.is_admin = ?", false)
+	})
+	if err := db.Table("(?) AS t", results).
+		Select("COUNT(DISTINCT user_id)").
+		Scan(&totalCount).Error; err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to count users with scores",
+		})
+		return
+	}
+
+	type UserWithICPCResult struct {
+		UserID         uint
+		UserName       string
+		IsPublic       bool
+		Solved         int
+		PenaltyMinutes int
+	}
+
+	var users []UserWithICPCResult
+	if err := db.Table("(?) AS r", results).
+		Joins("JOIN users ON users.id = r.user_id").
+		Select("users.id AS user_id, users.user_name, users.is_public, COUNT(r.solved_at) AS solved, "+
+			"COALESCE(SUM(r.solve_minutes + r.rejected * ?) FILTER (WHERE r.solved_at IS NOT NULL), 0)::int AS penalty_minutes", exam.PenaltyMinutes).
+		Group("users.id, users.user_name, users.is_public").
+		Order("solved DESC, penalty_minutes ASC, MAX(r.solved_at) ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to get exam leaderboard users",
+		})
+		return
+	}
+
+	if len(users) == 0 {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "No scores found for this exam",
+		})
+		return
+	}
+
+	var userIDs []uint
+	for _, u := range users {
+		userIDs = append(userIDs, u.UserID)
+	}
+
+	type ICPCProblemDetail struct {
+		UserID        uint
+		QuestionID    int
+		QuestionTitle string
+		SolvedAt      *time.Time
+		Rejected      int
+		SolveMinutes  *int
+	}
+
+	var problems []ICPCProblemDetail
+	problemResults := icpcResults(db, exam, func(q *gorm.DB) *gorm.DB {
+		return frozen(q).Where("Q.is_active = ?", true).
+			Where("UQR.user_id IN ?", userIDs).
+			Where("UQR.question_id IN (SELECT eq.question_id FROM exam_questions eq WHERE eq.exam_id = ?)", exam.ID)
+	})
+	if err := db.Table("(?) AS r", problemResults).
+		Joins("JOIN questions ON questions.id = r.question_id").
+		Select("r.user_id, r.question_id, questions.title AS question_title, r.solved_at, r.rejected, r.solve_minutes").
+		Find(&problems).Error; err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to get question scores",
+		})
+		return
+	}
+
+	userProblems := make(map[uint][]EnhancedQuestionScore)
+	for _, p := range problems {
+		problem := EnhancedQuestionScore{
+			QuestionID:    p.QuestionID,
+			QuestionTitle: p.QuestionTitle,
+			Attempts:      p.Rejected,
+		}
+		if p.SolvedAt != nil {
+			problem.Score = fullScore
+			problem.Attempts++
+			problem.SolvedAt = p.SolvedAt
+			problem.SolveMinutes = p.SolveMinutes
+		}
+		userProblems[p.UserID] = append(userProblems[p.UserID], problem)
+	}
+
+	var leaderboardScores []EnhancedLeaderboardScore
+	for _, user := range users {
+		leaderboardScores = append(leaderboardScores, EnhancedLeaderboardScore{
+			UserName:       examLeaderboardName(jwtClaims, exam, user.UserID, user.UserName, user.IsPublic),
+			TotalScore:     float64(user.Solved),
+			QuestionScores: userProblems[user.UserID],
+			Solved:         user.Solved,
+			PenaltyMinutes: user.PenaltyMinutes,
+		})
+	}
+
+	c.JSON(200, ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved exam leaderboard",
+		Data: EnhancedGetLeaderboardResponseData{
+			Count:       int(totalCount),
+			RankingMode: models.RankingICPC,
+			Scores:      leaderboardScores,
+			Frozen:      freezeTime != nil,
+			FreezeTime:  freezeTime,
 		},
 	})
 }
+
+// leaderboardFreezeTime returns the exam's freeze time if its leaderboard is frozen at now
+func leaderboardFreezeTime(exam models.Exam, now time.Time) *time.Time {
+	if exam.FreezeTime == nil || exam.Unfrozen || now.Before(*exam.FreezeTime) {
+		return nil
+	}
+	return exam.FreezeTime
+}
+
+type FreezeExamRequest struct {
+	FreezeTime time.Time `json:"freeze_time" binding:"required" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+}
+
+// FreezeExamLeaderboard schedules the freeze of an exam's leaderboard
+// @Summary      Freeze an exam leaderboard
+// @Description  Set the time the public exam leaderboard freezes. From then on it shows scores as of the freeze time, while judging continues and staff still see live results. Setting a freeze time again refreezes an unfrozen exam.
+// @Tags         Exam
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Param        freeze body FreezeExamRequest true "Freeze time"
+// @Success      200 {object} ResponseHTTP{data=models.Exam}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/freeze [put]
+// @Security BearerAuth
+func FreezeExamLeaderboard(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	var req FreezeExamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Invalid input: " + err.Error(),
+		})
+		return
+	}
+	if req.FreezeTime.Before(exam.StartTime) || req.FreezeTime.After(exam.EndTime) {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Freeze time must be within the exam",
+		})
+		return
+	}
+
+	exam.FreezeTime = &req.FreezeTime
+	exam.Unfrozen = false
+	if err := database.DBConn.Model(&exam).Select("freeze_time", "unfrozen").Updates(&exam).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to freeze exam leaderboard",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    exam,
+	})
+}
+
+// UnfreezeExamLeaderboard unfreezes an exam's leaderboard
+// @Summary      Unfreeze an exam leaderboard
+// @Description  Reveal the live exam leaderboard, including the submissions made since the freeze
+// @Tags         Exam
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Success      200 {object} ResponseHTTP{data=models.Exam}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/unfreeze [post]
+// @Security BearerAuth
+func UnfreezeExamLeaderboard(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+	if exam.FreezeTime == nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Exam has no freeze time",
+		})
+		return
+	}
+
+	exam.Unfrozen = true
+	if err := database.DBConn.Model(&exam).Select("unfrozen").Updates(&exam).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to unfreeze exam leaderboard",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    exam,
+	})
+}
@@ -0,0 +1,783 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+
+	"OJ-API/database"
+	"OJ-API/models"
+	"OJ-API/utils"
+)
+
+// accessCodeAlphabet leaves out characters that are easily confused (0/O, 1/I/L)
+const accessCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+const accessCodeLength = 8
+
+// examSessionFreshness is how recently a user must have opened a network-restricted exam
+// from an allowed network for their submissions to be accepted
+const examSessionFreshness = 15 * time.Minute
+
+// examRequiresSession reports whether users must start an exam session before taking the exam
+func examRequiresSession(exam models.Exam) bool {
+	return exam.RequireAccessCode || len(exam.AllowedCIDRs) > 0
+}
+
+// examTracksSession reports whether the exam records user sessions, either for its access
+// restrictions or to time each user from when they first open it
+func examTracksSession(exam models.Exam) bool {
+	return examRequiresSession(exam) || exam.DurationMinutes > 0
+}
+
+// isExamParticipant reports whether the user may take the exam. Exams without a participant list are open.
+func isExamParticipant(examID, userID uint) bool {
+	var participants int64
+	database.DBConn.Model(&models.ExamParticipant{}).Where("exam_id = ?", examID).Count(&participants)
+	if participants == 0 {
+		return true
+	}
+	var count int64
+	database.DBConn.Model(&models.ExamParticipant{}).Where("exam_id = ? AND user_id = ?", examID, userID).Count(&count)
+	return count > 0
+}
+
+// findExamSession returns the user's session for the exam, or nil if none was started
+func findExamSession(examID, userID uint) *models.ExamSession {
+	var session models.ExamSession
+	if err := database.DBConn.Where("exam_id = ? AND user_id = ?", examID, userID).
+		Limit(1).Find(&session).Error; err != nil || session.ExamID == 0 {
+		return nil
+	}
+	return &session
+}
+
+// checkExamAccess enforces the exam's participant list, allowed networks, access codes and the
+// user's exam window for a user viewing the exam from ip. Exam staff are not restricted. It returns
+// a zero status when access is granted, and records ip on the user's exam session.
+func checkExamAccess(jwtClaims *utils.JWTClaims, exam models.Exam, ip string) (int, string) {
+	if CanInCourse(jwtClaims, exam.CourseID, models.PermViewAllExams) {
+		return 0, ""
+	}
+
+	now := time.Now().UTC()
+	var participants int64
+	database.DBConn.Model(&models.ExamParticipant{}).Where("exam_id = ?", exam.ID).Count(&participants)
+	if jwtClaims == nil {
+		if participants > 0 || examTracksSession(exam) {
+			return http.StatusUnauthorized, "Login required for this exam"
+		}
+		return checkExamWindow(exam, 0, now)
+	}
+
+	if !isExamParticipant(exam.ID, jwtClaims.UserID) {
+		return http.StatusForbidden, "You are not a participant of this exam"
+	}
+	if len(exam.AllowedCIDRs) > 0 && !utils.IPInCIDRs(ip, exam.AllowedCIDRs) {
+		return http.StatusForbidden, "This exam is not available from your network"
+	}
+	if !examTracksSession(exam) {
+		return checkExamWindow(exam, jwtClaims.UserID, now)
+	}
+
+	session := findExamSession(exam.ID, jwtClaims.UserID)
+	if session == nil {
+		if exam.RequireAccessCode {
+			return http.StatusForbidden, "Access code required"
+		}
+		// Without access codes the session, and a timed exam's clock, starts on the first view inside the window
+		if status, message := checkExamWindow(exam, jwtClaims.UserID, now); status != 0 {
+			return status, message
+		}
+		session = &models.ExamSession{ExamID: exam.ID, UserID: jwtClaims.UserID, StartedAt: now}
+	}
+	session.IPAddress = ip
+	session.LastSeenAt = now
+	if err := saveExamSession(session); err != nil {
+		utils.Warnf("Failed to save exam session: %v", err)
+	}
+	return checkExamWindow(exam, jwtClaims.UserID, now)
+}
+
+// lateness describes a submission accepted after its deadline
+type lateness struct {
+	Late    bool    // submitted after the deadline, including within the grace period
+	Penalty float64 // percentage deducted from the score
+}
+
+// checkLate applies a late policy to a submission at now against the deadline
+func checkLate(policy *models.LatePolicy, deadline, now time.Time) (lateness, bool) {
+	if !now.After(deadline) {
+		return lateness{}, true
+	}
+	penalty, ok := policy.Penalty(now.Sub(deadline))
+	return lateness{Late: true, Penalty: penalty}, ok
+}
+
+// checkExamSubmission enforces the exam's restrictions and the user's exam window on a submission.
+// Past the end of the window, the question's late policy, or else the exam's, decides whether the
+// submission is accepted and how much it is penalized.
+func checkExamSubmission(user models.User, exam models.Exam, question models.Question, now time.Time) (lateness, int, string) {
+	claims := &utils.JWTClaims{UserID: user.ID, Username: user.UserName, IsAdmin: user.IsAdmin, Role: user.Role}
+	if CanInCourse(claims, exam.CourseID, models.PermViewAllExams) {
+		return lateness{}, 0, ""
+	}
+	if !isExamParticipant(exam.ID, user.ID) {
+		return lateness{}, http.StatusForbidden, "You are not a participant of this exam"
+	}
+	if examTracksSession(exam) {
+		session := findExamSession(exam.ID, user.ID)
+		if session == nil {
+			return lateness{}, http.StatusForbidden, "Start the exam before submitting"
+		}
+		// Submissions arrive through Gitea, so the user must have recently opened the exam from an allowed network
+		if len(exam.AllowedCIDRs) > 0 &&
+			(now.Sub(session.LastSeenAt) > examSessionFreshness || !utils.IPInCIDRs(session.IPAddress, exam.AllowedCIDRs)) {
+			return lateness{}, http.StatusForbidden, "Open the exam from an allowed network before submitting"
+		}
+	}
+
+	start, end := userExamWindow(exam, user.ID)
+	if now.Before(start) {
+		return lateness{}, http.StatusForbidden, "Exam has not started yet"
+	}
+	policy := question.LatePolicy
+	if policy == nil {
+		policy = exam.LatePolicy
+	}
+	late, ok := checkLate(policy, end, now)
+	if !ok {
+		return lateness{}, http.StatusGone, "Your exam time has ended"
+	}
+	return late, 0, ""
+}
+
+// checkQuestionSubmission checks that the user may submit to the question now: within their window
+// of an exam containing the question, or within the question's own active period otherwise. Late
+// submissions accepted by a late policy report their penalty; when several exams accept the
+// submission, the most favourable one applies.
+func checkQuestionSubmission(user models.User, question models.Question) (lateness, int, string) {
+	now := time.Now().UTC()
+	exams := examsForQuestion(question.ID)
+	if len(exams) == 0 {
+		if !question.StartTime.IsZero() && now.Before(question.StartTime) {
+			return lateness{}, http.StatusForbidden, "Testing period has not started yet"
+		}
+		if question.EndTime.IsZero() {
+			return lateness{}, 0, ""
+		}
+		late, ok := checkLate(question.LatePolicy, question.EndTime, now)
+		if !ok {
+			return lateness{}, http.StatusGone, "Testing period has ended"
+		}
+		return late, 0, ""
+	}
+
+	var best *lateness
+	status, message := 0, ""
+	for _, exam := range exams {
+		late, examStatus, examMessage := checkExamSubmission(user, exam, question, now)
+		if examStatus != 0 {
+			status, message = examStatus, examMessage
+			continue
+		}
+		if best == nil || (best.Late && !late.Late) || (best.Late == late.Late && late.Penalty < best.Penalty) {
+			best = &late
+		}
+	}
+	if best != nil {
+		return *best, 0, ""
+	}
+	return lateness{}, status, message
+}
+
+// lastSubmissionTime returns when the last submission to the question can be accepted from anyone:
+// the end of the latest exam window containing it, or the question's end time otherwise, plus the
+// late policy's cutoff. It returns false for questions without a deadline.
+func lastSubmissionTime(question models.Question) (time.Time, bool) {
+	exams := examsForQuestion(question.ID)
+	if len(exams) == 0 {
+		if question.EndTime.IsZero() {
+			return time.Time{}, false
+		}
+		return question.EndTime.Add(question.LatePolicy.Cutoff()), true
+	}
+
+	var last time.Time
+	for _, exam := range exams {
+		policy := question.LatePolicy
+		if policy == nil {
+			policy = exam.LatePolicy
+		}
+		if end := examLatestEnd(exam).Add(policy.Cutoff()); end.After(last) {
+			last = end
+		}
+	}
+	return last, true
+}
+
+// examsForQuestion returns the exams that contain the question
+func examsForQuestion(questionID uint) []models.Exam {
+	var exams []models.Exam
+	database.DBConn.
+		Joins("JOIN exam_questions ON exam_questions.exam_id = exams.id").
+		Where("exam_questions.question_id = ?", questionID).
+		Find(&exams)
+	return exams
+}
+
+// checkQuestionExamAccess applies checkExamAccess to every exam containing the question and
+// grants access if any of them does. Questions outside exams are not restricted.
+func checkQuestionExamAccess(jwtClaims *utils.JWTClaims, questionID uint, ip string) (int, string) {
+	exams := examsForQuestion(questionID)
+	if len(exams) == 0 {
+		return 0, ""
+	}
+	status, message := 0, ""
+	for _, exam := range exams {
+		if status, message = checkExamAccess(jwtClaims, exam, ip); status == 0 {
+			return 0, ""
+		}
+	}
+	return status, message
+}
+
+func saveExamSession(session *models.ExamSession) error {
+	return database.DBConn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "exam_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"ip_address", "last_seen_at"}),
+	}).Create(session).Error
+}
+
+// loadManagedExam loads the exam named by the :id parameter and checks that the user may manage it.
+// It writes the error response and returns false otherwise.
+func loadManagedExam(c *gin.Context) (models.Exam, bool) {
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	var exam models.Exam
+	if err := database.DBConn.First(&exam, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Exam not found",
+		})
+		return exam, false
+	}
+	if !canManageExam(jwtClaims, exam) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return exam, false
+	}
+	return exam, true
+}
+
+type StartExamSessionRequest struct {
+	AccessCode string `json:"access_code" example:"K7M2QX9P"`
+}
+
+// StartExamSession starts the user's session for a restricted exam
+// @Summary      Start an exam session
+// @Description  Check the exam's participant list, allowed networks and access code, and start the user's session. A one-time access code is only needed the first time; later calls just record the user's current address. Submissions to a network-restricted exam are only accepted within 15 minutes of the user last opening it from an allowed network. On timed exams the user's time starts with their first session.
+// @Tags         Exam
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Param        session body StartExamSessionRequest false "Access code"
+// @Success      200 {object} ResponseHTTP{data=models.ExamSession}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/{id}/session [post]
+// @Security BearerAuth
+func StartExamSession(c *gin.Context) {
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	db := database.DBConn
+	var exam models.Exam
+	if err := db.First(&exam, c.Param("id")).Error; err != nil || !canViewCourse(jwtClaims, exam.CourseID) {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Exam not found",
+		})
+		return
+	}
+
+	var req StartExamSessionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ResponseHTTP{
+				Success: false,
+				Message: "Invalid input: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	if status, message := checkExamWindow(exam, jwtClaims.UserID, now); status != 0 {
+		c.JSON(status, ResponseHTTP{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
+	if !isExamParticipant(exam.ID, jwtClaims.UserID) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "You are not a participant of this exam",
+		})
+		return
+	}
+	ip := utils.GetClientIP(c)
+	if len(exam.AllowedCIDRs) > 0 && !utils.IPInCIDRs(ip, exam.AllowedCIDRs) {
+		c.JSON(http.StatusForbidden, ResponseHTTP{
+			Success: false,
+			Message: "This exam is not available from your network",
+		})
+		return
+	}
+
+	session := findExamSession(exam.ID, jwtClaims.UserID)
+	if session == nil {
+		session = &models.ExamSession{ExamID: exam.ID, UserID: jwtClaims.UserID, StartedAt: now}
+		if exam.RequireAccessCode {
+			code := strings.ToUpper(strings.TrimSpace(req.AccessCode))
+			if code == "" {
+				c.JSON(http.StatusForbidden, ResponseHTTP{
+					Success: false,
+					Message: "Access code required",
+				})
+				return
+			}
+			// 以條件更新確保同一組代碼只能被使用一次
+			var accessCode models.ExamAccessCode
+			db.Where("exam_id = ? AND code = ?", exam.ID, code).Limit(1).Find(&accessCode)
+			result := db.Model(&models.ExamAccessCode{}).
+				Where("id = ? AND used_by_id IS NULL", accessCode.ID).
+				Updates(map[string]any{"used_by_id": jwtClaims.UserID, "used_at": now})
+			if accessCode.ID == 0 || result.Error != nil || result.RowsAffected == 0 {
+				c.JSON(http.StatusForbidden, ResponseHTTP{
+					Success: false,
+					Message: "Invalid or already used access code",
+				})
+				return
+			}
+			session.AccessCodeID = &accessCode.ID
+		}
+	}
+	session.IPAddress = ip
+	session.LastSeenAt = now
+
+	if err := saveExamSession(session); err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to start exam session",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    session,
+	})
+}
+
+type UpdateExamAccessRequest struct {
+	AllowedCIDRs      []string `json:"allowed_cidrs" example:"140.124.0.0/16"`
+	RequireAccessCode bool     `json:"require_access_code"`
+}
+
+// UpdateExamAccess sets an exam's network and access code restrictions
+// @Summary      Update exam access restrictions
+// @Description  Set the client networks (CIDRs or single addresses) the exam may be taken from and whether a one-time access code is required. An empty list allows every network.
+// @Tags         Exam
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Param        access body UpdateExamAccessRequest true "Access restrictions"
+// @Success      200 {object} ResponseHTTP{data=models.Exam}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/access [put]
+// @Security BearerAuth
+func UpdateExamAccess(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateExamAccessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Invalid input: " + err.Error(),
+		})
+		return
+	}
+	cidrs := make([]string, 0, len(req.AllowedCIDRs))
+	for _, cidr := range req.AllowedCIDRs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil && net.ParseIP(cidr) == nil {
+			c.JSON(http.StatusBadRequest, ResponseHTTP{
+				Success: false,
+				Message: "Invalid CIDR: " + cidr,
+			})
+			return
+		}
+		cidrs = append(cidrs, cidr)
+	}
+
+	exam.AllowedCIDRs = cidrs
+	exam.RequireAccessCode = req.RequireAccessCode
+	if err := database.DBConn.Model(&exam).Select("allowed_cidrs", "require_access_code").Updates(&exam).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to update exam access",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    exam,
+	})
+}
+
+type ExamParticipantData struct {
+	UserID    uint       `json:"user_id"`
+	UserName  string     `json:"user_name"`
+	CreatedAt time.Time  `json:"created_at"`
+	StartedAt *time.Time `json:"started_at"` // nil until the participant starts an exam session
+	IPAddress string     `json:"ip_address"`
+}
+
+// ListExamParticipants lists an exam's participants
+// @Summary      List exam participants
+// @Description  List the users allowed to take the exam and their session status. An empty list means the exam is open to everyone who can see it.
+// @Tags         Exam
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Success      200 {object} ResponseHTTP{data=[]ExamParticipantData}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/participants [get]
+// @Security BearerAuth
+func ListExamParticipants(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	participants := []ExamParticipantData{}
+	if err := database.DBConn.Table("exam_participants EP").
+		Select("EP.user_id, U.user_name, EP.created_at, ES.started_at, COALESCE(ES.ip_address, '') AS ip_address").
+		Joins("JOIN users U ON U.id = EP.user_id").
+		Joins("LEFT JOIN exam_sessions ES ON ES.exam_id = EP.exam_id AND ES.user_id = EP.user_id").
+		Where("EP.exam_id = ?", exam.ID).
+		Order("U.user_name").
+		Scan(&participants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to retrieve exam participants",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    participants,
+	})
+}
+
+type AddExamParticipantsRequest struct {
+	UserNames []string `json:"user_names" binding:"required,min=1" example:"student1,student2"`
+}
+
+// AddExamParticipants adds users to an exam's participant list
+// @Summary      Add exam participants
+// @Description  Add users to the exam's participant list. Once the list is not empty, only listed users can take the exam.
+// @Tags         Exam
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Param        participants body AddExamParticipantsRequest true "Users to add"
+// @Success      200 {object} ResponseHTTP{data=AddCourseMembersResponse}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/participants [post]
+// @Security BearerAuth
+func AddExamParticipants(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	var req AddExamParticipantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Invalid input: " + err.Error(),
+		})
+		return
+	}
+
+	db := database.DBConn
+	var users []models.User
+	if err := db.Where("user_name IN ?", req.UserNames).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to look up users",
+		})
+		return
+	}
+
+	found := make(map[string]bool, len(users))
+	participants := make([]models.ExamParticipant, 0, len(users))
+	response := AddCourseMembersResponse{Enrolled: []string{}, NotFound: []string{}}
+	for _, user := range users {
+		found[user.UserName] = true
+		participants = append(participants, models.ExamParticipant{ExamID: exam.ID, UserID: user.ID})
+		response.Enrolled = append(response.Enrolled, user.UserName)
+	}
+	for _, name := range req.UserNames {
+		if !found[name] {
+			response.NotFound = append(response.NotFound, name)
+		}
+	}
+
+	if len(participants) > 0 {
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&participants).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ResponseHTTP{
+				Success: false,
+				Message: "Failed to add exam participants",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// RemoveExamParticipant removes a user from an exam's participant list
+// @Summary      Remove an exam participant
+// @Description  Remove a user from the exam's participant list
+// @Tags         Exam
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Param        user_id path int true "User ID"
+// @Success      200 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/participants/{user_id} [delete]
+// @Security BearerAuth
+func RemoveExamParticipant(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	result := database.DBConn.Where("exam_id = ? AND user_id = ?", exam.ID, c.Param("user_id")).
+		Delete(&models.ExamParticipant{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to remove exam participant",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Exam participant not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Message: "Exam participant removed successfully",
+	})
+}
+
+// ResetExamSession clears a user's exam session
+// @Summary      Reset an exam session
+// @Description  Clear a user's exam session so they can start again, for example from another machine. The access code they used stays consumed.
+// @Tags         Exam
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Param        user_id path int true "User ID"
+// @Success      200 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/sessions/{user_id} [delete]
+// @Security BearerAuth
+func ResetExamSession(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	result := database.DBConn.Where("exam_id = ? AND user_id = ?", exam.ID, c.Param("user_id")).
+		Delete(&models.ExamSession{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to reset exam session",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Exam session not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Message: "Exam session reset successfully",
+	})
+}
+
+type GenerateExamAccessCodesRequest struct {
+	Count int `json:"count" binding:"required,min=1,max=500" example:"30"`
+}
+
+// GenerateExamAccessCodes creates one-time access codes for an exam
+// @Summary      Generate exam access codes
+// @Description  Generate one-time access codes for the exam. Each code can start one user's exam session.
+// @Tags         Exam
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Param        codes body GenerateExamAccessCodesRequest true "Number of codes"
+// @Success      200 {object} ResponseHTTP{data=[]models.ExamAccessCode}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/access_codes [post]
+// @Security BearerAuth
+func GenerateExamAccessCodes(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	var req GenerateExamAccessCodesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Invalid input: " + err.Error(),
+		})
+		return
+	}
+
+	codes := make([]models.ExamAccessCode, 0, req.Count)
+	seen := make(map[string]bool, req.Count)
+	for len(codes) < req.Count {
+		code, err := generateAccessCode()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ResponseHTTP{
+				Success: false,
+				Message: "Failed to generate access codes",
+			})
+			return
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, models.ExamAccessCode{ExamID: exam.ID, Code: code})
+	}
+
+	// 與既有代碼衝突的極少數情況直接略過
+	if err := database.DBConn.Clauses(clause.OnConflict{DoNothing: true}).Create(&codes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to save access codes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    codes,
+	})
+}
+
+// ListExamAccessCodes lists an exam's access codes
+// @Summary      List exam access codes
+// @Description  List the exam's one-time access codes and who used them
+// @Tags         Exam
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Success      200 {object} ResponseHTTP{data=[]models.ExamAccessCode}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/access_codes [get]
+// @Security BearerAuth
+func ListExamAccessCodes(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	codes := []models.ExamAccessCode{}
+	if err := database.DBConn.Where("exam_id = ?", exam.ID).Order("id").Find(&codes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to retrieve access codes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    codes,
+	})
+}
+
+func generateAccessCode() (string, error) {
+	code := make([]byte, accessCodeLength)
+	alphabetSize := big.NewInt(int64(len(accessCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = accessCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
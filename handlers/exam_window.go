@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+
+	"OJ-API/database"
+	"OJ-API/models"
+)
+
+// findExamOverride returns the user's override for the exam, or nil if they have none
+func findExamOverride(examID, userID uint) *models.ExamUserOverride {
+	var override models.ExamUserOverride
+	if err := database.DBConn.Where("exam_id = ? AND user_id = ?", examID, userID).
+		Limit(1).Find(&override).Error; err != nil || override.ExamID == 0 {
+		return nil
+	}
+	return &override
+}
+
+// userExamWindow returns the period the user may take the exam in. The user's override replaces
+// the exam's start and end times and lengthens the exam; for timed exams the window ends once the
+// user's time since first opening the exam runs out.
+func userExamWindow(exam models.Exam, userID uint) (start, end time.Time) {
+	start, end = exam.StartTime, exam.EndTime
+	override := findExamOverride(exam.ID, userID)
+	if override != nil {
+		if override.StartTime != nil {
+			start = *override.StartTime
+		}
+		if override.EndTime != nil {
+			end = *override.EndTime
+		}
+	}
+	extend := func(d time.Duration) time.Duration {
+		if override == nil {
+			return d
+		}
+		return d + d*time.Duration(override.ExtraPercent)/100 + time.Duration(override.ExtraMinutes)*time.Minute
+	}
+
+	if exam.DurationMinutes <= 0 {
+		return start, end.Add(extend(end.Sub(start)) - end.Sub(start))
+	}
+
+	duration := time.Duration(exam.DurationMinutes) * time.Minute
+	session := findExamSession(exam.ID, userID)
+	if session == nil {
+		// 尚未開始作答，回傳可開始作答的時段
+		return start, end
+	}
+	// Extra time also extends the hard end, so users starting late still get it
+	personalEnd := session.StartedAt.Add(extend(duration))
+	if hardEnd := end.Add(extend(duration) - duration); personalEnd.After(hardEnd) {
+		personalEnd = hardEnd
+	}
+	return start, personalEnd
+}
+
+// examLatestEnd returns the latest end of any user's exam window, including overrides and extra time
+func examLatestEnd(exam models.Exam) time.Time {
+	latest := exam.EndTime
+	var overrides []models.ExamUserOverride
+	database.DBConn.Where("exam_id = ?", exam.ID).Find(&overrides)
+	for _, override := range overrides {
+		start, end := exam.StartTime, exam.EndTime
+		if override.StartTime != nil {
+			start = *override.StartTime
+		}
+		if override.EndTime != nil {
+			end = *override.EndTime
+		}
+		// Extra time extends the hard end by a share of the window, or of the duration for timed exams
+		length := end.Sub(start)
+		if exam.DurationMinutes > 0 {
+			length = time.Duration(exam.DurationMinutes) * time.Minute
+		}
+		end = end.Add(length*time.Duration(override.ExtraPercent)/100 + time.Duration(override.ExtraMinutes)*time.Minute)
+		if end.After(latest) {
+			latest = end
+		}
+	}
+	return latest
+}
+
+// checkExamWindow returns a non-zero status when now is outside the user's exam window
+func checkExamWindow(exam models.Exam, userID uint, now time.Time) (int, string) {
+	start, end := userExamWindow(exam, userID)
+	if now.Before(start) {
+		return http.StatusForbidden, "Exam has not started yet"
+	}
+	if now.After(end) {
+		return http.StatusGone, "Your exam time has ended"
+	}
+	return 0, ""
+}
+
+type ExamOverrideRequest struct {
+	StartTime    *time.Time `json:"start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	EndTime      *time.Time `json:"end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	ExtraPercent int        `json:"extra_percent" binding:"min=0,max=1000" example:"50"`
+	ExtraMinutes int        `json:"extra_minutes" binding:"min=0" example:"0"`
+	Reason       string     `json:"reason" binding:"max=200" example:"Accommodation"`
+}
+
+type ExamOverrideData struct {
+	models.ExamUserOverride
+	UserName string `json:"user_name"`
+}
+
+// ListExamOverrides lists the per-user window overrides of an exam
+// @Summary      List exam overrides
+// @Description  List the users with a personal exam window (extra time or make-up times)
+// @Tags         Exam
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Success      200 {object} ResponseHTTP{data=[]ExamOverrideData}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/overrides [get]
+// @Security BearerAuth
+func ListExamOverrides(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	overrides := []ExamOverrideData{}
+	if err := database.DBConn.Table("exam_user_overrides").
+		Select("exam_user_overrides.*, users.user_name").
+		Joins("JOIN users ON users.id = exam_user_overrides.user_id").
+		Where("exam_user_overrides.exam_id = ?", exam.ID).
+		Order("users.user_name").
+		Scan(&overrides).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to retrieve exam overrides",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    overrides,
+	})
+}
+
+// SetExamOverride sets a user's personal exam window
+// @Summary      Set an exam override
+// @Description  Give a user a personal exam window. start_time and end_time replace the exam's times (make-up exams); extra_percent and extra_minutes lengthen the exam, or each user's time on timed exams (accommodations).
+// @Tags         Exam
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Param        user_id path int true "User ID"
+// @Param        override body ExamOverrideRequest true "Override"
+// @Success      200 {object} ResponseHTTP{data=models.ExamUserOverride}
+// @Failure      400 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/overrides/{user_id} [put]
+// @Security BearerAuth
+func SetExamOverride(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	db := database.DBConn
+	var user models.User
+	if err := db.First(&user, c.Param("user_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+
+	var req ExamOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Invalid input: " + err.Error(),
+		})
+		return
+	}
+	start, end := exam.StartTime, exam.EndTime
+	if req.StartTime != nil {
+		start = *req.StartTime
+	}
+	if req.EndTime != nil {
+		end = *req.EndTime
+	}
+	if !start.Before(end) {
+		c.JSON(http.StatusBadRequest, ResponseHTTP{
+			Success: false,
+			Message: "Start time must be before end time",
+		})
+		return
+	}
+
+	override := models.ExamUserOverride{
+		ExamID:       exam.ID,
+		UserID:       user.ID,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		ExtraPercent: req.ExtraPercent,
+		ExtraMinutes: req.ExtraMinutes,
+		Reason:       req.Reason,
+	}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "exam_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"start_time", "end_time", "extra_percent", "extra_minutes", "reason", "updated_at"}),
+	}).Create(&override).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to save exam override",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Data:    override,
+	})
+}
+
+// DeleteExamOverride removes a user's personal exam window
+// @Summary      Delete an exam override
+// @Description  Remove a user's personal exam window so the exam's own times apply again
+// @Tags         Exam
+// @Produce      json
+// @Param        id path string true "Exam ID"
+// @Param        user_id path int true "User ID"
+// @Success      200 {object} ResponseHTTP{}
+// @Failure      401
+// @Failure      403 {object} ResponseHTTP{}
+// @Failure      404 {object} ResponseHTTP{}
+// @Failure      500 {object} ResponseHTTP{}
+// @Router       /api/exams/admin/{id}/overrides/{user_id} [delete]
+// @Security BearerAuth
+func DeleteExamOverride(c *gin.Context) {
+	exam, ok := loadManagedExam(c)
+	if !ok {
+		return
+	}
+
+	result := database.DBConn.Where("exam_id = ? AND user_id = ?", exam.ID, c.Param("user_id")).
+		Delete(&models.ExamUserOverride{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, ResponseHTTP{
+			Success: false,
+			Message: "Failed to delete exam override",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, ResponseHTTP{
+			Success: false,
+			Message: "Exam override not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ResponseHTTP{
+		Success: true,
+		Message: "Exam override deleted successfully",
+	})
+}
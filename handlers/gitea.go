@@ -10,6 +10,7 @@ import (
 	"OJ-API/config"
 	"OJ-API/database"
 	"OJ-API/models"
+	"OJ-API/services"
 	"OJ-API/utils"
 )
 
@@ -71,6 +72,7 @@ func CreateUserAccessToken(username, email, password string) {
 // @Success		200		{object}	ResponseHTTP{data=BulkCreateUserResponse} "Return successful and failed users"
 // @Failure		400
 // @Failure		401
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Security	BearerAuth
@@ -78,13 +80,6 @@ func CreateUserAccessToken(username, email, password string) {
 func PostBulkCreateUserGitea(c *gin.Context) {
 	db := database.DBConn
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 
 	bulkUsers := new(BulkCreateUser)
 	if err := c.ShouldBindJSON(bulkUsers); err != nil {
@@ -185,6 +180,7 @@ type BulkCreateUserRequest struct {
 // @Success		200		{object}	ResponseHTTP{data=BulkCreateUserResponse} "Return successful and failed users"
 // @Failure		400
 // @Failure		401
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Security	BearerAuth
@@ -192,13 +188,6 @@ type BulkCreateUserRequest struct {
 func PostBulkCreateUserGiteav2(c *gin.Context) {
 	db := database.DBConn
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(403, ResponseHTTP{
-			Success: false,
-			Message: "Permission denied",
-		})
-		return
-	}
 
 	bulkUsers := new(BulkCreateUserRequest)
 	if err := c.ShouldBindJSON(bulkUsers); err != nil {
@@ -286,6 +275,7 @@ func PostBulkCreateUserGiteav2(c *gin.Context) {
 // @Success		200		{object}	ResponseHTTP{}
 // @Failure		400
 // @Failure		401
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Security	BearerAuth
@@ -330,6 +320,20 @@ func PostCreateQuestionRepositoryGitea(c *gin.Context) {
 		})
 		return
 	}
+	if !canViewCourse(jwtClaims, existingQuestion.CourseID) {
+		c.JSON(403, ResponseHTTP{
+			Success: false,
+			Message: "You are not enrolled in this question's course",
+		})
+		return
+	}
+	if status, message := checkQuestionExamAccess(jwtClaims, existingQuestion.ID, utils.GetClientIP(c)); status != 0 {
+		c.JSON(status, ResponseHTTP{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
 
 	parentRepoURLParts := strings.Split(existingQuestion.GitRepoURL, "/")
 	parentRepoUsername := parentRepoURLParts[0]
@@ -357,56 +361,32 @@ func PostCreateQuestionRepositoryGitea(c *gin.Context) {
 		repo = migrateRepo
 	}
 
-	hooks, resp, err := client.ListRepoHooks(jwtClaims.Username, parentRepoName, gitea.ListHooksOptions{})
-	if err != nil {
-		c.JSON(resp.StatusCode, ResponseHTTP{
-			Success: false,
-			Message: "Failed to list repository hooks: " + err.Error(),
-		})
-		return
-	}
-	hookExists := false
-	for _, hook := range hooks {
-		if hook.Config["url"] == config.GetOJBaseURL()+"/api/gitea" {
-			hookExists = true
-			break
-		}
-	}
-
-	accessToken, err := utils.GenerateAccessToken(jwtClaims.UserID, jwtClaims.Username, jwtClaims.IsAdmin)
-	if err != nil {
-		utils.Errorf("Failed to generate token for %s/%s: %v", jwtClaims.Username, parentRepoName, err)
-		return
-	}
-	if !hookExists {
-		if _, resp, err := client.CreateRepoHook(jwtClaims.Username, parentRepoName, gitea.CreateHookOption{
-			Type:   "gitea",
-			Active: true,
-			Events: []string{"push"},
-			Config: map[string]string{
-				"url":          config.GetOJBaseURL() + "/api/gitea",
-				"content_type": "json",
-			},
-			AuthorizationHeader: "Bearer " + accessToken,
-		}); err != nil {
-			c.JSON(resp.StatusCode, ResponseHTTP{
-				Success: false,
-				Message: "Failed to create repository hook: " + err.Error(),
-			})
-			return
-		}
-	}
-
 	var userQuestionRelation models.UserQuestionRelation
 	if err := db.Where(&models.UserQuestionRelation{
 		UserID:     jwtClaims.UserID,
 		QuestionID: uint(questionID),
-	}).First(&userQuestionRelation).Error; err != nil || (userQuestionRelation == models.UserQuestionRelation{}) {
-		db.Create(&models.UserQuestionRelation{
+	}).First(&userQuestionRelation).Error; err != nil || userQuestionRelation.ID == 0 {
+		userQuestionRelation = models.UserQuestionRelation{
 			UserID:         jwtClaims.UserID,
 			QuestionID:     uint(questionID),
 			GitUserRepoURL: jwtClaims.Username + "/" + parentRepoName,
+		}
+		if err := db.Create(&userQuestionRelation).Error; err != nil {
+			c.JSON(503, ResponseHTTP{
+				Success: false,
+				Message: "Failed to create user-question relation",
+			})
+			return
+		}
+	}
+
+	// Push events are signed with the relation's secret instead of carrying a user token
+	if err := services.EnsureRepoHook(client, &userQuestionRelation); err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to set up repository hook: " + err.Error(),
 		})
+		return
 	}
 
 	if repo != nil {
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"OJ-API/config"
+	"OJ-API/database"
+	"OJ-API/models"
+	"OJ-API/services"
+	"OJ-API/utils"
+)
+
+// practiceRefPrefix marks pushed branches that start a practice run instead of a graded submission
+const practiceRefPrefix = "refs/heads/practice/"
+
+type PracticeRunRequest struct {
+	Commit string `json:"commit" binding:"omitempty,hexadecimal,max=64" example:"3f786850e387550fdab836ed7e6dc881de23001b"` // HEAD of the default branch if empty
+}
+
+type GetPracticeRunsResponseData struct {
+	RunsCount int                  `json:"runs_count" validate:"required"`
+	Runs      []models.PracticeRun `json:"runs" validate:"required"`
+}
+
+// startPracticeRun records a practice run of the user's repository and queues it on the sandboxes
+func startPracticeRun(ctx context.Context, user models.User, question models.Question, uqr models.UserQuestionRelation, commit, ref, deliveryID string) (models.PracticeRun, error) {
+	db := database.DBConn
+	run := models.PracticeRun{
+		UQRID:      uqr.ID,
+		Score:      -3,
+		JudgeTime:  time.Now().UTC(),
+		Message:    "Waiting for judging...",
+		Commit:     commit,
+		Ref:        ref,
+		RequestID:  utils.RequestIDFromContext(ctx),
+		DeliveryID: deliveryID,
+	}
+	if err := db.Create(&run).Error; err != nil {
+		return run, err
+	}
+
+	ctx = utils.WithJobID(ctx, uint64(run.ID))
+	go func() {
+		// 獲取用戶 token
+		token, err := utils.GetToken(user.ID)
+		if err != nil {
+			utils.Ctx(ctx).Errorf("Failed to get token: %v", err)
+			db.Model(&run).Updates(models.PracticeRun{
+				Score:   -2,
+				Message: fmt.Sprintf("Failed to get token: %v", err),
+			})
+			return
+		}
+
+		// 練習評測與正式評測使用同一個沙箱隊列，但排在正式評測之後
+		gitRepoURL := config.GetGiteaBaseURL() + "/" + uqr.GitUserRepoURL
+		if err := services.GetSandboxClientManager().ReservePracticeJob(
+			ctx,
+			question.GitRepoURL, // parentGitFullName
+			gitRepoURL,          // gitRepoURL
+			uqr.GitUserRepoURL,  // gitFullName
+			commit,              // gitAfterHash (空字符串表示使用 HEAD)
+			user.UserName,       // gitUsername
+			token,               // gitToken
+			uint64(run.ID),      // practiceRunID
+		); err != nil {
+			utils.Ctx(ctx).Errorf("Failed to queue practice run: %v", err)
+			db.Model(&run).Updates(models.PracticeRun{
+				Score:   -2,
+				Message: fmt.Sprintf("Failed to queue job: %v", err),
+			})
+		}
+	}()
+	return run, nil
+}
+
+// PostPracticeRun starts a practice run of the user's repository
+//
+//	@Summary		Start a practice run
+//	@Description	Judge the user's repository against the public test suites only. Only targets whose score map task lists a public suite are compiled and run, with GTEST_FILTER and OJ_TEST_SUITES set to those suites. Practice runs are stored apart from graded submissions and never count towards scores, leaderboards or exports. Pushing to a practice/* branch starts one as well.
+//	@Tags			Practice
+//	@Accept			json
+//	@Produce		json
+//	@Param			question_id	path		int					true	"question ID"
+//	@Param			run			body		PracticeRunRequest	false	"Commit to judge"
+//	@Success		200			{object}	ResponseHTTP{data=models.PracticeRun}
+//	@Failure		400			{object}	ResponseHTTP{}
+//	@Failure		401
+//	@Failure		403			{object}	ResponseHTTP{}
+//	@Failure		404			{object}	ResponseHTTP{}
+//	@Failure		410			{object}	ResponseHTTP{}
+//	@Failure		503			{object}	ResponseHTTP{}
+//	@Router			/api/practice/{question_id} [post]
+//	@Security		BearerAuth
+func PostPracticeRun(c *gin.Context) {
+	db := database.DBConn
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	var req PracticeRunRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, ResponseHTTP{
+				Success: false,
+				Message: "Invalid input: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	var question models.Question
+	if err := db.Where("id = ? AND is_active = ?", c.Param("question_id"), true).First(&question).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Question not found",
+		})
+		return
+	}
+	var user models.User
+	if err := db.First(&user, jwtClaims.UserID).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+	// Practice runs follow the same submission period as graded pushes, but lateness does not matter
+	if _, status, message := checkQuestionSubmission(user, question); status != 0 {
+		c.JSON(status, ResponseHTTP{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
+
+	var uqr models.UserQuestionRelation
+	if err := db.Where("question_id = ? AND user_id = ?", question.ID, user.ID).First(&uqr).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Repository for this question not found",
+		})
+		return
+	}
+
+	run, err := startPracticeRun(c.Request.Context(), user, question, uqr, req.Commit, "", "")
+	if err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to create practice run",
+		})
+		return
+	}
+
+	c.JSON(200, ResponseHTTP{
+		Success: true,
+		Message: "Practice run queued",
+		Data:    run,
+	})
+}
+
+// GetPracticeRuns lists the user's practice runs of a question
+//
+//	@Summary		List practice runs
+//	@Description	List the user's practice runs of a question, newest first
+//	@Tags			Practice
+//	@Produce		json
+//	@Param			question_id	path		int	true	"question ID"
+//	@Param			page		query		int	false	"page number of results to return (1-based)"
+//	@Param			limit		query		int	false	"page size of results. Default is 10."
+//	@Success		200			{object}	ResponseHTTP{data=GetPracticeRunsResponseData}
+//	@Failure		401
+//	@Failure		503			{object}	ResponseHTTP{}
+//	@Router			/api/practice/{question_id} [get]
+//	@Security		BearerAuth
+func GetPracticeRuns(c *gin.Context) {
+	db := database.DBConn
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	ownRuns := func() *gorm.DB {
+		return db.Model(&models.PracticeRun{}).
+			Joins("JOIN user_question_relations UQR ON UQR.id = practice_runs.uqr_id").
+			Where("UQR.question_id = ? AND UQR.user_id = ?", c.Param("question_id"), jwtClaims.UserID)
+	}
+
+	var totalCount int64
+	if err := ownRuns().Count(&totalCount).Error; err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to count practice runs",
+		})
+		return
+	}
+
+	runs := []models.PracticeRun{}
+	if err := ownRuns().Order("practice_runs.created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&runs).Error; err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to get practice runs",
+		})
+		return
+	}
+
+	c.JSON(200, ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved practice runs",
+		Data: GetPracticeRunsResponseData{
+			RunsCount: int(totalCount),
+			Runs:      runs,
+		},
+	})
+}
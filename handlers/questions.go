@@ -11,6 +11,7 @@ import (
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type _GetQuestionListQuestionData struct {
@@ -33,6 +34,7 @@ type GetQuestionListResponseData struct {
 // @Param			page	query	int		false	"page number of results to return (1-based)"
 // @Param			limit	query	int		false	"page size of results. Default is 10."
 // @Param			status	query	string	false	"Filter by question status: 'all', 'active', or 'expired'. Default is 'all'."
+// @Param			course_id	query	int		false	"Only list questions of this course"
 // @Success		200		{object}	ResponseHTTP{data=[]GetQuestionListResponseData}
 // @Failure		404
 // @Failure		503
@@ -45,13 +47,21 @@ func GetQuestionList(c *gin.Context) {
 	var isAdmin bool
 	if ok && jwtClaim != nil {
 		userID = jwtClaim.UserID
-		isAdmin = jwtClaim.IsAdmin
+		isAdmin = jwtClaim.Can(models.PermViewAllQuestions)
 	}
 
 	// Parse query parameters for pagination
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	status := c.DefaultQuery("status", "all") // all, active, expired
+	courseID, err := parseCourseID(c.Query("course_id"))
+	if err != nil {
+		c.JSON(400, ResponseHTTP{
+			Success: false,
+			Message: "Invalid course ID",
+		})
+		return
+	}
 
 	// Calculate offset
 	offset := (page - 1) * limit
@@ -59,6 +69,10 @@ func GetQuestionList(c *gin.Context) {
 	// Build base query
 	baseQuery := db.Model(&models.Question{}).
 		Where("id NOT IN (SELECT question_id FROM exam_questions)")
+	baseQuery = scopeToCourses(baseQuery, jwtClaim, "course_id")
+	if courseID != nil {
+		baseQuery = baseQuery.Where("course_id = ?", *courseID)
+	}
 
 	if !isAdmin {
 		// If not admin, filter out inactive questions
@@ -86,6 +100,10 @@ func GetQuestionList(c *gin.Context) {
 	// Get questions first
 	query := db.Model(&models.Question{}).
 		Where("id NOT IN (SELECT question_id FROM exam_questions)")
+	query = scopeToCourses(query, jwtClaim, "course_id")
+	if courseID != nil {
+		query = query.Where("course_id = ?", *courseID)
+	}
 
 	if !isAdmin {
 		query = query.Where("is_active = ?", true)
@@ -137,20 +155,14 @@ func GetQuestionList(c *gin.Context) {
 			TopScore   *float64 `json:"top_score"`
 		}
 
-		err := db.Raw(`
-			SELECT 
-				q.id as question_id,
-				CASE 
-					WHEN MAX(uqt.score) IS NULL THEN NULL
-					WHEN MAX(uqt.score) < 0 THEN 0
-					ELSE MAX(uqt.score)
-				END as top_score
-			FROM questions q
-			LEFT JOIN user_question_relations uqr ON q.id = uqr.question_id AND uqr.user_id = ?
-			LEFT JOIN user_question_tables uqt ON uqr.id = uqt.uqr_id
-			WHERE q.id IN ?
-			GROUP BY q.id
-		`, userID, questionIDs).Scan(&topScores).Error
+		counted := countedScores(db, nil, func(q *gorm.DB) *gorm.DB {
+			return q.Where("UQR.user_id = ? AND UQR.question_id IN ?", userID, questionIDs)
+		})
+		err := db.Table("questions q").
+			Select("q.id AS question_id, cs.score AS top_score").
+			Joins("LEFT JOIN (?) cs ON cs.question_id = q.id", counted).
+			Where("q.id IN ?", questionIDs).
+			Scan(&topScores).Error
 
 		if err != nil {
 			c.JSON(503, ResponseHTTP{
@@ -345,6 +357,8 @@ type GetQuestionByIDResponseData struct {
 // @Produce		json
 // @Param			ID	path	int	true	"ID of the Question to get"
 // @Success		200		{object}	ResponseHTTP{data=GetQuestionResponseData}
+// @Failure		401
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Router			/api/questions/{ID}/question [get]
@@ -353,7 +367,7 @@ func GetQuestionByID(c *gin.Context) {
 	jwtClaim, ok := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
 	var isAdmin = false
 	if ok && jwtClaim != nil {
-		isAdmin = jwtClaim.IsAdmin
+		isAdmin = jwtClaim.Can(models.PermViewAllQuestions)
 	}
 
 	client, err := gitea.NewClient(config.GetGiteaBaseURL())
@@ -376,13 +390,20 @@ func GetQuestionByID(c *gin.Context) {
 
 	var question models.Question
 	db.Where("id = ?", ID).Limit(1).Find(&question)
-	if question.ID == 0 || (!question.IsActive && !isAdmin) {
+	if question.ID == 0 || (!question.IsActive && !isAdmin) || !canViewCourse(jwtClaim, question.CourseID) {
 		c.JSON(404, ResponseHTTP{
 			Success: false,
 			Message: "Question not found",
 		})
 		return
 	}
+	if status, message := checkQuestionExamAccess(jwtClaim, question.ID, utils.GetClientIP(c)); status != 0 {
+		c.JSON(status, ResponseHTTP{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
 
 	c.JSON(200, ResponseHTTP{
 		Success: true,
@@ -418,20 +439,13 @@ type GetQuestionLimitByIDResponseData struct {
 // @Produce		json
 // @Param			ID	path	int	true	"ID of the Question to get"
 // @Success		200		{object}	ResponseHTTP{data=GetQuestionResponseData}
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Router			/api/questions/admin/{ID}/question_limit [get]
 // @Security		BearerAuth
 func GetQuestionLimitByID(c *gin.Context) {
 	db := database.DBConn
-	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(401, ResponseHTTP{
-			Success: false,
-			Message: "Unauthorized",
-		})
-		return
-	}
 
 	IDstr := c.Param("ID")
 	ID, err := strconv.Atoi(IDstr)
@@ -452,6 +466,14 @@ func GetQuestionLimitByID(c *gin.Context) {
 		})
 		return
 	}
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+	if !CanInCourse(jwtClaims, question.CourseID, models.PermViewAllQuestions) {
+		c.JSON(403, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
 	var questionTestScript models.QuestionTestScript
 	if err := db.Where("question_id = ?", ID).First(&questionTestScript).Error; err != nil {
@@ -590,18 +612,45 @@ type AddQuestionRequest struct {
 	StartTime   time.Time `json:"start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
 	EndTime     time.Time `json:"end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
 	IsActive    bool      `json:"is_active" example:"true"`
+	CourseID    *uint     `json:"course_id" example:"1"` // omit for a question open to every user
+	// Omit to reject pushes after end_time
+	LatePolicy *models.LatePolicy `json:"late_policy"`
+	// Which submission counts; omit to follow the exam's policy, or best
+	ScoringPolicy string `json:"scoring_policy" example:"best" enums:"best,last,last_before_deadline,top_k_average"`
+	ScoringTopK   int    `json:"scoring_top_k" example:"3"`
+	// Branch name patterns whose pushes are judged; omit for main and master
+	TriggerBranches []string `json:"trigger_branches" example:"main,release/*"`
+	// Only judge pushes that change files under these paths; omit to judge any change
+	PathFilters []string `json:"path_filters" example:"src,include/*.h"`
 	AddQuestionScript
 	AddQuestionLimit
 }
 
 type AddQuestionResponse struct {
-	Id          uint      `json:"id" example:"123"`
-	Title       string    `json:"title" validate:"required" example:"Question Title"`
-	Description string    `json:"description" validate:"required" example:"Question Description"`
-	GitRepoURL  string    `json:"git_repo_url" validate:"required" example:"user_name/repo_name"`
-	StartTime   time.Time `json:"start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
-	EndTime     time.Time `json:"end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
-	IsActive    bool      `json:"is_active" example:"true"`
+	Id              uint               `json:"id" example:"123"`
+	Title           string             `json:"title" validate:"required" example:"Question Title"`
+	Description     string             `json:"description" validate:"required" example:"Question Description"`
+	GitRepoURL      string             `json:"git_repo_url" validate:"required" example:"user_name/repo_name"`
+	StartTime       time.Time          `json:"start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	EndTime         time.Time          `json:"end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
+	IsActive        bool               `json:"is_active" example:"true"`
+	CourseID        *uint              `json:"course_id" example:"1"`
+	LatePolicy      *models.LatePolicy `json:"late_policy"`
+	ScoringPolicy   string             `json:"scoring_policy" example:"best"`
+	ScoringTopK     int                `json:"scoring_top_k" example:"3"`
+	TriggerBranches []string           `json:"trigger_branches" example:"main,release/*"`
+	PathFilters     []string           `json:"path_filters" example:"src,include/*.h"`
+}
+
+// normalizeLatePolicy validates a late policy from a request; an empty policy means none
+func normalizeLatePolicy(policy *models.LatePolicy) (*models.LatePolicy, error) {
+	if policy == nil || *policy == (models.LatePolicy{}) {
+		return nil, nil
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
 }
 
 // AddQuestion is a function to add a question
@@ -614,6 +663,7 @@ type AddQuestionResponse struct {
 // @Success		200		{object}	ResponseHTTP{data=AddQuestionResponse}
 // @Failure		400
 // @Failure		401
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Router			/api/questions/admin/question [post]
@@ -621,13 +671,6 @@ type AddQuestionResponse struct {
 func AddQuestion(c *gin.Context) {
 	db := database.DBConn
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(401, ResponseHTTP{
-			Success: false,
-			Message: "Unauthorized",
-		})
-		return
-	}
 
 	var req AddQuestionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -638,12 +681,41 @@ func AddQuestion(c *gin.Context) {
 		return
 	}
 
+	if !CanInCourse(jwtClaims, req.CourseID, models.PermManageQuestions) {
+		c.JSON(403, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
+
+	latePolicy, err := normalizeLatePolicy(req.LatePolicy)
+	if err == nil {
+		err = validateScoringPolicy(req.ScoringPolicy, req.ScoringTopK)
+	}
+	if err == nil {
+		err = models.ValidatePushFilters(req.TriggerBranches, req.PathFilters)
+	}
+	if err != nil {
+		c.JSON(400, ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	newquestion := models.Question{
-		Title:       req.Title,
-		Description: req.Description,
-		GitRepoURL:  req.GitRepoURL,
-		StartTime:   req.StartTime,
-		EndTime:     req.EndTime,
+		Title:           req.Title,
+		Description:     req.Description,
+		GitRepoURL:      req.GitRepoURL,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		CourseID:        req.CourseID,
+		LatePolicy:      latePolicy,
+		ScoringPolicy:   req.ScoringPolicy,
+		ScoringTopK:     req.ScoringTopK,
+		TriggerBranches: req.TriggerBranches,
+		PathFilters:     req.PathFilters,
 	}
 
 	var existingQuestion models.Question
@@ -718,13 +790,19 @@ func AddQuestion(c *gin.Context) {
 	}
 
 	response := AddQuestionResponse{
-		Id:          newquestion.ID,
-		Title:       newquestion.Title,
-		Description: newquestion.Description,
-		GitRepoURL:  newquestion.GitRepoURL,
-		StartTime:   newquestion.StartTime,
-		EndTime:     newquestion.EndTime,
-		IsActive:    req.IsActive,
+		Id:              newquestion.ID,
+		Title:           newquestion.Title,
+		Description:     newquestion.Description,
+		GitRepoURL:      newquestion.GitRepoURL,
+		StartTime:       newquestion.StartTime,
+		EndTime:         newquestion.EndTime,
+		IsActive:        req.IsActive,
+		CourseID:        newquestion.CourseID,
+		LatePolicy:      newquestion.LatePolicy,
+		ScoringPolicy:   newquestion.ScoringPolicy,
+		ScoringTopK:     newquestion.ScoringTopK,
+		TriggerBranches: newquestion.TriggerBranches,
+		PathFilters:     newquestion.PathFilters,
 	}
 
 	questionInfo := models.QuestionTestScript{
@@ -799,6 +877,16 @@ type PatchQuestionRequest struct {
 	StartTime   *time.Time `json:"start_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
 	EndTime     *time.Time `json:"end_time" example:"2006-01-02T15:04:05Z" time_format:"RFC3339"`
 	IsActive    *bool      `json:"is_active" example:"true"`
+	CourseID    *uint      `json:"course_id" example:"1"` // 0 moves the question out of its course
+	// Omit to leave unchanged; an empty object removes the policy
+	LatePolicy *models.LatePolicy `json:"late_policy"`
+	// Omit to leave unchanged; an empty string follows the exam's policy, or best
+	ScoringPolicy *string `json:"scoring_policy" example:"last"`
+	ScoringTopK   *int    `json:"scoring_top_k" example:"3"`
+	// Omit to leave unchanged; an empty list restores main and master
+	TriggerBranches *[]string `json:"trigger_branches" example:"main,release/*"`
+	// Omit to leave unchanged; an empty list judges any change
+	PathFilters *[]string `json:"path_filters" example:"src,include/*.h"`
 
 	CompileScript *string `json:"compile_script" example:"script example"`
 	ExecuteScript *string `json:"execute_script" example:"script example"`
@@ -824,6 +912,7 @@ type PatchQuestionRequest struct {
 // @Success		200		{object}	ResponseHTTP{data=models.Question}
 // @Failure		400
 // @Failure		401
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Router			/api/questions/admin/{ID}/question [patch]
@@ -831,13 +920,6 @@ type PatchQuestionRequest struct {
 func PatchQuestion(c *gin.Context) {
 	db := database.DBConn
 	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(401, ResponseHTTP{
-			Success: false,
-			Message: "Unauthorized",
-		})
-		return
-	}
 
 	IDstr := c.Param("ID")
 	ID, err := strconv.Atoi(IDstr)
@@ -857,6 +939,13 @@ func PatchQuestion(c *gin.Context) {
 		})
 		return
 	}
+	if !CanInCourse(jwtClaims, question.CourseID, models.PermManageQuestions) {
+		c.JSON(403, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
 	var questionscript models.QuestionTestScript
 	if err := db.Where("question_id = ?", ID).First(&questionscript).Error; err != nil {
@@ -876,6 +965,21 @@ func PatchQuestion(c *gin.Context) {
 		return
 	}
 
+	if updateQuestion.CourseID != nil {
+		// Moving a question requires managing questions in the target course as well
+		var target *uint
+		if *updateQuestion.CourseID != 0 {
+			target = updateQuestion.CourseID
+		}
+		if !CanInCourse(jwtClaims, target, models.PermManageQuestions) {
+			c.JSON(403, ResponseHTTP{
+				Success: false,
+				Message: "Permission denied",
+			})
+			return
+		}
+		question.CourseID = target
+	}
 	if updateQuestion.Title != nil {
 		question.Title = *updateQuestion.Title
 	}
@@ -894,6 +998,40 @@ func PatchQuestion(c *gin.Context) {
 	if updateQuestion.IsActive != nil {
 		question.IsActive = *updateQuestion.IsActive
 	}
+	if updateQuestion.LatePolicy != nil {
+		latePolicy, err := normalizeLatePolicy(updateQuestion.LatePolicy)
+		if err != nil {
+			c.JSON(400, ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+		question.LatePolicy = latePolicy
+	}
+	if updateQuestion.ScoringPolicy != nil {
+		question.ScoringPolicy = *updateQuestion.ScoringPolicy
+	}
+	if updateQuestion.ScoringTopK != nil {
+		question.ScoringTopK = *updateQuestion.ScoringTopK
+	}
+	if updateQuestion.TriggerBranches != nil {
+		question.TriggerBranches = *updateQuestion.TriggerBranches
+	}
+	if updateQuestion.PathFilters != nil {
+		question.PathFilters = *updateQuestion.PathFilters
+	}
+	err = validateScoringPolicy(question.ScoringPolicy, question.ScoringTopK)
+	if err == nil {
+		err = models.ValidatePushFilters(question.TriggerBranches, question.PathFilters)
+	}
+	if err != nil {
+		c.JSON(400, ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
 
 	if updateQuestion.CompileScript != nil {
 		questionscript.CompileScript = *updateQuestion.CompileScript
@@ -962,20 +1100,13 @@ func PatchQuestion(c *gin.Context) {
 // @Success		200		{object}	ResponseHTTP{data=models.Question}
 // @Failure		400
 // @Failure		401
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Router			/api/questions/admin/{ID}/question [delete]
 // @Security		BearerAuth
 func DeleteQuestion(c *gin.Context) {
 	db := database.DBConn
-	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(401, ResponseHTTP{
-			Success: false,
-			Message: "Unauthorized",
-		})
-		return
-	}
 
 	IDstr := c.Param("ID")
 	ID, err := strconv.Atoi(IDstr)
@@ -995,6 +1126,14 @@ func DeleteQuestion(c *gin.Context) {
 		})
 		return
 	}
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+	if !CanInCourse(jwtClaims, question.CourseID, models.PermDeleteQuestions) {
+		c.JSON(403, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
 	if err := db.Delete(&question).Error; err != nil {
 		c.JSON(503, ResponseHTTP{
@@ -1028,20 +1167,13 @@ type QuestionScripts struct {
 // @Success		200		{object}	ResponseHTTP{data=QuestionScripts}
 // @Failure		400
 // @Failure		401
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Router			/api/questions/admin/{ID}/scripts [get]
 // @Security		BearerAuth
 func GetQuestionScripts(c *gin.Context) {
 	db := database.DBConn
-	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(401, ResponseHTTP{
-			Success: false,
-			Message: "Unauthorized",
-		})
-		return
-	}
 
 	IDstr := c.Param("ID")
 	ID, err := strconv.Atoi(IDstr)
@@ -1053,6 +1185,23 @@ func GetQuestionScripts(c *gin.Context) {
 		return
 	}
 
+	var question models.Question
+	if err := db.Where("id = ?", ID).First(&question).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Question not found",
+		})
+		return
+	}
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+	if !CanInCourse(jwtClaims, question.CourseID, models.PermViewAllQuestions) {
+		c.JSON(403, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
+
 	var questionTestScript models.QuestionTestScript
 	if err := db.Where("question_id = ?", ID).First(&questionTestScript).Error; err != nil {
 		c.JSON(404, ResponseHTTP{
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"os"
 
+	"OJ-API/config"
 	"OJ-API/database"
+	"OJ-API/gitclone"
 	"OJ-API/models"
+	"OJ-API/sandbox"
 	"OJ-API/services"
+	"OJ-API/storage"
 	"OJ-API/utils"
 
 	"github.com/gin-gonic/gin"
@@ -28,20 +35,13 @@ type Sandbox struct {
 // @Success		200		{object}	ResponseHTTP{data=models.QuestionTestScript}
 // @Failure		400
 // @Failure		401
+// @Failure		403
 // @Failure		404
 // @Failure		503
 // @Router			/api/sandbox/admin/sandbox_cmd [post]
 // @Security		BearerAuth
 func PostSandboxCmd(c *gin.Context) {
 	db := database.DBConn
-	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(401, ResponseHTTP{
-			Success: false,
-			Message: "Unauthorized",
-		})
-		return
-	}
 
 	cmd := new(Sandbox)
 	if err := c.ShouldBindJSON(cmd); err != nil {
@@ -126,3 +126,403 @@ func GetSandboxStatus(c *gin.Context) {
 		Data:    status,
 	})
 }
+
+// GetSandboxRepoArchive godoc
+//
+// @Summary Download a repository snapshot for a sandbox
+// @Description Clone a repository at a specific commit on the API server and stream it as a tar.gz archive. Only registered sandboxes holding the SANDBOX_TOKEN can call this endpoint.
+// @Tags Sandbox
+// @Produce application/gzip
+// @Param repo query string true "Repository full name (owner/repo)"
+// @Param commit query string false "Commit hash, empty for HEAD"
+// @Param X-Sandbox-ID header string true "Sandbox ID"
+// @Success		200		{file}		application/gzip
+// @Failure		401		{object}	ResponseHTTP{}
+// @Failure		404		{object}	ResponseHTTP{}
+// @Failure		503		{object}	ResponseHTTP{}
+// @Router /api/sandbox/repo [get]
+// @Security BearerAuth
+func GetSandboxRepoArchive(c *gin.Context) {
+	db := database.DBConn
+
+	sandboxToken := config.Config("SANDBOX_TOKEN")
+	authHeader := c.GetHeader("Authorization")
+	const bearerPrefix = "Bearer "
+	if sandboxToken == "" || len(authHeader) <= len(bearerPrefix) ||
+		subtle.ConstantTimeCompare([]byte(authHeader[len(bearerPrefix):]), []byte(sandboxToken)) != 1 {
+		c.JSON(401, ResponseHTTP{
+			Success: false,
+			Message: "Unauthorized",
+		})
+		return
+	}
+	if !services.GetSandboxScheduler().IsRegistered(c.GetHeader("X-Sandbox-ID")) {
+		c.JSON(401, ResponseHTTP{
+			Success: false,
+			Message: "Sandbox is not registered",
+		})
+		return
+	}
+
+	repo := c.Query("repo")
+	commit := c.Query("commit")
+	if repo == "" {
+		c.JSON(400, ResponseHTTP{
+			Success: false,
+			Message: "repo is required",
+		})
+		return
+	}
+
+	// Only serve repositories that belong to a question or a user's copy of it
+	var gitUsername, gitToken string
+	var uqr models.UserQuestionRelation
+	if err := db.Preload("User").Where("git_user_repo_url = ?", repo).First(&uqr).Error; err == nil {
+		token, err := utils.GetToken(uqr.UserID)
+		if err != nil {
+			c.JSON(503, ResponseHTTP{
+				Success: false,
+				Message: "Failed to retrieve token",
+			})
+			return
+		}
+		gitUsername = uqr.User.UserName
+		gitToken = token
+	} else {
+		var question models.Question
+		if err := db.Where("git_repo_url = ?", repo).First(&question).Error; err != nil {
+			c.JSON(404, ResponseHTTP{
+				Success: false,
+				Message: fmt.Sprintf("Repository %s not found", repo),
+			})
+			return
+		}
+	}
+
+	codePath, err := gitclone.CloneRepository(repo, config.GetGiteaBaseURL()+"/"+repo, commit, gitUsername, gitToken)
+	if err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	defer os.RemoveAll(codePath)
+
+	c.Header("Content-Type", "application/gzip")
+	c.Status(200)
+	if err := gitclone.WriteArchive(codePath, c.Writer); err != nil {
+		utils.Errorf("Failed to stream %s to sandbox %s: %v", repo, c.GetHeader("X-Sandbox-ID"), err)
+	}
+}
+
+// ListSandboxInstances godoc
+//
+// @Summary List connected sandbox instances
+// @Description List every sandbox connected to any API replica with its capacity, load, labels, version and the jobs it is running
+// @Tags Sandbox
+// @Produce json
+// @Success		200		{object}	ResponseHTTP{data=[]services.SandboxInstanceInfo}
+// @Failure		401		{object}	ResponseHTTP{}
+// @Failure		403		{object}	ResponseHTTP{}
+// @Failure		503		{object}	ResponseHTTP{}
+// @Router /api/sandbox/admin/instances [get]
+// @Security BearerAuth
+func ListSandboxInstances(c *gin.Context) {
+
+	instances, err := services.GetSandboxScheduler().ListInstances()
+	if err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: fmt.Sprintf("Failed to list sandbox instances: %v", err),
+		})
+		return
+	}
+
+	c.JSON(200, ResponseHTTP{
+		Success: true,
+		Message: "",
+		Data:    instances,
+	})
+}
+
+// PostDrainSandbox godoc
+//
+// @Summary Drain a sandbox instance
+// @Description Stop dispatching new jobs to the sandbox while letting its running jobs finish
+// @Tags Sandbox
+// @Produce json
+// @Param id path string true "Sandbox ID"
+// @Success		200		{object}	ResponseHTTP{}
+// @Failure		401		{object}	ResponseHTTP{}
+// @Failure		403		{object}	ResponseHTTP{}
+// @Failure		404		{object}	ResponseHTTP{}
+// @Failure		503		{object}	ResponseHTTP{}
+// @Router /api/sandbox/admin/instances/{id}/drain [post]
+// @Security BearerAuth
+func PostDrainSandbox(c *gin.Context) {
+	sandboxAdminCommand(c, "drained", func(scheduler *services.SandboxScheduler, id string) error {
+		return scheduler.DrainSandbox(id, true)
+	})
+}
+
+// PostUndrainSandbox godoc
+//
+// @Summary Undrain a sandbox instance
+// @Description Dispatch new jobs to a drained sandbox again. A sandbox that is shutting down stays drained.
+// @Tags Sandbox
+// @Produce json
+// @Param id path string true "Sandbox ID"
+// @Success		200		{object}	ResponseHTTP{}
+// @Failure		401		{object}	ResponseHTTP{}
+// @Failure		403		{object}	ResponseHTTP{}
+// @Failure		404		{object}	ResponseHTTP{}
+// @Failure		503		{object}	ResponseHTTP{}
+// @Router /api/sandbox/admin/instances/{id}/undrain [post]
+// @Security BearerAuth
+func PostUndrainSandbox(c *gin.Context) {
+	sandboxAdminCommand(c, "undrained", func(scheduler *services.SandboxScheduler, id string) error {
+		return scheduler.DrainSandbox(id, false)
+	})
+}
+
+// PostCordonSandbox godoc
+//
+// @Summary Cordon a sandbox instance
+// @Description Mark the sandbox as unschedulable so no new jobs are dispatched to it
+// @Tags Sandbox
+// @Produce json
+// @Param id path string true "Sandbox ID"
+// @Success		200		{object}	ResponseHTTP{}
+// @Failure		401		{object}	ResponseHTTP{}
+// @Failure		403		{object}	ResponseHTTP{}
+// @Failure		404		{object}	ResponseHTTP{}
+// @Failure		503		{object}	ResponseHTTP{}
+// @Router /api/sandbox/admin/instances/{id}/cordon [post]
+// @Security BearerAuth
+func PostCordonSandbox(c *gin.Context) {
+	sandboxAdminCommand(c, "cordoned", func(scheduler *services.SandboxScheduler, id string) error {
+		return scheduler.CordonSandbox(id, true)
+	})
+}
+
+// PostUncordonSandbox godoc
+//
+// @Summary Uncordon a sandbox instance
+// @Description Mark the sandbox as schedulable again
+// @Tags Sandbox
+// @Produce json
+// @Param id path string true "Sandbox ID"
+// @Success		200		{object}	ResponseHTTP{}
+// @Failure		401		{object}	ResponseHTTP{}
+// @Failure		403		{object}	ResponseHTTP{}
+// @Failure		404		{object}	ResponseHTTP{}
+// @Failure		503		{object}	ResponseHTTP{}
+// @Router /api/sandbox/admin/instances/{id}/uncordon [post]
+// @Security BearerAuth
+func PostUncordonSandbox(c *gin.Context) {
+	sandboxAdminCommand(c, "uncordoned", func(scheduler *services.SandboxScheduler, id string) error {
+		return scheduler.CordonSandbox(id, false)
+	})
+}
+
+type SandboxShutdownRequest struct {
+	Reason string `json:"reason" example:"node maintenance"`
+}
+
+// PostShutdownSandbox godoc
+//
+// @Summary Gracefully shut down a sandbox instance
+// @Description Drain the sandbox and ask it to exit once its running jobs have finished
+// @Tags Sandbox
+// @Accept json
+// @Produce json
+// @Param id path string true "Sandbox ID"
+// @Param request body SandboxShutdownRequest false "Shutdown reason"
+// @Success		200		{object}	ResponseHTTP{}
+// @Failure		401		{object}	ResponseHTTP{}
+// @Failure		403		{object}	ResponseHTTP{}
+// @Failure		404		{object}	ResponseHTTP{}
+// @Failure		503		{object}	ResponseHTTP{}
+// @Router /api/sandbox/admin/instances/{id}/shutdown [post]
+// @Security BearerAuth
+func PostShutdownSandbox(c *gin.Context) {
+	var req SandboxShutdownRequest
+	// The body is optional
+	_ = c.ShouldBindJSON(&req)
+	if req.Reason == "" {
+		req.Reason = "requested by admin"
+	}
+
+	sandboxAdminCommand(c, "asked to shut down", func(scheduler *services.SandboxScheduler, id string) error {
+		return scheduler.ShutdownSandbox(id, req.Reason)
+	})
+}
+
+// sandboxAdminCommand checks admin permission and runs a fleet command against the sandbox in the path
+func sandboxAdminCommand(c *gin.Context, action string, command func(scheduler *services.SandboxScheduler, id string) error) {
+
+	id := c.Param("id")
+	if err := command(services.GetSandboxScheduler(), id); err != nil {
+		if errors.Is(err, services.ErrSandboxNotFound) {
+			c.JSON(404, ResponseHTTP{
+				Success: false,
+				Message: fmt.Sprintf("Sandbox %s not found", id),
+			})
+			return
+		}
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: fmt.Sprintf("Failed to send command to sandbox %s: %v", id, err),
+		})
+		return
+	}
+
+	c.JSON(200, ResponseHTTP{
+		Success: true,
+		Message: fmt.Sprintf("Sandbox %s %s", id, action),
+	})
+}
+
+// PostCancelJob godoc
+//
+// @Summary Cancel a judge job
+// @Description Cancel a submission that is waiting or being judged. Staff of the question's course can cancel any of its jobs, students only their own. A running job is killed on its sandbox and recorded as CANCELLED.
+// @Tags Sandbox
+// @Produce json
+// @Param UQT_ID path int true "User question table ID"
+// @Success		200		{object}	ResponseHTTP{}
+// @Failure		401		{object}	ResponseHTTP{}
+// @Failure		403		{object}	ResponseHTTP{}
+// @Failure		404		{object}	ResponseHTTP{}
+// @Failure		409		{object}	ResponseHTTP{}
+// @Failure		503		{object}	ResponseHTTP{}
+// @Router /api/sandbox/jobs/{UQT_ID}/cancel [post]
+// @Security BearerAuth
+func PostCancelJob(c *gin.Context) {
+	db := database.DBConn
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	var uqt models.UserQuestionTable
+	if err := db.Preload("UQR.Question").Where("id = ?", c.Param("UQT_ID")).First(&uqt).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Job not found",
+		})
+		return
+	}
+	if uqt.UQR.UserID != jwtClaims.UserID && !CanInCourse(jwtClaims, uqt.UQR.Question.CourseID, models.PermCancelJobs) {
+		c.JSON(401, ResponseHTTP{
+			Success: false,
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	// Only waiting (-3) and judging (-1) jobs can be cancelled
+	if uqt.Score != -3 && uqt.Score != -1 {
+		c.JSON(409, ResponseHTTP{
+			Success: false,
+			Message: "Job has already finished",
+		})
+		return
+	}
+
+	reason := fmt.Sprintf("Cancelled by %s", jwtClaims.Username)
+	dispatched, err := services.GetSandboxScheduler().CancelJob(uint64(uqt.ID), reason)
+	if err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: fmt.Sprintf("Failed to cancel job: %v", err),
+		})
+		return
+	}
+
+	// The job never reached a sandbox, so record the verdict here
+	if !dispatched {
+		db.Model(&uqt).Updates(models.UserQuestionTable{
+			Score:   -4,
+			Message: sandbox.NewErrorResult(sandbox.CANCELLED, "Cancelled", reason),
+		})
+		go services.ReportCommitStatus(uint64(uqt.ID))
+	}
+
+	c.JSON(200, ResponseHTTP{
+		Success: true,
+		Message: "Job cancelled",
+	})
+}
+
+// GetJudgeArtifacts godoc
+//
+// @Summary Download the artifacts of a judge run
+// @Description Download the archived raw output of a submission (compile logs, execute stdout/stderr, score script output, gtest JSON and isolate meta) as a tar.gz bundle. Requires the view artifacts permission in the question's course.
+// @Tags Sandbox
+// @Produce application/gzip
+// @Param UQT_ID path int true "User question table ID"
+// @Success		200		{file}		file
+// @Failure		401		{object}	ResponseHTTP{}
+// @Failure		403		{object}	ResponseHTTP{}
+// @Failure		404		{object}	ResponseHTTP{}
+// @Failure		503		{object}	ResponseHTTP{}
+// @Router /api/sandbox/jobs/{UQT_ID}/artifacts [get]
+// @Security BearerAuth
+func GetJudgeArtifacts(c *gin.Context) {
+	db := database.DBConn
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	var uqt models.UserQuestionTable
+	if err := db.Preload("UQR.Question").Where("id = ?", c.Param("UQT_ID")).First(&uqt).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Artifacts not found",
+		})
+		return
+	}
+	if !CanInCourse(jwtClaims, uqt.UQR.Question.CourseID, models.PermViewArtifacts) {
+		c.JSON(403, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
+
+	var artifact models.JudgeArtifact
+	if err := db.Where("user_question_table_id = ?", uqt.ID).First(&artifact).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Artifacts not found",
+		})
+		return
+	}
+
+	store, err := storage.Default()
+	if err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: fmt.Sprintf("Artifact storage unavailable: %v", err),
+		})
+		return
+	}
+	reader, err := store.Get(c.Request.Context(), artifact.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(404, ResponseHTTP{
+				Success: false,
+				Message: "Artifacts not found",
+			})
+			return
+		}
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: fmt.Sprintf("Failed to read artifacts: %v", err),
+		})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(200, artifact.Size, "application/gzip", reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="judge-%d.tar.gz"`, artifact.UserQuestionTableID),
+		"X-Content-SHA256":    artifact.SHA256,
+	})
+}
@@ -18,9 +18,11 @@ import (
 )
 
 type Score struct {
-	Score     float64   `json:"score" example:"100" validate:"required"`
-	Message   string    `json:"message" example:"Scored successfully" validate:"required"`
-	JudgeTime time.Time `json:"judge_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339" validate:"required"`
+	Score       float64   `json:"score" example:"90" validate:"required"`
+	RawScore    float64   `json:"raw_score" example:"100"`   // before the late penalty
+	LatePenalty float64   `json:"late_penalty" example:"10"` // percentage deducted for a late submission
+	Message     string    `json:"message" example:"Scored successfully" validate:"required"`
+	JudgeTime   time.Time `json:"judge_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339" validate:"required"`
 }
 
 type GetScoreResponseData struct {
@@ -31,7 +33,7 @@ type GetScoreResponseData struct {
 // GetScoreByRepo is a function to get a score by repo
 //
 //	@Summary		Get a score by repo
-//	@Description	Get a score by repo
+//	@Description	Get a score by repo. Test suites with summary visibility omit failure messages and hidden suites are omitted until the question stops accepting submissions, including late and extended exam submissions, unless the caller can view scores in the course.
 //	@Tags			Score
 //	@Accept			json
 //	@Produce		json
@@ -57,7 +59,7 @@ func GetScoreByRepo(c *gin.Context) {
 		})
 		return
 	}
-	if jwtClaims.Username != owner && !jwtClaims.IsAdmin {
+	if jwtClaims.Username != owner && !jwtClaims.Can(models.PermViewScores) {
 		c.JSON(401, ResponseHTTP{
 			Success: false,
 			Message: "Unauthorized",
@@ -113,12 +115,30 @@ func GetScoreByRepo(c *gin.Context) {
 		return
 	}
 
+	// Hidden test suites stay hidden from students until the question no longer accepts submissions
+	now := time.Now()
+	questions := make(map[uint]models.Question)
+	revealed := make(map[uint]bool)
 	var scores []Score
 	for _, score := range _scores {
+		question, ok := questions[score.UQR.QuestionID]
+		if !ok {
+			if err := db.First(&question, score.UQR.QuestionID).Error; err != nil {
+				c.JSON(503, ResponseHTTP{
+					Success: false,
+					Message: "Failed to get question by repo",
+				})
+				return
+			}
+			questions[score.UQR.QuestionID] = question
+			revealed[question.ID] = hiddenSuitesRevealed(question, now)
+		}
 		scores = append(scores, Score{
-			Score:     score.Score,
-			Message:   score.Message,
-			JudgeTime: score.CreatedAt,
+			Score:       score.Score,
+			RawScore:    score.RawScore,
+			LatePenalty: score.LatePenalty,
+			Message:     redactMessage(score.Message, question, jwtClaims, revealed[question.ID]),
+			JudgeTime:   score.CreatedAt,
 		})
 	}
 	c.JSON(200, ResponseHTTP{
@@ -134,7 +154,7 @@ func GetScoreByRepo(c *gin.Context) {
 // GetScore by UQR ID
 //
 //	@Summary		Get a score by UQR ID
-//	@Description	Get a score by UQR ID
+//	@Description	Get a score by UQR ID. Test suites with summary visibility omit failure messages and hidden suites are omitted until the question stops accepting submissions, including late and extended exam submissions, unless the caller can view scores in the course.
 //	@Tags			Score
 //	@Accept			json
 //	@Produce		json
@@ -237,12 +257,15 @@ func GetScoreByUQRID(c *gin.Context) {
 		return
 	}
 
+	revealHidden := hiddenSuitesRevealed(question, time.Now())
 	var scores []Score
 	for _, score := range _scores {
 		scores = append(scores, Score{
-			Score:     score.Score,
-			Message:   score.Message,
-			JudgeTime: score.CreatedAt,
+			Score:       score.Score,
+			RawScore:    score.RawScore,
+			LatePenalty: score.LatePenalty,
+			Message:     redactMessage(score.Message, question, jwtClaims, revealHidden),
+			JudgeTime:   score.CreatedAt,
 		})
 	}
 	c.JSON(200, ResponseHTTP{
@@ -258,7 +281,7 @@ func GetScoreByUQRID(c *gin.Context) {
 // GetScoreByQuestionID is a function to get a score by question ID
 //
 //	@Summary		Get a score by question ID
-//	@Description	Get a score by question ID
+//	@Description	Get a score by question ID. Test suites with summary visibility omit failure messages and hidden suites are omitted until the question stops accepting submissions, including late and extended exam submissions, unless the caller can view scores in the course.
 //	@Tags			Score
 //	@Accept			json
 //	@Produce		json
@@ -339,12 +362,15 @@ func GetScoreByQuestionID(c *gin.Context) {
 		})
 		return
 	}
+	revealHidden := hiddenSuitesRevealed(question, time.Now())
 	var scores []Score
 	for _, score := range _scores {
 		scores = append(scores, Score{
-			Score:     score.Score,
-			Message:   score.Message,
-			JudgeTime: score.CreatedAt,
+			Score:       score.Score,
+			RawScore:    score.RawScore,
+			LatePenalty: score.LatePenalty,
+			Message:     redactMessage(score.Message, question, jwtClaims, revealHidden),
+			JudgeTime:   score.CreatedAt,
 		})
 	}
 	if len(scores) == 0 {
@@ -375,7 +401,9 @@ func GetScoreByQuestionID(c *gin.Context) {
 //	@Success		200		{object}	ResponseHTTP{}
 //	@Failure		400
 //	@Failure		401
+//	@Failure		403
 //	@Failure		404
+//	@Failure		410
 //	@Failure		503
 //	@Router			/api/score/{question_id}/question/user_rescore [post]
 //	@Security		BearerAuth
@@ -400,11 +428,19 @@ func ReScoreUserQuestion(c *gin.Context) {
 		})
 		return
 	}
-	// Check question active time
-	if question.StartTime.After(time.Now().UTC()) || question.EndTime.Before(time.Now().UTC()) {
-		c.JSON(410, ResponseHTTP{
+	// Check question active time, or the user's exam window for exam questions
+	var user models.User
+	if err := db.First(&user, jwtClaims.UserID).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
 			Success: false,
-			Message: "Question is not in active time range",
+			Message: "User not found",
+		})
+		return
+	}
+	if _, status, message := checkQuestionSubmission(user, question); status != 0 {
+		c.JSON(status, ResponseHTTP{
+			Success: false,
+			Message: message,
 		})
 		return
 	}
@@ -420,12 +456,17 @@ func ReScoreUserQuestion(c *gin.Context) {
 		return
 	}
 
+	// Re-scoring judges the last pushed commit again, so it keeps that push's lateness
+	late := latestLateness(uqr.ID)
 	newScore := models.UserQuestionTable{
-		UQR:       uqr,
-		Score:     -3,
-		JudgeTime: time.Now().UTC(),
-		Commit:    "",
-		Message:   "Waiting for judging...",
+		UQR:         uqr,
+		Score:       -3,
+		JudgeTime:   time.Now().UTC(),
+		Commit:      "",
+		LatePenalty: late.Penalty,
+		Late:        late.Late,
+		Message:     "Waiting for judging...",
+		RequestID:   utils.RequestIDFromContext(c.Request.Context()),
 	}
 	if err := db.Create(&newScore).Error; err != nil {
 		c.JSON(503, ResponseHTTP{
@@ -440,6 +481,7 @@ func ReScoreUserQuestion(c *gin.Context) {
 		Message: "Re-scoring the question",
 	})
 
+	ctx := utils.WithJobID(c.Request.Context(), uint64(newScore.ID))
 	go func() {
 		// 獲取用戶 token
 		token, err := utils.GetToken(jwtClaims.UserID)
@@ -457,6 +499,7 @@ func ReScoreUserQuestion(c *gin.Context) {
 		// 使用 gRPC 客戶端添加任務，Git clone 將在沙箱端完成
 		clientManager := services.GetSandboxClientManager()
 		if err := clientManager.ReserveJob(
+			ctx,
 			question.GitRepoURL, // parentGitFullName
 			gitRepoURL,          // gitRepoURL
 			uqr.GitUserRepoURL,  // gitFullName
@@ -464,6 +507,7 @@ func ReScoreUserQuestion(c *gin.Context) {
 			jwtClaims.Username,  // gitUsername
 			token,               // gitToken
 			uint64(newScore.ID), // userQuestionTableID
+			models.SandboxJobPriorityNormal,
 		); err != nil {
 			db.Model(&newScore).Updates(models.UserQuestionTable{
 				Score:   -2,
@@ -473,6 +517,98 @@ func ReScoreUserQuestion(c *gin.Context) {
 	}()
 }
 
+// ReJudgeSubmission judges the commit of a submission again
+//
+//	@Summary		Re-judge a submission
+//	@Description	Judge the commit of a submission again in place. The submission keeps its ID, submission time and lateness, so scoring policies, ICPC attempts and frozen leaderboards see it where it was. Pushes of a commit that was already submitted are not judged again, so this is how a commit is forced through another judge.
+//	@Tags			Score
+//	@Produce		json
+//	@Param			UQT_ID	path		int	true	"User question table ID"
+//	@Success		200		{object}	ResponseHTTP{data=models.UserQuestionTable}
+//	@Failure		401
+//	@Failure		403		{object}	ResponseHTTP{}
+//	@Failure		404		{object}	ResponseHTTP{}
+//	@Failure		409		{object}	ResponseHTTP{}
+//	@Failure		503		{object}	ResponseHTTP{}
+//	@Router			/api/score/admin/uqt/{UQT_ID}/rejudge [post]
+//	@Security		BearerAuth
+func ReJudgeSubmission(c *gin.Context) {
+	db := database.DBConn
+
+	var uqt models.UserQuestionTable
+	if err := db.Preload("UQR").Where("id = ?", c.Param("UQT_ID")).First(&uqt).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Submission not found",
+		})
+		return
+	}
+	var question models.Question
+	if err := db.First(&question, uqt.UQR.QuestionID).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Question not found",
+		})
+		return
+	}
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+	if !CanInCourse(jwtClaims, question.CourseID, models.PermRescore) {
+		c.JSON(403, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
+	var user models.User
+	if err := db.First(&user, uqt.UQR.UserID).Error; err != nil {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+
+	// Reset the verdict unless the submission is still waiting (-3) or being judged (-1)
+	result := db.Model(&models.UserQuestionTable{}).
+		Where("id = ? AND score NOT IN ?", uqt.ID, []float64{-3, -1}).
+		Updates(map[string]interface{}{
+			"score":      -3,
+			"raw_score":  0,
+			"judge_time": time.Now().UTC(),
+			"message":    "Waiting for judging...",
+			"request_id": utils.RequestIDFromContext(c.Request.Context()),
+		})
+	if result.Error != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to reset the submission",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(409, ResponseHTTP{
+			Success: false,
+			Message: "Submission is already waiting or being judged",
+		})
+		return
+	}
+	if err := db.Preload("UQR").First(&uqt, uqt.ID).Error; err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to reload the submission",
+		})
+		return
+	}
+
+	c.JSON(200, ResponseHTTP{
+		Success: true,
+		Message: "Re-judging the submission",
+		Data:    uqt,
+	})
+
+	queueSubmission(c.Request.Context(), user, question, uqt.UQR, &uqt, models.SandboxJobPriorityNormal)
+}
+
 type TopScore struct {
 	QuestionID     int       `json:"question_id" example:"1" validate:"required"`
 	QuestionTitle  string    `json:"question_title" example:"Two Sum" validate:"required"`
@@ -513,15 +649,17 @@ func GetTopScore(c *gin.Context) {
 
 	var totalCount int64
 
-	subQuery := db.Model(&models.UserQuestionTable{}).
-		Select("DISTINCT ON (question_id) question_id, title question_title, git_user_repo_url, GREATEST(score, 0) score, message, judge_time").
-		Joins("JOIN user_question_relations UQR ON user_question_tables.uqr_id = UQR.id").
-		Joins("JOIN questions Q ON UQR.question_id = Q.id").
-		Where("Q.is_active = ?", true).
-		Where("question_id NOT IN (SELECT question_id FROM exam_questions)").
-		Where("UQR.user_id = ?", jwtClaims.UserID).
-		Order("question_id, score DESC").
-		Order("judge_time DESC")
+	// The score of each question follows its scoring policy, with the counted submission's result
+	counted := countedScores(db, nil, func(q *gorm.DB) *gorm.DB {
+		return q.Where("Q.is_active = ?", true).
+			Where("UQR.question_id NOT IN (SELECT question_id FROM exam_questions)").
+			Where("UQR.user_id = ?", jwtClaims.UserID)
+	})
+	subQuery := db.Table("(?) AS cs", counted).
+		Select("cs.question_id, Q.title question_title, cs.git_user_repo_url, cs.score, COALESCE(uqt.message, '') message, uqt.judge_time").
+		Joins("JOIN questions Q ON cs.question_id = Q.id").
+		Joins("LEFT JOIN user_question_tables uqt ON cs.submission_id = uqt.id").
+		Order("cs.question_id")
 
 	if err := db.Table("(?) AS sub", subQuery).
 		Count(&totalCount).Error; err != nil {
@@ -581,20 +719,13 @@ func GetTopScore(c *gin.Context) {
 //	@Success		200		{object}	ResponseHTTP{}
 //	@Failure		400
 //	@Failure		401
+//	@Failure		403
 //	@Failure		404
 //	@Failure		503
 //	@Router			/api/score/admin/{question_id}/question/rescore [post]
 //	@Security		BearerAuth
 func ReScoreQuestion(c *gin.Context) {
 	db := database.DBConn
-	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	if !jwtClaims.IsAdmin {
-		c.JSON(401, ResponseHTTP{
-			Success: false,
-			Message: "Unauthorized",
-		})
-		return
-	}
 
 	questionID := c.Param("question_id")
 	if questionID == "" {
@@ -613,6 +744,14 @@ func ReScoreQuestion(c *gin.Context) {
 		})
 		return
 	}
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+	if !CanInCourse(jwtClaims, question.CourseID, models.PermRescore) {
+		c.JSON(403, ResponseHTTP{
+			Success: false,
+			Message: "Permission denied",
+		})
+		return
+	}
 
 	var uqr []models.UserQuestionRelation
 	if err := db.Model(&models.UserQuestionRelation{}).
@@ -625,14 +764,19 @@ func ReScoreQuestion(c *gin.Context) {
 		return
 	}
 
+	requestID := utils.RequestIDFromContext(c.Request.Context())
 	newScores := []models.UserQuestionTable{}
 	for _, u := range uqr {
+		late := latestLateness(u.ID)
 		newScores = append(newScores, models.UserQuestionTable{
-			UQR:       u,
-			Score:     -3,
-			JudgeTime: time.Now().UTC(),
-			Commit:    "",
-			Message:   "Waiting for judging...",
+			UQR:         u,
+			Score:       -3,
+			JudgeTime:   time.Now().UTC(),
+			Commit:      "",
+			LatePenalty: late.Penalty,
+			Late:        late.Late,
+			Message:     "Waiting for judging...",
+			RequestID:   requestID,
 		})
 	}
 
@@ -649,6 +793,7 @@ func ReScoreQuestion(c *gin.Context) {
 		Message: "Re-scoring the question",
 	})
 
+	ctx := c.Request.Context()
 	go func() {
 		var wg sync.WaitGroup
 
@@ -675,6 +820,7 @@ func ReScoreQuestion(c *gin.Context) {
 				// 使用 gRPC 客戶端添加任務，Git clone 將在沙箱端完成
 				clientManager := services.GetSandboxClientManager()
 				if err := clientManager.ReserveJob(
+					utils.WithJobID(ctx, uint64(newScores[i].ID)),
 					question.GitRepoURL,     // parentGitFullName
 					gitRepoURL,              // gitRepoURL
 					gitFullName,             // gitFullName
@@ -682,6 +828,7 @@ func ReScoreQuestion(c *gin.Context) {
 					username,                // gitUsername
 					token,                   // gitToken
 					uint64(newScores[i].ID), // userQuestionTableID
+					models.SandboxJobPriorityLow,
 				); err != nil {
 					db.Model(&newScores[i]).Updates(models.UserQuestionTable{
 						Score:   -2,
@@ -695,6 +842,18 @@ func ReScoreQuestion(c *gin.Context) {
 	}()
 }
 
+// latestLateness returns the lateness of the user's latest submission to a question
+func latestLateness(uqrID uint) lateness {
+	var late lateness
+	database.DBConn.Model(&models.UserQuestionTable{}).
+		Select("late, late_penalty AS penalty").
+		Where("uqr_id = ?", uqrID).
+		Order("id DESC").
+		Limit(1).
+		Scan(&late)
+	return late
+}
+
 // GetAllScore is a function to get all scores for the user
 //
 //	@Summary		Get all scores for the user
@@ -797,12 +956,13 @@ type GetLeaderboardResponseData struct {
 // GetLeaderboard is a function to get the leaderboard
 //
 //	@Summary		Get the leaderboard
-//	@Description	Get the leaderboard (Optional Authentication if Admin will show all users, otherwise only public users)
+//	@Description	Get the leaderboard (Optional Authentication if Admin will show all users, otherwise only public users). Without course_id only questions outside any course are ranked; with course_id the course's questions are ranked among its students.
 //	@Tags			Score
 //	@Accept			json
 //	@Produce		json
-//	@Param			page	query	int	false	"page number of results to return (1-based)"
-//	@Param			limit	query	int	false	"page size of results. Default is 10."
+//	@Param			page		query	int	false	"page number of results to return (1-based)"
+//	@Param			limit		query	int	false	"page size of results. Default is 10."
+//	@Param			course_id	query	int	false	"rank the questions and students of this course"
 //	@Success		200	{object}	ResponseHTTP{data=GetLeaderboardResponseData}
 //	@Failure		400
 //	@Failure		401
@@ -813,7 +973,6 @@ type GetLeaderboardResponseData struct {
 func GetLeaderboard(c *gin.Context) {
 	// Check if user is authenticated (optional)
 	jwtClaims, _ := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
-	isAuthenticated := jwtClaims != nil
 
 	db := database.DBConn
 
@@ -821,34 +980,40 @@ func GetLeaderboard(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	offset := (page - 1) * limit
 
+	courseID, err := parseCourseID(c.Query("course_id"))
+	if err != nil {
+		c.JSON(400, ResponseHTTP{
+			Success: false,
+			Message: "Invalid course ID",
+		})
+		return
+	}
+	if !canViewCourse(jwtClaims, courseID) {
+		c.JSON(404, ResponseHTTP{
+			Success: false,
+			Message: "Course not found",
+		})
+		return
+	}
+
+	// Course leaderboards rank the course's students on its questions; the global one skips course questions
+	courseFilter := "Q.course_id IS NULL AND users.is_admin = FALSE"
+	var courseArgs []any
+	if courseID != nil {
+		courseFilter = "Q.course_id = ? AND UQR.user_id IN (SELECT user_id FROM course_enrollments WHERE course_id = ? AND role = ?)"
+		courseArgs = []any{*courseID, *courseID, models.RoleStudent}
+	}
+
 	// Get total count of users who have scores
 	var totalCount int64
-	subquery := db.Raw(`
-	SELECT DISTINCT ON (UQR.user_id, UQR.question_id)
-		UQR.user_id AS user_id,
-		UQR.question_id,
-		uqt.created_at,
-		GREATEST(uqt.score, 0) AS max_score
-	FROM user_question_tables uqt
-	JOIN user_question_relations UQR ON uqt.uqr_id = UQR.id
-	JOIN questions Q ON UQR.question_id = Q.id
-	JOIN users ON users.id = UQR.user_id
-	WHERE Q.is_active = TRUE
-		AND UQR.question_id NOT IN (SELECT question_id FROM exam_questions)
-		AND users.is_admin = FALSE
-	ORDER BY UQR.user_id, UQR.question_id, uqt.score DESC, uqt.created_at ASC
-	`)
-	subquery4count := db.Table("user_question_tables").
-		Select("UQR.user_id AS user_id, GREATEST(MAX(score), 0) AS max_score, UQR.question_id").
-		Joins("JOIN user_question_relations UQR ON user_question_tables.uqr_id = UQR.id").
-		Joins("JOIN questions Q ON UQR.question_id = Q.id").
-		Joins("JOIN users ON users.id = UQR.user_id").
-		Where("Q.is_active = ?", true).
-		Where("question_id NOT IN (SELECT question_id FROM exam_questions)").
-		Where("users.is_admin = false").
-		Group("UQR.user_id, UQR.question_id")
+	subquery := countedScores(db, nil, func(q *gorm.DB) *gorm.DB {
+		return q.Joins("JOIN users ON users.id = UQR.user_id").
+			Where("Q.is_active = ?", true).
+			Where("UQR.question_id NOT IN (SELECT question_id FROM exam_questions)").
+			Where(courseFilter, courseArgs...)
+	})
 
-	if err := db.Table("(?) AS t", subquery4count).
+	if err := db.Table("(?) AS t", subquery).
 		Select("COUNT(DISTINCT user_id)").
 		Scan(&totalCount).Error; err != nil {
 		c.JSON(503, ResponseHTTP{
@@ -869,9 +1034,9 @@ func GetLeaderboard(c *gin.Context) {
 	var usersWithScores []UserWithTotalScore
 	if err := db.Table("(?) AS subquery", subquery).
 		Joins("JOIN users ON users.id = subquery.user_id").
-		Select("users.id AS user_id, users.user_name, users.is_public, SUM(max_score) AS total_score").
+		Select("users.id AS user_id, users.user_name, users.is_public, SUM(subquery.score) AS total_score").
 		Group("users.id, users.user_name, users.is_public").
-		Order("total_score DESC, MAX(subquery.created_at) ASC").
+		Order("total_score DESC, MAX(subquery.submitted_at) ASC").
 		Offset(offset).
 		Limit(limit).
 		Find(&usersWithScores).Error; err != nil {
@@ -906,14 +1071,15 @@ func GetLeaderboard(c *gin.Context) {
 	}
 
 	var questionScores []QuestionScoreDetail
-	subquery2 := db.Model(&models.UserQuestionTable{}).
-		Select("UQR.user_id, UQR.question_id, GREATEST(MAX(user_question_tables.score), 0) AS score, MAX(UQR.git_user_repo_url) AS git_user_repo_url").
-		Joins("JOIN user_question_relations UQR ON user_question_tables.uqr_id = UQR.id").
-		Joins("JOIN questions Q ON UQR.question_id = Q.id").
-		Where("Q.is_active = ?", true).
-		Where("UQR.user_id IN ?", userIDs).
-		Where("question_id NOT IN (SELECT question_id FROM exam_questions)").
-		Group("UQR.user_id, UQR.question_id")
+	subquery2 := countedScores(db, nil, func(q *gorm.DB) *gorm.DB {
+		q = q.Where("Q.is_active = ?", true).
+			Where("UQR.user_id IN ?", userIDs).
+			Where("UQR.question_id NOT IN (SELECT question_id FROM exam_questions)")
+		if courseID != nil {
+			return q.Where("Q.course_id = ?", *courseID)
+		}
+		return q.Where("Q.course_id IS NULL")
+	})
 
 	if err := db.Table("(?) AS sq", subquery2).
 		Joins("JOIN questions ON questions.id = sq.question_id").
@@ -942,7 +1108,7 @@ func GetLeaderboard(c *gin.Context) {
 	for _, user := range usersWithScores {
 		userName := user.UserName
 		if !user.IsPublic {
-			if !isAuthenticated || !jwtClaims.IsAdmin {
+			if !CanInCourse(jwtClaims, courseID, models.PermViewScores) {
 				hash := utils.HashUserID(user.UserID)
 				userName = hash[len(hash)-9:]
 			} else {
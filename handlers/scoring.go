@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OJ-API/models"
+	"OJ-API/sandbox/grp_parser"
+	"OJ-API/utils"
+)
+
+// countedScores returns a subquery with the score that counts for each user on each question under
+// the question's scoring policy, falling back to the exam's policy and then to best. Every endpoint
+// ranking or exporting scores goes through it, so the policies are applied the same way everywhere.
+//
+// Columns: uqr_id, user_id, question_id, git_user_repo_url, score, submission_id, submitted_at.
+// submission_id and submitted_at identify the submission the score comes from (the best one for
+// top_k_average); they are NULL when no submission counts. Pending and failed judgements count as 0.
+//
+// exam selects the exam whose policy applies; when nil, the policy of an exam containing the question
+// is used. scope filters the submissions considered, joined as UQR (user_question_relations) and
+// Q (questions).
+func countedScores(db *gorm.DB, exam *models.Exam, scope func(*gorm.DB) *gorm.DB) *gorm.DB {
+	examPolicy := "(SELECT E.scoring_policy FROM exams E JOIN exam_questions EQP ON EQP.exam_id = E.id " +
+		"WHERE EQP.question_id = Q.id AND E.scoring_policy <> '' ORDER BY E.id LIMIT 1)"
+	examTopK := "(SELECT E.scoring_top_k FROM exams E JOIN exam_questions EQP ON EQP.exam_id = E.id " +
+		"WHERE EQP.question_id = Q.id AND E.scoring_policy <> '' ORDER BY E.id LIMIT 1)"
+	var args []any
+	if exam != nil {
+		examPolicy, examTopK = "NULLIF(?, '')", "?"
+		args = []any{exam.ScoringPolicy, exam.ScoringTopK}
+	}
+
+	ranked := db.Table("user_question_tables AS uqt").
+		Select(`UQR.id AS uqr_id, UQR.user_id, UQR.question_id, UQR.git_user_repo_url,
+			uqt.id, uqt.score, uqt.late, uqt.created_at,
+			COALESCE(NULLIF(Q.scoring_policy, ''), `+examPolicy+`, '`+models.ScoringBest+`') AS policy,
+			GREATEST(CASE WHEN Q.scoring_policy <> '' THEN Q.scoring_top_k ELSE COALESCE(`+examTopK+`, 0) END, 1) AS top_k,
+			ROW_NUMBER() OVER (PARTITION BY uqt.uqr_id ORDER BY uqt.score DESC, uqt.created_at ASC) AS best_rank,
+			ROW_NUMBER() OVER (PARTITION BY uqt.uqr_id ORDER BY (uqt.score >= 0) DESC, uqt.created_at DESC) AS last_rank,
+			ROW_NUMBER() OVER (PARTITION BY uqt.uqr_id ORDER BY (uqt.score >= 0 AND NOT uqt.late) DESC, uqt.created_at DESC) AS on_time_rank`,
+			args...).
+		Joins("JOIN user_question_relations UQR ON uqt.uqr_id = UQR.id").
+		Joins("JOIN questions Q ON UQR.question_id = Q.id")
+	if scope != nil {
+		ranked = scope(ranked)
+	}
+
+	// 依各題的計分方式挑出計分的那次繳交
+	counted := func(column string) string {
+		return `CASE policy
+			WHEN '` + models.ScoringLast + `' THEN MAX(` + column + `) FILTER (WHERE last_rank = 1 AND score >= 0)
+			WHEN '` + models.ScoringLastBeforeDeadline + `' THEN MAX(` + column + `) FILTER (WHERE on_time_rank = 1 AND score >= 0 AND NOT late)
+			ELSE MAX(` + column + `) FILTER (WHERE best_rank = 1 AND score >= 0)
+		END`
+	}
+	return db.Table("(?) AS ranked", ranked).
+		Select(`uqr_id, user_id, question_id, git_user_repo_url,
+			COALESCE(CASE policy
+				WHEN '` + models.ScoringTopKAverage + `' THEN AVG(score) FILTER (WHERE best_rank <= top_k AND score >= 0)
+				ELSE ` + counted("score") + `
+			END, 0) AS score,
+			` + counted("id") + ` AS submission_id,
+			` + counted("created_at") + ` AS submitted_at`).
+		Group("uqr_id, user_id, question_id, git_user_repo_url, policy, top_k")
+}
+
+// fullScore is the score of a submission that passes every test
+const fullScore = 100
+
+// icpcResults returns a subquery with each user's ICPC result on each of the exam's questions they
+// submitted to: the time of their first full-score submission and the judged submissions before it.
+// Pending and failed judgements are not attempts.
+//
+// Columns: user_id, question_id, solved_at (NULL if unsolved), rejected, solve_minutes (minutes from
+// the user's start of the exam to solved_at). scope filters the submissions considered, joined as
+// UQR (user_question_relations) and Q (questions).
+func icpcResults(db *gorm.DB, exam models.Exam, scope func(*gorm.DB) *gorm.DB) *gorm.DB {
+	// 作答起點：個人補考時間或計時考試的開始作答時間
+	start := "COALESCE(EUO.start_time, ?)"
+	if exam.DurationMinutes > 0 {
+		start = "COALESCE(ES.started_at, EUO.start_time, ?)"
+	}
+	attempts := db.Table("user_question_tables AS uqt").
+		Select(`UQR.user_id, UQR.question_id, uqt.created_at, `+start+` AS started_at,
+			MIN(uqt.created_at) FILTER (WHERE uqt.score >= ?) OVER (PARTITION BY uqt.uqr_id) AS solved_at`,
+			exam.StartTime, fullScore).
+		Joins("JOIN user_question_relations UQR ON uqt.uqr_id = UQR.id").
+		Joins("JOIN questions Q ON UQR.question_id = Q.id").
+		Joins("LEFT JOIN exam_user_overrides EUO ON EUO.exam_id = ? AND EUO.user_id = UQR.user_id", exam.ID).
+		Joins("LEFT JOIN exam_sessions ES ON ES.exam_id = ? AND ES.user_id = UQR.user_id", exam.ID).
+		Where("uqt.score >= 0")
+	if scope != nil {
+		attempts = scope(attempts)
+	}
+
+	return db.Table("(?) AS attempts", attempts).
+		Select(`user_id, question_id, solved_at,
+			COUNT(*) FILTER (WHERE solved_at IS NULL OR created_at < solved_at) AS rejected,
+			GREATEST(FLOOR(EXTRACT(EPOCH FROM solved_at - started_at) / 60), 0)::int AS solve_minutes`).
+		Group("user_id, question_id, solved_at, started_at")
+}
+
+// validateScoringPolicy checks a scoring policy and its K from a request
+func validateScoringPolicy(policy string, topK int) error {
+	if !models.ValidScoringPolicy(policy) {
+		return errors.New("scoring_policy must be best, last, last_before_deadline or top_k_average")
+	}
+	if topK < 0 {
+		return errors.New("scoring_top_k must not be negative")
+	}
+	if policy == models.ScoringTopKAverage && topK == 0 {
+		return errors.New("scoring_top_k is required with top_k_average")
+	}
+	return nil
+}
+
+// hiddenSuitesRevealed reports whether students may see the question's hidden test suites at now:
+// only once no submission can be accepted any more, late or in an extended exam window. Questions
+// without a deadline never reveal them.
+func hiddenSuitesRevealed(question models.Question, now time.Time) bool {
+	last, ok := lastSubmissionTime(question)
+	return ok && now.After(last)
+}
+
+// redactMessage returns a judge message as the caller may see it under the visibility of each test
+// suite in the score map. Users who can view scores in the question's course see every suite; others
+// see hidden suites only when revealHidden is set, see hiddenSuitesRevealed.
+func redactMessage(message string, question models.Question, jwtClaims *utils.JWTClaims, revealHidden bool) string {
+	if CanInCourse(jwtClaims, question.CourseID, models.PermViewScores) {
+		return message
+	}
+	var all grp_parser.InputJSON
+	if err := json.Unmarshal([]byte(message), &all); err != nil {
+		return message
+	}
+
+	restricted := false
+	for _, suite := range all.TestSuites {
+		if suite.Visibility == grp_parser.VisibilitySummary || suite.Visibility == grp_parser.VisibilityHidden {
+			restricted = true
+			break
+		}
+	}
+	if !restricted {
+		return message
+	}
+
+	redacted, err := json.MarshalIndent(grp_parser.Redact(all, revealHidden), "", "  ")
+	if err != nil {
+		return message
+	}
+	return string(redacted)
+}
@@ -79,13 +79,15 @@ func PostUserIsPublic(c *gin.Context) {
 }
 
 type GetUserData struct {
-	ID       uint   `json:"id"`
-	UserName string `json:"user_name"`
-	Hash     string `json:"hash"`
-	Enable   bool   `json:"enable"`
-	Email    string `json:"email"`
-	IsPublic bool   `json:"is_public"`
-	IsAdmin  bool   `json:"is_admin"`
+	ID          uint                `json:"id"`
+	UserName    string              `json:"user_name"`
+	Hash        string              `json:"hash"`
+	Enable      bool                `json:"enable"`
+	Email       string              `json:"email"`
+	IsPublic    bool                `json:"is_public"`
+	IsAdmin     bool                `json:"is_admin"`
+	Role        models.Role         `json:"role" example:"student"`
+	Permissions []models.Permission `json:"permissions"`
 }
 
 // Get User Info
@@ -122,13 +124,15 @@ func GetUser(c *gin.Context) {
 		Success: true,
 		Message: "User info retrieved successfully",
 		Data: GetUserData{
-			ID:       user.ID,
-			UserName: user.UserName,
-			Hash:     hash[len(hash)-9:],
-			Enable:   user.Enable,
-			Email:    user.Email,
-			IsPublic: user.IsPublic,
-			IsAdmin:  user.IsAdmin,
+			ID:          user.ID,
+			UserName:    user.UserName,
+			Hash:        hash[len(hash)-9:],
+			Enable:      user.Enable,
+			Email:       user.Email,
+			IsPublic:    user.IsPublic,
+			IsAdmin:     user.IsAdmin,
+			Role:        user.Role,
+			Permissions: models.RolePermissions[user.Role],
 		},
 	})
 }
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"OJ-API/config"
 	"OJ-API/database"
@@ -14,41 +21,131 @@ import (
 	"OJ-API/utils"
 )
 
+// branchRefPrefix marks pushed refs that are branches
+const branchRefPrefix = "refs/heads/"
+
 type WebhookPayload struct {
-	Ref        string           `json:"ref"`
-	Before     string           `json:"before"`
-	After      string           `json:"after"`
-	CompareURL string           `json:"compare_url"`
-	Commits    []gitea.Commit   `json:"commits"`
-	Repository gitea.Repository `json:"repository"`
-	Pusher     gitea.User       `json:"pusher"`
-	Sender     gitea.User       `json:"sender"`
+	Ref          string                `json:"ref"`
+	Before       string                `json:"before"`
+	After        string                `json:"after"`
+	CompareURL   string                `json:"compare_url"`
+	Commits      []gitea.PayloadCommit `json:"commits"`
+	TotalCommits int                   `json:"total_commits"` // Gitea truncates commits in large pushes
+	Repository   gitea.Repository      `json:"repository"`
+	Pusher       gitea.User            `json:"pusher"`
+	Sender       gitea.User            `json:"sender"`
+}
+
+// WebhookSubmission identifies the submission or practice run an earlier delivery created. Gitea keeps
+// webhook responses in the repository's delivery history, so it carries no judge message.
+type WebhookSubmission struct {
+	ID       uint    `json:"id" example:"1"`
+	Practice bool    `json:"practice" example:"false"`
+	Score    float64 `json:"score" example:"100"`
+	Status   string  `json:"status" example:"judged" enums:"waiting,judging,system_error,cancelled,judged"`
+}
+
+// newWebhookSubmission summarizes a submission or practice run by its score
+func newWebhookSubmission(id uint, practice bool, score float64) WebhookSubmission {
+	status := "judged"
+	switch score {
+	case -1:
+		status = "judging"
+	case -2:
+		status = "system_error"
+	case -3:
+		status = "waiting"
+	case -4:
+		status = "cancelled"
+	}
+	return WebhookSubmission{
+		ID:       id,
+		Practice: practice,
+		Score:    score,
+		Status:   status,
+	}
+}
+
+type GetSkippedPushesResponseData struct {
+	PushesCount int                  `json:"pushes_count" validate:"required"`
+	Pushes      []models.SkippedPush `json:"pushes" validate:"required"`
+}
+
+// pushSkipReason explains why a push is not judged, or returns an empty string if it is.
+// Practice branches are never filtered by the question's trigger branches or path filters.
+func pushSkipReason(payload WebhookPayload, question models.Question) string {
+	if !strings.HasPrefix(payload.Ref, branchRefPrefix) {
+		return "Only branch pushes are judged, got " + payload.Ref
+	}
+	if strings.Trim(payload.After, "0") == "" {
+		return "Branch deleted"
+	}
+	if strings.HasPrefix(payload.Ref, practiceRefPrefix) {
+		return ""
+	}
+
+	branch := strings.TrimPrefix(payload.Ref, branchRefPrefix)
+	if !question.TriggersBranch(branch) {
+		branches := question.TriggerBranches
+		if len(branches) == 0 {
+			branches = models.DefaultTriggerBranches
+		}
+		return fmt.Sprintf("Branch %s is not judged, push to %s instead", branch, strings.Join(branches, ", "))
+	}
+
+	// 提交列表被截斷時無法確定所有變更的檔案，因此照常評測
+	if len(question.PathFilters) == 0 || len(payload.Commits) == 0 || payload.TotalCommits > len(payload.Commits) {
+		return ""
+	}
+	var files []string
+	for _, commit := range payload.Commits {
+		files = append(files, commit.Added...)
+		files = append(files, commit.Removed...)
+		files = append(files, commit.Modified...)
+	}
+	if !question.MatchesPaths(files) {
+		return "No changes under " + strings.Join(question.PathFilters, ", ")
+	}
+	return ""
 }
 
 // PostGiteaHook is a function to receive Gitea hook
 //
 //	@Summary		Receive Gitea hook
-//	@Description	Receive Gitea hook
+//	@Description	Receive Gitea hook. Only pushes to the question's trigger branches (main and master by default) that change files under its path filters are judged; pushes to practice/* branches start practice runs. Other pushes are answered with the reason and recorded as skipped. Exam restrictions and late policies apply to the repository owner, whoever pushed. Events must be signed by Gitea with the repository's webhook secret. A repeated X-Gitea-Delivery or an already submitted commit returns the ID, score and status of the existing submission instead of judging again.
 //	@Tags			Gitea
 //	@Accept			json
 //	@Produce		json
-//	@Param			hook	body		WebhookPayload	true	"Gitea Hook"
-//	@Success		200		{object}	ResponseHTTP{type=WebhookPayload}
-//	@Failure		403		{object}	ResponseHTTP{}
-//	@Failure		410		{object}	ResponseHTTP{}
-//	@Failure		503		{object}	ResponseHTTP{}
+//	@Param			hook				body		WebhookPayload	true	"Gitea Hook"
+//	@Param			X-Gitea-Signature	header		string			true	"hex HMAC-SHA256 of the body keyed with the repository's webhook secret"
+//	@Param			X-Gitea-Delivery	header		string			false	"delivery ID, the same across retries"
+//	@Success		200					{object}	ResponseHTTP{type=WebhookPayload}
+//	@Failure		401					{object}	ResponseHTTP{}
+//	@Failure		403					{object}	ResponseHTTP{}
+//	@Failure		410					{object}	ResponseHTTP{}
+//	@Failure		503					{object}	ResponseHTTP{}
 //	@Router			/api/gitea [post]
 func PostGiteaHook(c *gin.Context) {
+	defer func() {
+		services.WebhookRequests.WithLabelValues(strconv.Itoa(c.Writer.Status())).Inc()
+	}()
 	db := database.DBConn
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to read hook",
+		})
+		return
+	}
 	var payload WebhookPayload
-	if err := c.ShouldBindJSON(&payload); err != nil {
+	if err := json.Unmarshal(body, &payload); err != nil {
 		c.JSON(503, ResponseHTTP{
 			Success: false,
 			Message: "Failed to parse hook",
 		})
 		return
 	}
-	utils.Debugf("Received hook: %+v", payload)
 
 	var existingUserQuestionRelation models.UserQuestionRelation
 	if err := db.Where(&models.UserQuestionRelation{
@@ -61,6 +158,36 @@ func PostGiteaHook(c *gin.Context) {
 		return
 	}
 
+	// Push events are signed with the repository's secret; unsigned or forged events are rejected
+	// Repositories whose hook has not been given a secret yet are rejected until it is set up
+	secret, err := utils.WebhookSecret(existingUserQuestionRelation)
+	if errors.Is(err, utils.ErrNoWebhookSecret) {
+		c.JSON(401, ResponseHTTP{
+			Success: false,
+			Message: "Repository hook has no secret yet",
+		})
+		return
+	}
+	if err != nil || !utils.ValidWebhookSignature(body, c.GetHeader("X-Gitea-Signature"), secret) {
+		c.JSON(401, ResponseHTTP{
+			Success: false,
+			Message: "Invalid webhook signature",
+		})
+		return
+	}
+	utils.Debugf("Received hook: %+v", payload)
+
+	// Gitea retries deliveries; a delivery that was already handled returns what it created
+	deliveryID := c.GetHeader("X-Gitea-Delivery")
+	if existing, ok := receivedDelivery(existingUserQuestionRelation.ID, deliveryID); ok {
+		c.JSON(200, ResponseHTTP{
+			Success: true,
+			Message: "Delivery already received",
+			Data:    existing,
+		})
+		return
+	}
+
 	var existingQuestion models.Question
 	if err := db.Where(&models.Question{ID: existingUserQuestionRelation.QuestionID, IsActive: true}).First(&existingQuestion).Error; err != nil {
 		c.JSON(503, ResponseHTTP{
@@ -70,40 +197,109 @@ func PostGiteaHook(c *gin.Context) {
 		return
 	}
 
-	// Check if current time is within the allowed testing period
-	now := time.Now().UTC()
-	if !existingQuestion.StartTime.IsZero() && now.Before(existingQuestion.StartTime) {
-		c.JSON(403, ResponseHTTP{
+	// The repository owner is judged, whoever pushed; collaborators must not inherit their own access
+	var existingUser models.User
+	if err := db.First(&existingUser, existingUserQuestionRelation.UserID).Error; err != nil {
+		c.JSON(503, ResponseHTTP{
 			Success: false,
-			Message: "Testing period has not started yet",
+			Message: "No user found for this user-question relation",
+		})
+		return
+	}
+
+	if reason := pushSkipReason(payload, existingQuestion); reason != "" {
+		// A redelivery of the same push is recorded once
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.SkippedPush{
+			UQRID:      existingUserQuestionRelation.ID,
+			Ref:        payload.Ref,
+			Commit:     payload.After,
+			Reason:     reason,
+			RequestID:  utils.RequestIDFromContext(c.Request.Context()),
+			DeliveryID: deliveryID,
+		}).Error; err != nil {
+			utils.Ctx(c.Request.Context()).Errorf("Failed to record skipped push: %v", err)
+		}
+		c.JSON(200, ResponseHTTP{
+			Success: true,
+			Message: "Successfully received hook, push skipped: " + reason,
+			Data:    payload,
 		})
 		return
 	}
-	if !existingQuestion.EndTime.IsZero() && now.After(existingQuestion.EndTime) {
-		c.JSON(410, ResponseHTTP{
+
+	// Check if current time is within the allowed testing period; exam questions use the
+	// owner's exam window and access restrictions instead of the question's own period.
+	// Late pushes accepted by a late policy are judged normally and penalized afterwards.
+	late, status, message := checkQuestionSubmission(existingUser, existingQuestion)
+	if status != 0 {
+		c.JSON(status, ResponseHTTP{
 			Success: false,
-			Message: "Testing period has ended",
+			Message: message,
 		})
 		return
 	}
 
-	var existingUser models.User
-	if err := db.Where(&models.User{UserName: payload.Pusher.UserName}).First(&existingUser).Error; err != nil {
-		existingUser = models.User{
-			UserName: payload.Pusher.UserName,
-			Email:    payload.Pusher.Email,
+	// Pushes to practice/* branches run the public test suites without a graded submission
+	if strings.HasPrefix(payload.Ref, practiceRefPrefix) {
+		if _, err := startPracticeRun(c.Request.Context(), existingUser, existingQuestion, existingUserQuestionRelation, payload.After, payload.Ref, deliveryID); err != nil {
+			if existing, ok := receivedDelivery(existingUserQuestionRelation.ID, deliveryID); ok {
+				c.JSON(200, ResponseHTTP{
+					Success: true,
+					Message: "Delivery already received",
+					Data:    existing,
+				})
+				return
+			}
+			c.JSON(503, ResponseHTTP{
+				Success: false,
+				Message: "Failed to create practice run",
+			})
+			return
 		}
-		db.Create(&existingUser)
+		c.JSON(200, ResponseHTTP{
+			Success: true,
+			Message: "Successfully received hook, practice run queued",
+			Data:    payload,
+		})
+		return
+	}
+
+	// A commit is judged once; pushing it again returns its submission. Cancelled and failed
+	// judges do not count, so pushing again retries them.
+	var duplicate models.UserQuestionTable
+	if err := db.Where("uqr_id = ? AND commit = ? AND score NOT IN ?", existingUserQuestionRelation.ID, payload.After, []float64{-4, -2}).
+		Order("id DESC").
+		Limit(1).
+		Find(&duplicate).Error; err == nil && duplicate.ID != 0 {
+		c.JSON(200, ResponseHTTP{
+			Success: true,
+			Message: "Commit already submitted",
+			Data:    newWebhookSubmission(duplicate.ID, false, duplicate.Score),
+		})
+		return
 	}
 
 	newScore := models.UserQuestionTable{
-		UQR:       existingUserQuestionRelation,
-		Score:     -3,
-		JudgeTime: time.Now().UTC(),
-		Commit:    payload.After,
-		Message:   "Waiting for judging...",
+		UQR:         existingUserQuestionRelation,
+		Score:       -3,
+		JudgeTime:   time.Now().UTC(),
+		Commit:      payload.After,
+		LatePenalty: late.Penalty,
+		Late:        late.Late,
+		Message:     "Waiting for judging...",
+		RequestID:   utils.RequestIDFromContext(c.Request.Context()),
+		DeliveryID:  deliveryID,
 	}
 	if err := db.Create(&newScore).Error; err != nil {
+		// A concurrent retry of the same delivery won the unique index
+		if existing, ok := receivedDelivery(existingUserQuestionRelation.ID, deliveryID); ok {
+			c.JSON(200, ResponseHTTP{
+				Success: true,
+				Message: "Delivery already received",
+				Data:    existing,
+			})
+			return
+		}
 		c.JSON(503, ResponseHTTP{
 			Success: false,
 			Message: "Failed to create new score entry",
@@ -117,12 +313,37 @@ func PostGiteaHook(c *gin.Context) {
 		Data:    payload,
 	})
 
+	queueSubmission(c.Request.Context(), existingUser, existingQuestion, existingUserQuestionRelation, &newScore, models.SandboxJobPriorityNormal)
+}
+
+// receivedDelivery returns the submission or practice run created by an earlier webhook delivery with the same ID
+func receivedDelivery(uqrID uint, deliveryID string) (WebhookSubmission, bool) {
+	if deliveryID == "" {
+		return WebhookSubmission{}, false
+	}
+	db := database.DBConn
+	var uqt models.UserQuestionTable
+	if err := db.Where("uqr_id = ? AND delivery_id = ?", uqrID, deliveryID).Limit(1).Find(&uqt).Error; err == nil && uqt.ID != 0 {
+		return newWebhookSubmission(uqt.ID, false, uqt.Score), true
+	}
+	var run models.PracticeRun
+	if err := db.Where("uqr_id = ? AND delivery_id = ?", uqrID, deliveryID).Limit(1).Find(&run).Error; err == nil && run.ID != 0 {
+		return newWebhookSubmission(run.ID, true, run.Score), true
+	}
+	return WebhookSubmission{}, false
+}
+
+// queueSubmission queues a graded submission of the user's repository on the sandboxes.
+// The submission's commit is judged, or HEAD if it has none.
+func queueSubmission(ctx context.Context, user models.User, question models.Question, uqr models.UserQuestionRelation, uqt *models.UserQuestionTable, priority int) {
+	db := database.DBConn
+	ctx = utils.WithJobID(ctx, uint64(uqt.ID))
 	go func() {
 		// 獲取用戶 token
-		token, err := utils.GetToken(existingUser.ID)
+		token, err := utils.GetToken(user.ID)
 		if err != nil {
-			utils.Errorf("Failed to get token: %v", err)
-			db.Model(&newScore).Updates(models.UserQuestionTable{
+			utils.Ctx(ctx).Errorf("Failed to get token: %v", err)
+			db.Model(uqt).Updates(models.UserQuestionTable{
 				Score:   -2,
 				Message: fmt.Sprintf("Failed to get token: %v", err),
 			})
@@ -130,23 +351,85 @@ func PostGiteaHook(c *gin.Context) {
 		}
 
 		// 構建 Git 倉庫 URL
-		gitRepoURL := config.GetGiteaBaseURL() + "/" + payload.Repository.FullName
+		gitRepoURL := config.GetGiteaBaseURL() + "/" + uqr.GitUserRepoURL
 
 		// 使用 gRPC 客戶端添加任務，Git clone 將在沙箱端完成
 		clientManager := services.GetSandboxClientManager()
 		if err := clientManager.ReserveJob(
-			existingQuestion.GitRepoURL, // parentGitFullName
-			gitRepoURL,                  // gitRepoURL
-			payload.Repository.FullName, // gitFullName
-			payload.After,               // gitAfterHash
-			existingUser.UserName,       // gitUsername
-			token,                       // gitToken
-			uint64(newScore.ID),         // userQuestionTableID
+			ctx,
+			question.GitRepoURL, // parentGitFullName
+			gitRepoURL,          // gitRepoURL
+			uqr.GitUserRepoURL,  // gitFullName
+			uqt.Commit,          // gitAfterHash (空字符串表示使用 HEAD)
+			user.UserName,       // gitUsername
+			token,               // gitToken
+			uint64(uqt.ID),      // userQuestionTableID
+			priority,
 		); err != nil {
-			db.Model(&newScore).Updates(models.UserQuestionTable{
+			utils.Ctx(ctx).Errorf("Failed to queue job: %v", err)
+			db.Model(uqt).Updates(models.UserQuestionTable{
 				Score:   -2,
 				Message: fmt.Sprintf("Failed to queue job: %v", err),
 			})
 		}
 	}()
 }
+
+// GetSkippedPushes lists the user's pushes of a question that were not judged
+//
+//	@Summary		List skipped pushes
+//	@Description	List the user's pushes of a question that were received but not judged, newest first, with the reason they were skipped
+//	@Tags			Gitea
+//	@Produce		json
+//	@Param			question_id	path		int	true	"question ID"
+//	@Param			page		query		int	false	"page number of results to return (1-based)"
+//	@Param			limit		query		int	false	"page size of results. Default is 10."
+//	@Success		200			{object}	ResponseHTTP{data=GetSkippedPushesResponseData}
+//	@Failure		401
+//	@Failure		503			{object}	ResponseHTTP{}
+//	@Router			/api/score/{question_id}/question/skipped [get]
+//	@Security		BearerAuth
+func GetSkippedPushes(c *gin.Context) {
+	db := database.DBConn
+	jwtClaims := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	ownPushes := func() *gorm.DB {
+		return db.Model(&models.SkippedPush{}).
+			Joins("JOIN user_question_relations UQR ON UQR.id = skipped_pushes.uqr_id").
+			Where("UQR.question_id = ? AND UQR.user_id = ?", c.Param("question_id"), jwtClaims.UserID)
+	}
+
+	var totalCount int64
+	if err := ownPushes().Count(&totalCount).Error; err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to count skipped pushes",
+		})
+		return
+	}
+
+	pushes := []models.SkippedPush{}
+	if err := ownPushes().Order("skipped_pushes.created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&pushes).Error; err != nil {
+		c.JSON(503, ResponseHTTP{
+			Success: false,
+			Message: "Failed to get skipped pushes",
+		})
+		return
+	}
+
+	c.JSON(200, ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved skipped pushes",
+		Data: GetSkippedPushesResponseData{
+			PushesCount: int(totalCount),
+			Pushes:      pushes,
+		},
+	})
+}
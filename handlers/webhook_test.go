@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"OJ-API/models"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+func TestPushSkipReason(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	const zero = "0000000000000000000000000000000000000000"
+	push := func(ref string, files ...string) WebhookPayload {
+		payload := WebhookPayload{Ref: ref, After: sha}
+		if len(files) > 0 {
+			payload.Commits = []gitea.PayloadCommit{{Modified: files}}
+			payload.TotalCommits = 1
+		}
+		return payload
+	}
+	filtered := models.Question{TriggerBranches: []string{"main", "release/*"}, PathFilters: []string{"src", "include/*.h"}}
+
+	tests := []struct {
+		name     string
+		payload  WebhookPayload
+		question models.Question
+		want     string // expected prefix of the reason; empty means the push is judged
+	}{
+		{"default branch main", push("refs/heads/main"), models.Question{}, ""},
+		{"default branch master", push("refs/heads/master"), models.Question{}, ""},
+		{"default branches only", push("refs/heads/dev"), models.Question{}, "Branch dev is not judged, push to main, master instead"},
+		{"tag push", push("refs/tags/v1.0"), models.Question{}, "Only branch pushes are judged"},
+		{"branch deleted", WebhookPayload{Ref: "refs/heads/main", After: zero}, models.Question{}, "Branch deleted"},
+		{"practice branch ignores filters", push("refs/heads/practice/try", "README.md"), filtered, ""},
+		{"practice branch deleted", WebhookPayload{Ref: "refs/heads/practice/try", After: zero}, filtered, "Branch deleted"},
+		{"branch pattern", push("refs/heads/release/v2", "src/main.c"), filtered, ""},
+		{"branch not in patterns", push("refs/heads/master", "src/main.c"), filtered, "Branch master is not judged, push to main, release/* instead"},
+		{"change under directory", push("refs/heads/main", "docs/a.md", "src/lib/util.c"), filtered, ""},
+		{"change matching glob", push("refs/heads/main", "include/list.h"), filtered, ""},
+		{"no change under filters", push("refs/heads/main", "README.md", "srcx/main.c"), filtered, "No changes under src, include/*.h"},
+		{"removed file counts", WebhookPayload{Ref: "refs/heads/main", After: sha, Commits: []gitea.PayloadCommit{{Removed: []string{"src/old.c"}}}, TotalCommits: 1}, filtered, ""},
+		{"no commit list", push("refs/heads/main"), filtered, ""},
+		{"truncated commit list", WebhookPayload{Ref: "refs/heads/main", After: sha, Commits: []gitea.PayloadCommit{{Modified: []string{"README.md"}}}, TotalCommits: 30}, filtered, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pushSkipReason(tt.payload, tt.question)
+			if (tt.want == "") != (got == "") || !strings.HasPrefix(got, tt.want) {
+				t.Errorf("pushSkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
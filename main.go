@@ -19,10 +19,12 @@ import (
 
 	"OJ-API/config"
 	"OJ-API/database"
+	"OJ-API/metrics"
 	"OJ-API/models"
 	pb "OJ-API/proto"
 	"OJ-API/routes"
 	"OJ-API/services"
+	"OJ-API/tracing"
 	"OJ-API/utils"
 )
 
@@ -38,6 +40,12 @@ func main() {
 	utils.InitLog()
 	utils.SetServerSource("api-server")
 
+	// 初始化追蹤
+	shutdownTracing, err := tracing.Init(context.Background(), "oj-api-server", "1.0")
+	if err != nil {
+		utils.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
 	// 檢查 TLS 憑證文件
 	certFile := config.Config("TLS_CERT_FILE")
 	keyFile := config.Config("TLS_KEY_FILE")
@@ -66,39 +74,29 @@ func main() {
 		utils.Fatal("Can't connect database:", err.Error())
 	}
 
-	// 初始化沙箱調度器
-	scheduler := services.GetSandboxScheduler()
-	defer scheduler.Close()
-
-	// 創建 gRPC 服務器
-	var grpcServer *grpc.Server
-	if useTLS {
-		// 載入 TLS 憑證
-		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
-		if err != nil {
-			utils.Fatal("Failed to load TLS certificates:", err)
-		}
-
-		// 創建 TLS 憑證
-		creds := credentials.NewServerTLSFromCert(&cert)
-		grpcServer = grpc.NewServer(grpc.Creds(creds))
-	} else {
-		grpcServer = grpc.NewServer()
-	}
-	pb.RegisterSchedulerServiceServer(grpcServer, scheduler)
-
 	// Database migrations
 	models := []interface{}{
 		&models.User{},
 		&models.Announcement{},
+		&models.Course{},
+		&models.CourseEnrollment{},
 		&models.Exam{},
 		&models.Question{},
 		&models.ExamQuestion{},
+		&models.ExamParticipant{},
+		&models.ExamAccessCode{},
+		&models.ExamSession{},
+		&models.ExamUserOverride{},
 		&models.QuestionTestScript{},
 		&models.Tag{},
 		&models.TagAndQuestion{},
 		&models.UserQuestionRelation{},
 		&models.UserQuestionTable{},
+		&models.PracticeRun{},
+		&models.SkippedPush{},
+		&models.SandboxJob{},
+		&models.SandboxNode{},
+		&models.JudgeArtifact{},
 	}
 
 	for _, m := range models {
@@ -107,8 +105,34 @@ func main() {
 		}
 	}
 
+	// 初始化沙箱調度器（需在資料表建立之後）
+	scheduler := services.GetSandboxScheduler()
+	defer scheduler.Close()
+
+	// 創建 gRPC 服務器
+	var grpcServer *grpc.Server
+	if useTLS {
+		// 載入 TLS 憑證
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			utils.Fatal("Failed to load TLS certificates:", err)
+		}
+
+		// 創建 TLS 憑證
+		creds := credentials.NewServerTLSFromCert(&cert)
+		grpcServer = grpc.NewServer(grpc.Creds(creds))
+	} else {
+		grpcServer = grpc.NewServer()
+	}
+	pb.RegisterSchedulerServiceServer(grpcServer, scheduler)
+
 	// Initialize Gin router
-	r := gin.Default()
+	r := gin.New() // 存取紀錄與 recovery 由 routes 註冊
+	// 只信任設定的反向代理所帶的轉送標頭，避免用戶端偽造 IP 繞過考試網段限制
+	r.RemoteIPHeaders = []string{"CF-Connecting-IP", "X-Forwarded-For", "X-Real-IP"}
+	if err := r.SetTrustedProxies(config.GetTrustedProxies()); err != nil {
+		utils.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
 	routes.RegisterRoutes(r)
 
 	// 創建一個多路複用處理器，可以同時處理 HTTP 和 gRPC 請求
@@ -117,6 +141,14 @@ func main() {
 	// 將 Gin 路由器包裝為 HTTP 處理器
 	mux.Handle("/", r)
 
+	// Prometheus 指標在獨立的內部 port 上提供，不經過對外的 API
+	services.RegisterMetrics()
+	metricsPort := config.Config("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9090"
+	}
+	go metrics.Serve(":" + metricsPort)
+
 	// 創建 HTTP 服務器
 	var httpServer *http.Server
 
@@ -165,6 +197,7 @@ func main() {
 		httpServer.Shutdown(context.Background())
 		grpcServer.GracefulStop()
 		scheduler.Close()
+		shutdownTracing(context.Background())
 		os.Exit(0)
 	}()
 
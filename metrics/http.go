@@ -0,0 +1,44 @@
+// Package metrics 在獨立的內部 port 上提供 Prometheus 指標，指標本身由各套件以 promauto 註冊
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"OJ-API/config"
+	"OJ-API/utils"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler 輸出預設 Registry 的指標。設定 METRICS_TOKEN 時需要帶上 Bearer token
+func Handler() http.Handler {
+	token := config.Config("METRICS_TOKEN")
+	metrics := promhttp.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			auth := r.Header.Get("Authorization")
+			if subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+token)) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		metrics.ServeHTTP(w, r)
+	})
+}
+
+// Serve 在獨立的 port 上提供 /metrics，不與對外的 API 共用，只需在內部網路開放
+func Serve(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	utils.Infof("Metrics server listening on %s", addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		utils.Errorf("Metrics server stopped: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlerToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		auth   string
+		status int
+	}{
+		{"no token configured", "", "", http.StatusOK},
+		{"valid token", "s3cret", "Bearer s3cret", http.StatusOK},
+		{"missing token", "s3cret", "", http.StatusUnauthorized},
+		{"wrong token", "s3cret", "Bearer other", http.StatusUnauthorized},
+		{"token without scheme", "s3cret", "s3cret", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("METRICS_TOKEN", tt.token)
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status == http.StatusOK && !strings.Contains(rec.Body.String(), "go_goroutines") {
+				t.Error("metrics output is missing the default Go collector")
+			}
+		})
+	}
+}
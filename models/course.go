@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Course groups questions, exams and users so several classes can share one instance
+type Course struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Code        string    `gorm:"size:50;not null;uniqueIndex" json:"code"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Description string    `gorm:"size:500" json:"description"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CourseEnrollment is a user's membership in a course. Role is the user's role
+// inside that course and only grants permissions on the course's resources.
+type CourseEnrollment struct {
+	CourseID  uint      `gorm:"primaryKey" json:"course_id"`
+	Course    Course    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID    uint      `gorm:"primaryKey;index" json:"user_id"`
+	User      User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Role      Role      `gorm:"size:20;not null;default:'student'" json:"role"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
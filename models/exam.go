@@ -2,12 +2,39 @@ package models
 
 import "time"
 
+// Exam leaderboard ranking modes
+const (
+	RankingScore = "score" // total score, ties broken by the latest counted submission
+	RankingICPC  = "icpc"  // problems solved at full score, then penalty minutes
+)
+
 type Exam struct {
 	ID          uint      `gorm:"primarykey" json:"id"`
 	OwnerID     uint      `gorm:"not null" json:"owner_id"`
 	Owner       User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"owner"`
+	CourseID    *uint     `gorm:"index" json:"course_id"` // nil for exams open to every user
+	Course      *Course   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
 	Title       string    `gorm:"not null;size:50" json:"title"`
 	Description string    `gorm:"size:500" json:"description"`
 	StartTime   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"start_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"`
 	EndTime     time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"end_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"`
+	// DurationMinutes makes the exam timed: each user gets this many minutes from first opening it,
+	// within StartTime and EndTime. Zero means everyone shares the StartTime to EndTime window.
+	DurationMinutes int `gorm:"not null;default:0" json:"duration_minutes" example:"90"`
+	// Access restrictions for proctored exams; see ExamParticipant, ExamAccessCode and ExamSession
+	AllowedCIDRs      []string `gorm:"serializer:json;type:text" json:"allowed_cidrs" example:"140.124.0.0/16"`
+	RequireAccessCode bool     `gorm:"not null;default:false" json:"require_access_code"`
+	// LatePolicy accepts submissions after the user's exam window with a penalty; nil rejects them
+	LatePolicy *LatePolicy `gorm:"serializer:json;type:text" json:"late_policy"`
+	// ScoringPolicy applies to the exam's questions without their own; empty means best
+	ScoringPolicy string `gorm:"size:30;not null;default:''" json:"scoring_policy" example:"last" enums:"best,last,last_before_deadline,top_k_average"`
+	ScoringTopK   int    `gorm:"not null;default:0" json:"scoring_top_k" example:"3"`
+	// FreezeTime freezes the public leaderboard: from then on it only counts submissions made before
+	// it, until the exam is unfrozen. Judging and staff views are not affected.
+	FreezeTime *time.Time `json:"freeze_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"`
+	Unfrozen   bool       `gorm:"not null;default:false" json:"unfrozen"`
+	// RankingMode is how the leaderboard ranks users; PenaltyMinutes is the ICPC penalty per rejected
+	// attempt before a problem is solved
+	RankingMode    string `gorm:"size:20;not null;default:score" json:"ranking_mode" example:"score" enums:"score,icpc"`
+	PenaltyMinutes int    `gorm:"not null;default:20" json:"penalty_minutes" example:"20"`
 }
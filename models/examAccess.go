@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ExamParticipant lists who may take an exam. An exam without participants is open to everyone who can see it.
+type ExamParticipant struct {
+	ExamID    uint      `gorm:"primaryKey" json:"exam_id"`
+	Exam      Exam      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID    uint      `gorm:"primaryKey;index" json:"user_id"`
+	User      User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ExamAccessCode is a one-time code that lets one user start an exam session
+type ExamAccessCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	ExamID    uint       `gorm:"not null;uniqueIndex:idx_exam_access_code" json:"exam_id"`
+	Exam      Exam       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Code      string     `gorm:"size:16;not null;uniqueIndex:idx_exam_access_code" json:"code"`
+	UsedByID  *uint      `json:"used_by_id"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ExamSession records that a user passed an exam's access checks, and the address they last opened it from.
+// Submissions arrive through Gitea webhooks, so on network-restricted exams they are only accepted
+// shortly after the user last opened the exam from an allowed address.
+type ExamSession struct {
+	ExamID       uint      `gorm:"primaryKey" json:"exam_id"`
+	Exam         Exam      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID       uint      `gorm:"primaryKey;index" json:"user_id"`
+	User         User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	AccessCodeID *uint     `json:"access_code_id"`
+	IPAddress    string    `gorm:"size:64;not null" json:"ip_address"`
+	StartedAt    time.Time `gorm:"not null" json:"started_at"`
+	LastSeenAt   time.Time `gorm:"not null" json:"last_seen_at"`
+}
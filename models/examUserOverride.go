@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ExamUserOverride adjusts one user's exam window, e.g. extra time as an accommodation or a make-up sitting
+type ExamUserOverride struct {
+	ExamID       uint       `gorm:"primaryKey" json:"exam_id"`
+	Exam         Exam       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID       uint       `gorm:"primaryKey;index" json:"user_id"`
+	User         User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	StartTime    *time.Time `json:"start_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"` // replaces the exam's start time when set
+	EndTime      *time.Time `json:"end_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"`   // replaces the exam's end time when set
+	ExtraPercent int        `gorm:"not null;default:0" json:"extra_percent" example:"50"`                 // extra time as a percentage of the exam's length
+	ExtraMinutes int        `gorm:"not null;default:0" json:"extra_minutes" example:"0"`
+	Reason       string     `gorm:"size:200" json:"reason"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// JudgeArtifact is the archived raw output (compile logs, stdout/stderr, isolate meta, ...) of one judge run
+type JudgeArtifact struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	UserQuestionTableID uint      `gorm:"not null;uniqueIndex" json:"user_question_table_id"`
+	StorageKey          string    `gorm:"size:255;not null" json:"-"`
+	Size                int64     `gorm:"not null" json:"size"`
+	SHA256              string    `gorm:"size:64;not null" json:"sha256"`
+	CreatedAt           time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
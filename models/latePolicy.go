@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	LatePenaltyLinear = "linear" // deduct in proportion to the time late
+	LatePenaltyStep   = "step"   // deduct a full step for every started interval
+)
+
+// LatePolicy decides whether a submission after the deadline is accepted and how much of its score is deducted.
+// Questions and exams without a policy reject late submissions.
+type LatePolicy struct {
+	GraceMinutes      int     `json:"grace_minutes" example:"10"` // late by at most this much costs nothing
+	Mode              string  `json:"mode" example:"linear" enums:"linear,step"`
+	PenaltyPercent    float64 `json:"penalty_percent" example:"10"`     // deducted per interval after the grace period
+	IntervalMinutes   int     `json:"interval_minutes" example:"1440"`  // 60 for per hour, 1440 for per day
+	MaxPenaltyPercent float64 `json:"max_penalty_percent" example:"50"` // 0 means up to 100
+	CutoffMinutes     int     `json:"cutoff_minutes" example:"4320"`    // late submissions are rejected after this; 0 means after the grace period
+}
+
+// Validate checks that the policy's values are consistent
+func (p *LatePolicy) Validate() error {
+	if p.Mode != "" && p.Mode != LatePenaltyLinear && p.Mode != LatePenaltyStep {
+		return errors.New("late policy mode must be linear or step")
+	}
+	if p.GraceMinutes < 0 || p.IntervalMinutes < 0 || p.CutoffMinutes < 0 {
+		return errors.New("late policy minutes must not be negative")
+	}
+	if p.PenaltyPercent < 0 || p.PenaltyPercent > 100 || p.MaxPenaltyPercent < 0 || p.MaxPenaltyPercent > 100 {
+		return errors.New("late policy percentages must be between 0 and 100")
+	}
+	if p.PenaltyPercent > 0 && p.IntervalMinutes == 0 {
+		return errors.New("late policy interval_minutes is required with penalty_percent")
+	}
+	if p.CutoffMinutes != 0 && p.CutoffMinutes < p.GraceMinutes {
+		return errors.New("late policy cutoff_minutes must not be before the grace period ends")
+	}
+	return nil
+}
+
+// Cutoff returns how long after the deadline late submissions are still accepted
+func (p *LatePolicy) Cutoff() time.Duration {
+	if p == nil {
+		return 0
+	}
+	cutoff := p.CutoffMinutes
+	if cutoff == 0 {
+		cutoff = p.GraceMinutes
+	}
+	return time.Duration(cutoff) * time.Minute
+}
+
+// Penalty returns the percentage deducted from a submission that is late by the given duration,
+// and false if the submission is past the cutoff
+func (p *LatePolicy) Penalty(late time.Duration) (float64, bool) {
+	if p == nil {
+		return 0, late <= 0
+	}
+	if late <= 0 {
+		return 0, true
+	}
+	if late > p.Cutoff() {
+		return 0, false
+	}
+
+	late -= time.Duration(p.GraceMinutes) * time.Minute
+	if late <= 0 || p.PenaltyPercent == 0 {
+		return 0, true
+	}
+	intervals := late.Minutes() / float64(p.IntervalMinutes)
+	if p.Mode == LatePenaltyStep {
+		intervals = math.Ceil(intervals)
+	}
+	maxPenalty := p.MaxPenaltyPercent
+	if maxPenalty == 0 {
+		maxPenalty = 100
+	}
+	return math.Min(p.PenaltyPercent*intervals, maxPenalty), true
+}
+
+// ApplyLatePenalty deducts a late penalty percentage from a score
+func ApplyLatePenalty(score, penaltyPercent float64) float64 {
+	if penaltyPercent <= 0 || score <= 0 {
+		return score
+	}
+	return math.Round(score*(100-penaltyPercent)) / 100
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatePolicyPenalty(t *testing.T) {
+	linear := &LatePolicy{
+		GraceMinutes:    10,
+		Mode:            LatePenaltyLinear,
+		PenaltyPercent:  10,
+		IntervalMinutes: 60,
+		CutoffMinutes:   24 * 60,
+	}
+	step := &LatePolicy{
+		GraceMinutes:    10,
+		Mode:            LatePenaltyStep,
+		PenaltyPercent:  10,
+		IntervalMinutes: 60,
+		CutoffMinutes:   24 * 60,
+	}
+	capped := &LatePolicy{
+		Mode:              LatePenaltyLinear,
+		PenaltyPercent:    20,
+		IntervalMinutes:   60,
+		MaxPenaltyPercent: 50,
+		CutoffMinutes:     24 * 60,
+	}
+	uncapped := &LatePolicy{
+		Mode:            LatePenaltyStep,
+		PenaltyPercent:  40,
+		IntervalMinutes: 60,
+		CutoffMinutes:   24 * 60,
+	}
+	graceOnly := &LatePolicy{GraceMinutes: 30}
+
+	tests := []struct {
+		name        string
+		policy      *LatePolicy
+		late        time.Duration
+		wantPenalty float64
+		wantOK      bool
+	}{
+		{"no policy on time", nil, 0, 0, true},
+		{"no policy late", nil, time.Second, 0, false},
+		{"early", linear, -time.Hour, 0, true},
+		{"within grace", linear, 10 * time.Minute, 0, true},
+		{"linear half interval", linear, 40 * time.Minute, 5, true},
+		{"linear two intervals", linear, 130 * time.Minute, 20, true},
+		{"step starts a full interval", step, 11 * time.Minute, 10, true},
+		{"step exact interval", step, 70 * time.Minute, 10, true},
+		{"step next interval", step, 71 * time.Minute, 20, true},
+		{"max penalty", capped, 5 * time.Hour, 50, true},
+		{"default max penalty is 100", uncapped, 4 * time.Hour, 100, true},
+		{"at cutoff", linear, 24 * time.Hour, 100, true},
+		{"past cutoff", linear, 24*time.Hour + time.Second, 0, false},
+		{"cutoff defaults to grace", graceOnly, 30 * time.Minute, 0, true},
+		{"past grace without cutoff", graceOnly, 31 * time.Minute, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			penalty, ok := tt.policy.Penalty(tt.late)
+			if ok != tt.wantOK {
+				t.Fatalf("Penalty(%v) ok = %v, want %v", tt.late, ok, tt.wantOK)
+			}
+			if diff := penalty - tt.wantPenalty; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Penalty(%v) = %v, want %v", tt.late, penalty, tt.wantPenalty)
+			}
+		})
+	}
+}
+
+func TestLatePolicyCutoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *LatePolicy
+		want   time.Duration
+	}{
+		{"no policy", nil, 0},
+		{"explicit cutoff", &LatePolicy{GraceMinutes: 10, CutoffMinutes: 60}, time.Hour},
+		{"grace period", &LatePolicy{GraceMinutes: 10}, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Cutoff(); got != tt.want {
+				t.Errorf("Cutoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PracticeRun judges a student's code against the public test suites only. Practice runs are kept
+// apart from graded submissions, so they never count towards scores, leaderboards or exports.
+type PracticeRun struct {
+	ID         uint                 `gorm:"primaryKey" json:"id"`
+	UQRID      uint                 `gorm:"not null;index:idx_practice_uqr_created,priority:1" json:"uqr_id"`
+	UQR        UserQuestionRelation `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Score      float64              `gorm:"not null" json:"score"` // over the public suites only
+	JudgeTime  time.Time            `gorm:"not null;default:CURRENT_TIMESTAMP" json:"judge_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"`
+	Message    string               `gorm:"not null" json:"message"`
+	Commit     string               `gorm:"size:150;not null;default:''" json:"commit"`
+	Ref        string               `gorm:"size:255;not null;default:''" json:"ref"` // pushed ref, empty when started from the API
+	RequestID  string               `gorm:"size:64;not null;default:''" json:"request_id"`
+	DeliveryID string               `gorm:"size:64;not null;default:'';uniqueIndex:idx_practice_delivery,where:delivery_id <> ''" json:"delivery_id"` // X-Gitea-Delivery of the push, empty when started from the API
+	CreatedAt  time.Time            `gorm:"autoCreateTime;index:idx_practice_uqr_created,priority:2" json:"created_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// DefaultTriggerBranches are the branches whose pushes are judged when a question declares none
+var DefaultTriggerBranches = []string{"main", "master"}
+
+// ValidatePushFilters checks that trigger branches and path filters are usable glob patterns
+func ValidatePushFilters(branches, paths []string) error {
+	for _, branch := range branches {
+		if strings.TrimSpace(branch) == "" {
+			return errors.New("trigger_branches must not contain empty names")
+		}
+		if _, err := path.Match(branch, ""); err != nil {
+			return errors.New("trigger_branches contains an invalid pattern: " + branch)
+		}
+	}
+	for _, filter := range paths {
+		if strings.Trim(filter, "/ ") == "" {
+			return errors.New("path_filters must not contain empty paths")
+		}
+		if _, err := path.Match(filter, ""); err != nil {
+			return errors.New("path_filters contains an invalid pattern: " + filter)
+		}
+	}
+	return nil
+}
+
+// TriggersBranch reports whether pushes to the branch are judged
+func (q *Question) TriggersBranch(branch string) bool {
+	branches := q.TriggerBranches
+	if len(branches) == 0 {
+		branches = DefaultTriggerBranches
+	}
+	for _, pattern := range branches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesPaths reports whether any of the changed files falls under the question's path filters.
+// A filter matches the file itself, everything below it as a directory, or the file as a glob.
+// Questions without path filters match every change.
+func (q *Question) MatchesPaths(files []string) bool {
+	if len(q.PathFilters) == 0 {
+		return true
+	}
+	for _, file := range files {
+		for _, filter := range q.PathFilters {
+			dir := strings.Trim(filter, "/")
+			if file == dir || strings.HasPrefix(file, dir+"/") {
+				return true
+			}
+			if ok, _ := path.Match(dir, file); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -10,4 +10,17 @@ type Question struct {
 	StartTime   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"start_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"`
 	EndTime     time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"end_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"`
 	IsActive    bool      `gorm:"not null;default:true;index:idx_questions_is_active" json:"is_active"`
+	CourseID    *uint     `gorm:"index" json:"course_id"` // nil for questions open to every user
+	Course      *Course   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+	// LatePolicy accepts pushes after EndTime with a penalty; nil rejects them. For exam questions
+	// it takes precedence over the exam's policy.
+	LatePolicy *LatePolicy `gorm:"serializer:json;type:text" json:"late_policy"`
+	// ScoringPolicy picks the submission that counts; empty falls back to the exam's policy, then best.
+	// ScoringTopK is the K of top_k_average.
+	ScoringPolicy string `gorm:"size:30;not null;default:''" json:"scoring_policy" example:"best" enums:"best,last,last_before_deadline,top_k_average"`
+	ScoringTopK   int    `gorm:"not null;default:0" json:"scoring_top_k" example:"3"`
+	// TriggerBranches are the branch name patterns whose pushes are judged; empty means main and master.
+	// PathFilters limit judging to pushes that change files under these paths; empty means any change.
+	TriggerBranches []string `gorm:"serializer:json;type:text" json:"trigger_branches" example:"main,release/*"`
+	PathFilters     []string `gorm:"serializer:json;type:text" json:"path_filters" example:"src,include/*.h"`
 }
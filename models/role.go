@@ -0,0 +1,79 @@
+package models
+
+// Role is a user's global role. Gitea site admins are always admins.
+type Role string
+
+const (
+	RoleAdmin      Role = "admin"
+	RoleInstructor Role = "instructor"
+	RoleTA         Role = "ta"
+	RoleStudent    Role = "student"
+)
+
+// Permission is an action a role may perform
+type Permission string
+
+const (
+	PermViewUsers        Permission = "users:view"           // view user details
+	PermManageUsers      Permission = "users:manage"         // update users, change emails, bulk create accounts
+	PermResetPasswords   Permission = "users:reset_password" // reset other users' passwords
+	PermManageRoles      Permission = "users:manage_roles"   // assign roles
+	PermViewAllQuestions Permission = "questions:view_all"   // see unpublished questions, limits and scripts
+	PermManageQuestions  Permission = "questions:manage"     // create and update questions
+	PermDeleteQuestions  Permission = "questions:delete"
+	PermViewAllExams     Permission = "exams:view_all" // see unpublished exams and their questions
+	PermManageExams      Permission = "exams:manage"   // create exams and manage the exams they own
+	PermManageAllExams   Permission = "exams:manage_all"
+	PermViewScores       Permission = "scores:view" // view other users' scores and full leaderboards
+	PermRescore          Permission = "scores:rescore"
+	PermExportScores     Permission = "scores:export"
+	PermCancelJobs       Permission = "sandbox:cancel_jobs" // cancel other users' judge jobs
+	PermViewArtifacts    Permission = "sandbox:artifacts"   // download judge artifacts
+	PermManageSandboxes  Permission = "sandbox:manage"
+	PermManageCourses    Permission = "courses:manage" // create courses and manage the courses they teach
+	PermManageAllCourses Permission = "courses:manage_all"
+	PermManageMembers    Permission = "courses:manage_members" // enroll and remove course members
+)
+
+// RolePermissions lists the permissions granted to each role
+var RolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermViewUsers, PermManageUsers, PermResetPasswords, PermManageRoles,
+		PermViewAllQuestions, PermManageQuestions, PermDeleteQuestions,
+		PermViewAllExams, PermManageExams, PermManageAllExams,
+		PermViewScores, PermRescore, PermExportScores,
+		PermCancelJobs, PermViewArtifacts, PermManageSandboxes,
+		PermManageCourses, PermManageAllCourses, PermManageMembers,
+	},
+	RoleInstructor: {
+		PermViewUsers,
+		PermViewAllQuestions, PermManageQuestions, PermDeleteQuestions,
+		PermViewAllExams, PermManageExams,
+		PermViewScores, PermRescore, PermExportScores,
+		PermCancelJobs, PermViewArtifacts,
+		PermManageCourses, PermManageMembers,
+	},
+	RoleTA: {
+		PermViewAllQuestions,
+		PermViewAllExams,
+		PermViewScores, PermRescore, PermExportScores,
+		PermCancelJobs, PermViewArtifacts,
+	},
+	RoleStudent: {},
+}
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	_, ok := RolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants the permission
+func (r Role) Can(p Permission) bool {
+	for _, granted := range RolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
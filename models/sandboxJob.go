@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+const (
+	SandboxJobQueued   = "queued"
+	SandboxJobAssigned = "assigned"
+)
+
+// Kinds of judge jobs, naming the table a job's TargetID refers to
+const (
+	SandboxJobJudge    = "judge"    // graded submission, TargetID is a UserQuestionTable ID
+	SandboxJobPractice = "practice" // practice run, TargetID is a PracticeRun ID
+)
+
+// Jobs with a higher priority are dispatched first
+const (
+	SandboxJobPriorityLow      = 0  // bulk rescoring by admins
+	SandboxJobPriorityPractice = 5  // practice runs, behind graded pushes
+	SandboxJobPriorityNormal   = 10 // pushes and student rescoring
+)
+
+// SandboxJob is a judge job waiting for or assigned to a sandbox, shared by all API replicas
+type SandboxJob struct {
+	ID                uint              `gorm:"primaryKey" json:"id"`
+	Kind              string            `gorm:"size:20;not null;default:'judge';uniqueIndex:idx_sandbox_job_target,priority:1" json:"kind"`
+	TargetID          uint              `gorm:"not null;uniqueIndex:idx_sandbox_job_target,priority:2" json:"target_id"`
+	ParentGitFullName string            `gorm:"size:255;not null" json:"parent_git_full_name"`
+	GitRepoURL        string            `gorm:"size:500;not null" json:"git_repo_url"`
+	GitFullName       string            `gorm:"size:255;not null" json:"git_full_name"`
+	GitAfterHash      string            `gorm:"size:150;not null;default:''" json:"git_after_hash"`
+	GitUsername       string            `gorm:"size:100;not null;default:''" json:"git_username"`
+	GitToken          string            `gorm:"size:1000;not null;default:''" json:"-"` // encrypted with ENCRYPTION_KEY
+	RequestID         string            `gorm:"size:64;not null;default:''" json:"request_id"`
+	TraceContext      map[string]string `gorm:"serializer:json;type:text" json:"-"` // W3C trace context of the enqueueing request
+	Status            string            `gorm:"size:20;not null;index" json:"status"`
+	Priority          int               `gorm:"not null;default:10" json:"priority"`
+	SandboxID         string            `gorm:"size:100;not null;default:'';index" json:"sandbox_id"`
+	ReplicaID         string            `gorm:"size:100;not null;default:''" json:"replica_id"`
+	AssignedAt        *time.Time        `json:"assigned_at"`
+	CreatedAt         time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
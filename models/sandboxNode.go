@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// SandboxNode is a sandbox connected to one of the API replicas
+type SandboxNode struct {
+	ID              string            `gorm:"primaryKey;size:100" json:"id"`
+	ReplicaID       string            `gorm:"size:100;not null;index" json:"replica_id"`
+	Capacity        int32             `gorm:"not null;default:0" json:"capacity"`
+	AvailableCount  int32             `gorm:"not null;default:0" json:"available_count"`
+	WaitingCount    int32             `gorm:"not null;default:0" json:"waiting_count"`
+	ProcessingCount int32             `gorm:"not null;default:0" json:"processing_count"`
+	Cordoned        bool              `gorm:"not null;default:false" json:"cordoned"`
+	Draining        bool              `gorm:"not null;default:false" json:"draining"`
+	Labels          map[string]string `gorm:"serializer:json;type:text" json:"labels"`
+	Version         string            `gorm:"size:100;not null;default:''" json:"version"`
+	LastSeen        time.Time         `gorm:"not null" json:"last_seen"`
+	ConnectedAt     time.Time         `gorm:"not null" json:"connected_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// Scoring policies decide which of a user's submissions to a question count towards their score
+const (
+	ScoringBest               = "best"                 // the highest score
+	ScoringLast               = "last"                 // the latest judged submission
+	ScoringLastBeforeDeadline = "last_before_deadline" // the latest judged submission that was not late
+	ScoringTopKAverage        = "top_k_average"        // the average of the K highest scores
+)
+
+// ValidScoringPolicy reports whether policy is a known scoring policy; empty means the default
+func ValidScoringPolicy(policy string) bool {
+	switch policy {
+	case "", ScoringBest, ScoringLast, ScoringLastBeforeDeadline, ScoringTopKAverage:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SkippedPush records a push to a student's repository that the webhook received but did not judge,
+// so that students can see why a push produced no submission
+type SkippedPush struct {
+	ID         uint                 `gorm:"primaryKey" json:"id"`
+	UQRID      uint                 `gorm:"not null;index:idx_skipped_push_uqr_created,priority:1" json:"uqr_id"`
+	UQR        UserQuestionRelation `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Ref        string               `gorm:"type:text;not null;default:''" json:"ref"`
+	Commit     string               `gorm:"size:150;not null;default:''" json:"commit"`
+	Reason     string               `gorm:"type:text;not null" json:"reason"`
+	RequestID  string               `gorm:"size:64;not null;default:''" json:"request_id"`
+	DeliveryID string               `gorm:"size:64;not null;default:'';uniqueIndex:idx_skipped_push_delivery,where:delivery_id <> ''" json:"delivery_id"` // X-Gitea-Delivery of the push
+	CreatedAt  time.Time            `gorm:"autoCreateTime;index:idx_skipped_push_uqr_created,priority:2" json:"created_at"`
+}
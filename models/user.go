@@ -12,6 +12,7 @@ type User struct {
 	RefreshToken              string    `gorm:"size:1000" json:"refresh_token"`
 	Nonce                     string    `gorm:"size:100" json:"nonce"`
 	IsAdmin                   bool      `gorm:"default:false;not null" json:"is_admin"`
+	Role                      Role      `gorm:"size:20;not null;default:'student'" json:"role"`
 	ResetPassword             bool      `gorm:"default:false;not null" json:"reset_password"`
 	ForgetPasswordRequestTime time.Time `json:"forget_password_request_time"`
 }
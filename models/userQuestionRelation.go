@@ -7,4 +7,5 @@ type UserQuestionRelation struct {
 	QuestionID     uint     `gorm:"not null;index:idx_uqr_question_user" json:"question_id"`
 	Question       Question `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"question"`
 	GitUserRepoURL string   `gorm:"size:150;not null" json:"git_user_repo_url"`
+	WebhookSecret  string   `gorm:"size:255;not null;default:''" json:"-"` // signs the repository's push events, encrypted with ENCRYPTION_KEY
 }
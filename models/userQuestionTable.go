@@ -3,12 +3,17 @@ package models
 import "time"
 
 type UserQuestionTable struct {
-	ID        uint                 `gorm:"primaryKey" json:"id"`
-	UQRID     uint                 `gorm:"not null;index:idx_uqt_uqr_score_created,priority:1" json:"uqr_id"`
-	UQR       UserQuestionRelation `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"uqr"`
-	Score     float64              `gorm:"not null;index:idx_uqt_uqr_score_created,priority:2" json:"score"`
-	JudgeTime time.Time            `gorm:"not null;default:CURRENT_TIMESTAMP" json:"judge_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"`
-	Message   string               `gorm:"not null" json:"message"`
-	Commit    string               `gorm:"size:150;not null;default:''" json:"commit"`
-	CreatedAt time.Time            `gorm:"autoCreateTime;index:idx_uqt_uqr_score_created,priority:3" json:"created_at"`
+	ID          uint                 `gorm:"primaryKey" json:"id"`
+	UQRID       uint                 `gorm:"not null;index:idx_uqt_uqr_score_created,priority:1;index:idx_uqt_uqr_commit,priority:1" json:"uqr_id"`
+	UQR         UserQuestionRelation `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"uqr"`
+	Score       float64              `gorm:"not null;index:idx_uqt_uqr_score_created,priority:2" json:"score"` // after the late penalty
+	RawScore    float64              `gorm:"not null;default:0" json:"raw_score"`                              // before the late penalty
+	LatePenalty float64              `gorm:"not null;default:0" json:"late_penalty"`                           // percentage deducted for a late submission
+	Late        bool                 `gorm:"not null;default:false" json:"late"`                               // submitted after the deadline
+	JudgeTime   time.Time            `gorm:"not null;default:CURRENT_TIMESTAMP" json:"judge_time" example:"2006-01-02T15:04:05Z07:00" time_format:"RFC3339"`
+	Message     string               `gorm:"not null" json:"message"`
+	Commit      string               `gorm:"size:150;not null;default:'';index:idx_uqt_uqr_commit,priority:2" json:"commit"`
+	RequestID   string               `gorm:"size:64;not null;default:'';index" json:"request_id"`
+	DeliveryID  string               `gorm:"size:64;not null;default:'';uniqueIndex:idx_uqt_delivery,where:delivery_id <> ''" json:"delivery_id"` // X-Gitea-Delivery of the push, empty when not from a webhook
+	CreatedAt   time.Time            `gorm:"autoCreateTime;index:idx_uqt_uqr_score_created,priority:3" json:"created_at"`
 }
@@ -2,15 +2,26 @@ package routes
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
 	_ "OJ-API/docs"
 	"OJ-API/handlers"
 	"OJ-API/models"
+	"OJ-API/services"
+	"OJ-API/tracing"
 	"OJ-API/utils"
 )
 
@@ -85,7 +96,164 @@ func AuthMiddleware(required ...bool) gin.HandlerFunc {
 	}
 }
 
+// MetricsMiddleware records the latency of every request by its route template
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		services.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// RequirePermission aborts with 403 unless the authenticated user's role grants all of the permissions.
+// It must run after AuthMiddleware.
+func RequirePermission(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jwtClaims, _ := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+		for _, permission := range permissions {
+			if !jwtClaims.Can(permission) {
+				c.JSON(http.StatusForbidden, handlers.ResponseHTTP{
+					Success: false,
+					Message: "Permission denied",
+				})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireCoursePermission aborts with 403 unless the user holds all of the permissions in the
+// course named by the :course_id parameter. It must run after AuthMiddleware.
+func RequireCoursePermission(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		courseID, err := strconv.ParseUint(c.Param("course_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, handlers.ResponseHTTP{
+				Success: false,
+				Message: "Invalid course ID",
+			})
+			c.Abort()
+			return
+		}
+		id := uint(courseID)
+		jwtClaims, _ := c.Request.Context().Value(models.JWTClaimsKey).(*utils.JWTClaims)
+		for _, permission := range permissions {
+			if !handlers.CanInCourse(jwtClaims, &id, permission) {
+				c.JSON(http.StatusForbidden, handlers.ResponseHTTP{
+					Success: false,
+					Message: "Permission denied",
+				})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequestIDMiddleware assigns every request an ID (reusing a valid X-Request-ID header)
+// and stores it in the request context so logs and judge jobs can be correlated
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Writer.Header().Set("X-Request-ID", requestID)
+		c.Request = c.Request.WithContext(utils.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// validRequestID accepts up to 64 characters of letters, digits, '-', '_' and '.'
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// TracingMiddleware starts a server span for every request, continuing an incoming W3C trace context
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("oj.request_id", utils.RequestIDFromContext(ctx)),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// LoggerMiddleware writes one structured access log record per request
+func LoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		utils.Logger().LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
 func RegisterRoutes(r *gin.Engine) {
+	r.Use(RequestIDMiddleware())
+	r.Use(TracingMiddleware())
+	r.Use(MetricsMiddleware())
+
 	// Enhanced CORS middleware with comprehensive browser compatibility
 	r.Use(func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
@@ -104,8 +272,8 @@ func RegisterRoutes(r *gin.Engine) {
 
 		// Comprehensive headers for all browsers including Edge, Chrome, Firefox, Safari
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS, HEAD")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Accept, Accept-Language, Content-Language, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Origin, Cache-Control, X-Requested-With, Cookie, Set-Cookie, Access-Control-Allow-Origin, Access-Control-Allow-Credentials, Pragma, Expires, Last-Modified, If-Modified-Since, If-None-Match, ETag, Priority, Sec-Fetch-Dest, Sec-Fetch-Mode, Sec-Fetch-Site, Sec-Ch-Ua, Sec-Ch-Ua-Mobile, Sec-Ch-Ua-Platform, User-Agent, Referer")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Set-Cookie, Authorization, Content-Length, Content-Type, Cache-Control, ETag, Last-Modified, Expires")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Accept, Accept-Language, Content-Language, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Origin, Cache-Control, X-Requested-With, Cookie, Set-Cookie, Access-Control-Allow-Origin, Access-Control-Allow-Credentials, Pragma, Expires, Last-Modified, If-Modified-Since, If-None-Match, ETag, Priority, Sec-Fetch-Dest, Sec-Fetch-Mode, Sec-Fetch-Site, Sec-Ch-Ua, Sec-Ch-Ua-Mobile, Sec-Ch-Ua-Platform, User-Agent, Referer, X-Request-ID")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Set-Cookie, Authorization, Content-Length, Content-Type, Cache-Control, ETag, Last-Modified, Expires, X-Request-ID")
 		c.Writer.Header().Set("Access-Control-Max-Age", "86400")
 
 		// Additional headers for browser compatibility
@@ -134,7 +302,7 @@ func RegisterRoutes(r *gin.Engine) {
 		})
 	})
 
-	r.Use(gin.Logger())
+	r.Use(LoggerMiddleware())
 	r.Use(gin.Recovery())
 
 	// Swagger documentation
@@ -162,14 +330,25 @@ func RegisterRoutes(r *gin.Engine) {
 		api.GET("/auth/oauth/callback", handlers.OAuthCallback)
 
 		// Admin routes
-		api.POST("/admin/:id/user/reset_password", AuthMiddleware(), handlers.ResetUserPassword)
-		api.GET("/admin/user", AuthMiddleware(), handlers.GetAllUserInfo)
-		api.GET("/admin/:id/user", AuthMiddleware(), handlers.GetUserInfo)
-		api.PATCH("/admin/:id/user", AuthMiddleware(), handlers.UpdateUserInfo)
-		api.POST("/admin/:id/user/change_email", AuthMiddleware(), handlers.ChangeUserEmail)
-		api.GET("/admin/questions/:id/export", AuthMiddleware(), handlers.ExportQuestionScore)
-
-		// Exam routes
+		api.POST("/admin/:id/user/reset_password", AuthMiddleware(), RequirePermission(models.PermResetPasswords), handlers.ResetUserPassword)
+		api.GET("/admin/user", AuthMiddleware(), RequirePermission(models.PermViewUsers), handlers.GetAllUserInfo)
+		api.GET("/admin/:id/user", AuthMiddleware(), RequirePermission(models.PermViewUsers), handlers.GetUserInfo)
+		api.PATCH("/admin/:id/user", AuthMiddleware(), RequirePermission(models.PermManageUsers), handlers.UpdateUserInfo)
+		api.POST("/admin/:id/user/change_email", AuthMiddleware(), RequirePermission(models.PermManageUsers), handlers.ChangeUserEmail)
+		api.PUT("/admin/:id/user/role", AuthMiddleware(), RequirePermission(models.PermManageRoles), handlers.UpdateUserRole)
+		api.GET("/admin/questions/:id/export", AuthMiddleware(), handlers.ExportQuestionScore) // permission depends on the question's course
+
+		// Course routes
+		api.GET("/courses", AuthMiddleware(), handlers.ListCourses)
+		api.POST("/courses/admin", AuthMiddleware(), RequirePermission(models.PermManageCourses), handlers.CreateCourse)
+		api.GET("/courses/:course_id/course", AuthMiddleware(), handlers.GetCourse)
+		api.PATCH("/courses/admin/:course_id/course", AuthMiddleware(), RequireCoursePermission(models.PermManageCourses), handlers.UpdateCourse)
+		api.DELETE("/courses/admin/:course_id/course", AuthMiddleware(), RequireCoursePermission(models.PermManageCourses), handlers.DeleteCourse)
+		api.GET("/courses/:course_id/members", AuthMiddleware(), RequireCoursePermission(models.PermViewScores), handlers.ListCourseMembers)
+		api.POST("/courses/admin/:course_id/members", AuthMiddleware(), RequireCoursePermission(models.PermManageMembers), handlers.AddCourseMembers)
+		api.DELETE("/courses/admin/:course_id/members/:user_id", AuthMiddleware(), RequireCoursePermission(models.PermManageMembers), handlers.RemoveCourseMember)
+
+		// Exam routes (permissions depend on the exam's course and are checked by the handlers)
 		api.POST("/exams/admin", AuthMiddleware(), handlers.CreateExam)
 		api.GET("/exams/admin/:id/exam", AuthMiddleware(), handlers.GetExam)
 		api.PUT("/exams/admin/:id/exam", AuthMiddleware(), handlers.UpdateExam)
@@ -178,26 +357,48 @@ func RegisterRoutes(r *gin.Engine) {
 		api.POST("/exams/admin/:id/questions/:question_id/question", AuthMiddleware(), handlers.AddQuestionToExam)
 		api.DELETE("/exams/admin/:id/questions/:question_id/question", AuthMiddleware(), handlers.RemoveQuestionFromExam)
 		api.PUT("/exams/admin/:id/questions/:question_id/question", AuthMiddleware(), handlers.UpdateQuestionInExam)
+		api.PUT("/exams/admin/:id/access", AuthMiddleware(), handlers.UpdateExamAccess)
+		api.GET("/exams/admin/:id/participants", AuthMiddleware(), handlers.ListExamParticipants)
+		api.POST("/exams/admin/:id/participants", AuthMiddleware(), handlers.AddExamParticipants)
+		api.DELETE("/exams/admin/:id/participants/:user_id", AuthMiddleware(), handlers.RemoveExamParticipant)
+		api.GET("/exams/admin/:id/access_codes", AuthMiddleware(), handlers.ListExamAccessCodes)
+		api.POST("/exams/admin/:id/access_codes", AuthMiddleware(), handlers.GenerateExamAccessCodes)
+		api.DELETE("/exams/admin/:id/sessions/:user_id", AuthMiddleware(), handlers.ResetExamSession)
+		api.GET("/exams/admin/:id/overrides", AuthMiddleware(), handlers.ListExamOverrides)
+		api.PUT("/exams/admin/:id/overrides/:user_id", AuthMiddleware(), handlers.SetExamOverride)
+		api.DELETE("/exams/admin/:id/overrides/:user_id", AuthMiddleware(), handlers.DeleteExamOverride)
+		api.PUT("/exams/admin/:id/freeze", AuthMiddleware(), handlers.FreezeExamLeaderboard)
+		api.POST("/exams/admin/:id/unfreeze", AuthMiddleware(), handlers.UnfreezeExamLeaderboard)
+		api.POST("/exams/:id/session", AuthMiddleware(), handlers.StartExamSession)
 		api.GET("/exams/:id/exam", AuthMiddleware(false), handlers.GetExamInfo)
 		api.GET("/exams/:id/leaderboard", AuthMiddleware(false), handlers.GetExamLeaderboard)
 		api.GET("/exams/:id/questions", AuthMiddleware(false), handlers.GetExamQuestions)
 		api.GET("/exams/:id/score/top", AuthMiddleware(), handlers.GetTopExamScore)
 
 		// Sandbox routes
-		api.POST("/sandbox/admin/sandbox_cmd", AuthMiddleware(), handlers.PostSandboxCmd)
+		api.POST("/sandbox/admin/sandbox_cmd", AuthMiddleware(), RequirePermission(models.PermManageSandboxes), handlers.PostSandboxCmd)
 		api.GET("/sandbox/status", handlers.GetSandboxStatus)
+		api.GET("/sandbox/repo", handlers.GetSandboxRepoArchive)
+		api.GET("/sandbox/admin/instances", AuthMiddleware(), RequirePermission(models.PermManageSandboxes), handlers.ListSandboxInstances)
+		api.POST("/sandbox/admin/instances/:id/drain", AuthMiddleware(), RequirePermission(models.PermManageSandboxes), handlers.PostDrainSandbox)
+		api.POST("/sandbox/admin/instances/:id/undrain", AuthMiddleware(), RequirePermission(models.PermManageSandboxes), handlers.PostUndrainSandbox)
+		api.POST("/sandbox/admin/instances/:id/cordon", AuthMiddleware(), RequirePermission(models.PermManageSandboxes), handlers.PostCordonSandbox)
+		api.POST("/sandbox/admin/instances/:id/uncordon", AuthMiddleware(), RequirePermission(models.PermManageSandboxes), handlers.PostUncordonSandbox)
+		api.POST("/sandbox/admin/instances/:id/shutdown", AuthMiddleware(), RequirePermission(models.PermManageSandboxes), handlers.PostShutdownSandbox)
+		api.POST("/sandbox/jobs/:UQT_ID/cancel", AuthMiddleware(), handlers.PostCancelJob)
+		api.GET("/sandbox/jobs/:UQT_ID/artifacts", AuthMiddleware(), handlers.GetJudgeArtifacts) // permission depends on the question's course
 
 		// Gitea routes
-		api.POST("/gitea", AuthMiddleware(), handlers.PostGiteaHook)
+		api.POST("/gitea", handlers.PostGiteaHook) // authenticated by the X-Gitea-Signature of the repository's webhook secret
 		api.POST("/gitea/:question_id/question", AuthMiddleware(), handlers.PostCreateQuestionRepositoryGitea)
 		api.GET("/gitea/user", AuthMiddleware(), handlers.GetUserProfileGitea)
-		api.POST("/gitea/admin/user/bulk", AuthMiddleware(), handlers.PostBulkCreateUserGitea)
-		api.POST("/gitea/admin/user/bulk_v2", AuthMiddleware(), handlers.PostBulkCreateUserGiteav2)
+		api.POST("/gitea/admin/user/bulk", AuthMiddleware(), RequirePermission(models.PermManageUsers), handlers.PostBulkCreateUserGitea)
+		api.POST("/gitea/admin/user/bulk_v2", AuthMiddleware(), RequirePermission(models.PermManageUsers), handlers.PostBulkCreateUserGiteav2)
 		api.POST("/gitea/user/keys", AuthMiddleware(), handlers.PostCreatePublicKeyGitea)
 		api.GET("/gitea/user/keys", AuthMiddleware(), handlers.ListMyPublicKeysGitea)
 		api.DELETE("/gitea/user/keys", AuthMiddleware(), handlers.DeletePublicKeyGitea)
 
-		// Questions routes
+		// Questions routes (permissions depend on the question's course and are checked by the handlers)
 		api.GET("/questions", AuthMiddleware(false), handlers.GetQuestionList)
 		api.GET("/questions/:ID/question", AuthMiddleware(false), handlers.GetQuestionByID)
 		api.PATCH("/questions/admin/:ID/question", AuthMiddleware(), handlers.PatchQuestion)
@@ -213,11 +414,17 @@ func RegisterRoutes(r *gin.Engine) {
 		api.GET("/score/all", AuthMiddleware(), handlers.GetAllScore)
 		api.GET("/score/leaderboard", AuthMiddleware(false), handlers.GetLeaderboard)
 		api.GET("/score/:question_id/question", AuthMiddleware(), handlers.GetScoreByQuestionID)
-		api.POST("/score/admin/:question_id/question/rescore", AuthMiddleware(), handlers.ReScoreQuestion)
+		api.GET("/score/:question_id/question/skipped", AuthMiddleware(), handlers.GetSkippedPushes)
+		api.POST("/score/admin/:question_id/question/rescore", AuthMiddleware(), handlers.ReScoreQuestion) // permission depends on the question's course
+		api.POST("/score/admin/uqt/:UQT_ID/rejudge", AuthMiddleware(), handlers.ReJudgeSubmission)         // permission depends on the question's course
 		api.GET("/score/top", AuthMiddleware(), handlers.GetTopScore)
 		api.POST("/score/:question_id/question/user_rescore", AuthMiddleware(), handlers.ReScoreUserQuestion)
 		api.GET("/score/uqr/:UQR_ID/score", AuthMiddleware(), handlers.GetScoreByUQRID)
 
+		// Practice routes
+		api.POST("/practice/:question_id", AuthMiddleware(), handlers.PostPracticeRun)
+		api.GET("/practice/:question_id", AuthMiddleware(), handlers.GetPracticeRuns)
+
 		// User routes
 		api.GET("/user", AuthMiddleware(), handlers.GetUser)
 		api.POST("/user/is_public", AuthMiddleware(), handlers.PostUserIsPublic)
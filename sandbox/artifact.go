@@ -0,0 +1,255 @@
+package sandbox
+
+import (
+	"OJ-API/config"
+	"OJ-API/database"
+	"OJ-API/models"
+	"OJ-API/storage"
+	"OJ-API/utils"
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// defaultArtifactOutputLimit 每個輸出檔案保留的最大位元組數
+const defaultArtifactOutputLimit = 1 << 20
+
+// ArtifactKey 回傳評測產物在儲存空間中的 key
+func ArtifactKey(uqtID uint) string {
+	return fmt.Sprintf("judges/%d.tar.gz", uqtID)
+}
+
+// artifactFileInfo manifest 中每個檔案的資訊
+type artifactFileInfo struct {
+	Name         string `json:"name"`
+	Size         int    `json:"size"`
+	OriginalSize int    `json:"original_size"`
+	Truncated    bool   `json:"truncated"`
+}
+
+// artifactManifest 評測產物的摘要，存放在壓縮檔的 manifest.json
+type artifactManifest struct {
+	UserQuestionTableID uint               `json:"user_question_table_id"`
+	RequestID           string             `json:"request_id"`
+	Repository          string             `json:"repository"`
+	Commit              string             `json:"commit"`
+	BoxID               int                `json:"box_id"`
+	Verdict             string             `json:"verdict"`
+	Score               float64            `json:"score"`
+	StartedAt           time.Time          `json:"started_at"`
+	FinishedAt          time.Time          `json:"finished_at"`
+	Files               []artifactFileInfo `json:"files"`
+}
+
+type artifactFile struct {
+	name string
+	data []byte
+}
+
+// artifactBundle 收集單次評測各階段的原始輸出，nil 時所有操作皆不做事
+type artifactBundle struct {
+	mu       sync.Mutex
+	limit    int
+	files    []artifactFile
+	manifest artifactManifest
+}
+
+// newArtifactBundle 建立評測產物，ARTIFACT_STORAGE=none 時停用並回傳 nil
+func newArtifactBundle(uqt models.UserQuestionTable, repo string, boxID int) *artifactBundle {
+	if config.Config("ARTIFACT_STORAGE") == "none" {
+		return nil
+	}
+	limit := defaultArtifactOutputLimit
+	if v, err := strconv.Atoi(config.Config("ARTIFACT_MAX_OUTPUT_BYTES")); err == nil && v > 0 {
+		limit = v
+	}
+	return &artifactBundle{
+		limit: limit,
+		manifest: artifactManifest{
+			UserQuestionTableID: uqt.ID,
+			RequestID:           uqt.RequestID,
+			Repository:          repo,
+			Commit:              uqt.Commit,
+			BoxID:               boxID,
+			StartedAt:           time.Now().UTC(),
+		},
+	}
+}
+
+// add 加入一個檔案，超過上限的部分會被截斷
+func (b *artifactBundle) add(name string, data []byte) {
+	if b == nil {
+		return
+	}
+	info := artifactFileInfo{Name: name, OriginalSize: len(data)}
+	if len(data) > b.limit {
+		data = append(data[:b.limit:b.limit], []byte(fmt.Sprintf("\n... truncated %d bytes\n", len(data)-b.limit))...)
+		info.Truncated = true
+	}
+	info.Size = len(data)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files = append(b.files, artifactFile{name: name, data: data})
+	b.manifest.Files = append(b.manifest.Files, info)
+}
+
+// addFile 加入磁碟上的檔案，檔案不存在時略過
+func (b *artifactBundle) addFile(name, path string) {
+	if b == nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	b.add(name, data)
+}
+
+// archive 將所有檔案與 manifest 打包成 tar.gz
+func (b *artifactBundle) archive(verdict JudgeResult, score float64) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.manifest.Verdict = string(verdict)
+	b.manifest.Score = score
+	b.manifest.FinishedAt = time.Now().UTC()
+	manifest, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	files := append([]artifactFile{{name: "manifest.json", data: manifest}}, b.files...)
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    f.name,
+			Mode:    0644,
+			Size:    int64(len(f.data)),
+			ModTime: b.manifest.FinishedAt,
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// storeArtifacts 打包並上傳評測產物，記錄到 judge_artifacts
+func storeArtifacts(ctx context.Context, b *artifactBundle, verdict JudgeResult, score float64) {
+	if b == nil {
+		return
+	}
+	uqtID := b.manifest.UserQuestionTableID
+	data, err := b.archive(verdict, score)
+	if err != nil {
+		utils.Ctx(ctx).Warnf("Failed to archive judge artifacts: %v", err)
+		return
+	}
+
+	// 打包完成後於背景上傳，避免延遲 box 的釋放
+	go func() {
+		store, err := storage.Default()
+		if err != nil {
+			utils.Ctx(ctx).Warnf("Artifact storage unavailable: %v", err)
+			return
+		}
+
+		key := ArtifactKey(uqtID)
+		uploadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+		defer cancel()
+		if err := store.Put(uploadCtx, key, bytes.NewReader(data), int64(len(data)), "application/gzip"); err != nil {
+			utils.Ctx(ctx).Warnf("Failed to upload judge artifacts: %v", err)
+			return
+		}
+
+		sum := sha256.Sum256(data)
+		artifact := models.JudgeArtifact{
+			UserQuestionTableID: uqtID,
+			StorageKey:          key,
+			Size:                int64(len(data)),
+			SHA256:              hex.EncodeToString(sum[:]),
+		}
+		if err := database.DBConn.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_question_table_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"storage_key", "size", "sha256", "created_at"}),
+		}).Create(&artifact).Error; err != nil {
+			utils.Ctx(ctx).Warnf("Failed to record judge artifacts: %v", err)
+			return
+		}
+		utils.Ctx(ctx).Debugf("Stored judge artifacts (%d bytes) at %s", len(data), key)
+	}()
+}
+
+// lockedBuffer 可同時被 stdout 與 stderr 寫入的 buffer
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+var artifactNameReplacer = strings.NewReplacer("/", "_", "\\", "_", " ", "_")
+
+// runIsolate 執行 isolate 並回傳合併的輸出，同時將 stdout、stderr 與 isolate meta 記錄到評測產物
+func runIsolate(ctx context.Context, bundle *artifactBundle, stage, target string, args []string) ([]byte, error) {
+	if bundle == nil {
+		return exec.CommandContext(ctx, "isolate", args...).CombinedOutput()
+	}
+
+	// --meta 必須放在 --run 之前
+	metaFile, err := os.CreateTemp("", "isolate-meta-*")
+	if err == nil {
+		metaFile.Close()
+		defer os.Remove(metaFile.Name())
+		for i, arg := range args {
+			if arg == "--run" {
+				args = append(args[:i:i], append([]string{"--meta=" + metaFile.Name()}, args[i:]...)...)
+				break
+			}
+		}
+	}
+
+	var combined lockedBuffer
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "isolate", args...)
+	cmd.Stdout = io.MultiWriter(&stdout, &combined)
+	cmd.Stderr = io.MultiWriter(&stderr, &combined)
+	runErr := cmd.Run()
+
+	name := fmt.Sprintf("%s/%s", stage, artifactNameReplacer.Replace(target))
+	bundle.add(name+".stdout", stdout.Bytes())
+	bundle.add(name+".stderr", stderr.Bytes())
+	if metaFile != nil {
+		bundle.addFile(name+".meta", metaFile.Name())
+	}
+	return combined.buf.Bytes(), runErr
+}
@@ -0,0 +1,485 @@
+package grp_parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Report formats understood by ParseReport
+const (
+	FormatGTest  = "gtest"  // gtest JSON (--gtest_output=json)
+	FormatJUnit  = "junit"  // JUnit XML, also written by pytest --junitxml and most Java build tools
+	FormatTAP    = "tap"    // Test Anything Protocol
+	FormatPytest = "pytest" // pytest-json-report (--json-report)
+)
+
+// tapSuiteName is the test suite of TAP test points outside any subtest
+const tapSuiteName = "TAP"
+
+// DetectFormat guesses the format of a test report from its content
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return FormatJUnit
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var probe struct {
+			Tests json.RawMessage `json:"tests"`
+		}
+		// gtest 的 tests 是數量，pytest 的 tests 是測試列表
+		if json.Unmarshal(trimmed, &probe) == nil && bytes.HasPrefix(bytes.TrimSpace(probe.Tests), []byte("[")) {
+			return FormatPytest
+		}
+		return FormatGTest
+	default:
+		return FormatTAP
+	}
+}
+
+// ParseReport normalizes a test report in any supported format into the gtest structure
+func ParseReport(data []byte) (InputJSON, error) {
+	switch DetectFormat(data) {
+	case FormatJUnit:
+		return parseJUnit(data)
+	case FormatPytest:
+		return parsePytest(data)
+	case FormatTAP:
+		return parseTAP(data)
+	default:
+		var input InputJSON
+		if err := json.Unmarshal(data, &input); err != nil {
+			return InputJSON{}, fmt.Errorf("failed to parse input JSON: %v", err)
+		}
+		return input, nil
+	}
+}
+
+// scoreProperty encodes a score property the way gtest records it
+func scoreProperty(value string) json.RawMessage {
+	raw, _ := json.Marshal(strings.TrimSpace(value))
+	return raw
+}
+
+// formatSeconds formats a duration in seconds the way gtest does
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', -1, 64) + "s"
+}
+
+// addCase appends a test case to a suite and updates the suite's counters
+func (suite *TestSuite) addCase(tc TestCase) {
+	suite.Tests++
+	switch {
+	case len(tc.Errors) > 0:
+		suite.Errors++
+	case len(tc.Failures) > 0:
+		suite.Failures++
+	case tc.Result == "SKIPPED":
+		suite.Disabled++
+	}
+	suite.TestSuite = append(suite.TestSuite, tc)
+}
+
+// addSuite appends a test suite to the report and updates the report's counters
+func (input *InputJSON) addSuite(suite TestSuite) {
+	input.Tests += suite.Tests
+	input.Failures += suite.Failures
+	input.Errors += suite.Errors
+	input.Disabled += suite.Disabled
+	input.TestSuites = append(input.TestSuites, suite)
+}
+
+// newCase returns a test case that ran, with the gtest status and result
+func newCase(name, className string) TestCase {
+	return TestCase{
+		Name:      name,
+		ClassName: className,
+		Status:    "RUN",
+		Result:    "COMPLETED",
+	}
+}
+
+// === JUnit XML ===
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// text joins the message attribute and the body, either of which may be empty
+func (m junitMessage) text() string {
+	return strings.TrimSpace(strings.Join([]string{m.Message, strings.TrimSpace(m.Text)}, "\n"))
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	File       string          `xml:"file,attr"`
+	Line       int             `xml:"line,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Failures   []junitMessage  `xml:"failure"`
+	Errors     []junitMessage  `xml:"error"`
+	Skipped    *junitMessage   `xml:"skipped"`
+	Properties []junitProperty `xml:"properties>property"`
+}
+
+type junitSuite struct {
+	Name      string       `xml:"name,attr"`
+	Time      string       `xml:"time,attr"`
+	Timestamp string       `xml:"timestamp,attr"`
+	Cases     []junitCase  `xml:"testcase"`
+	Suites    []junitSuite `xml:"testsuite"`
+}
+
+type junitReport struct {
+	XMLName   xml.Name
+	Name      string       `xml:"name,attr"`
+	Time      string       `xml:"time,attr"`
+	Timestamp string       `xml:"timestamp,attr"`
+	Suites    []junitSuite `xml:"testsuite"`
+	Cases     []junitCase  `xml:"testcase"`
+}
+
+// parseJUnit converts a JUnit XML report, whose root is either <testsuites> or a single <testsuite>.
+// Nested suites are flattened; a test case records partial credit with a "score" property.
+func parseJUnit(data []byte) (InputJSON, error) {
+	var report junitReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return InputJSON{}, fmt.Errorf("failed to parse JUnit XML: %v", err)
+	}
+
+	input := InputJSON{Name: "AllTests", Timestamp: report.Timestamp}
+	if report.Time != "" {
+		input.Time = report.Time + "s"
+	}
+	suites := report.Suites
+	if report.XMLName.Local == "testsuite" {
+		suites = []junitSuite{{Name: report.Name, Time: report.Time, Timestamp: report.Timestamp, Cases: report.Cases, Suites: report.Suites}}
+	} else if report.XMLName.Local != "testsuites" {
+		return InputJSON{}, fmt.Errorf("unexpected JUnit root element <%s>", report.XMLName.Local)
+	}
+
+	var walk func(js junitSuite)
+	walk = func(js junitSuite) {
+		if len(js.Cases) > 0 {
+			suite := TestSuite{Name: js.Name, Timestamp: js.Timestamp}
+			if js.Time != "" {
+				suite.Time = js.Time + "s"
+			}
+			for _, jc := range js.Cases {
+				tc := newCase(jc.Name, jc.ClassName)
+				tc.File, tc.Line, tc.Timestamp = jc.File, jc.Line, jc.Timestamp
+				if jc.Time != "" {
+					tc.Time = jc.Time + "s"
+				}
+				for _, f := range jc.Failures {
+					tc.Failures = append(tc.Failures, Failure{Failure: f.text(), Type: f.Type})
+				}
+				for _, e := range jc.Errors {
+					tc.Errors = append(tc.Errors, Error{Error: e.text(), Type: e.Type})
+				}
+				if jc.Skipped != nil {
+					tc.Status, tc.Result = "NOTRUN", "SKIPPED"
+				}
+				for _, p := range jc.Properties {
+					if p.Name == "score" {
+						tc.Score = scoreProperty(p.Value)
+					}
+				}
+				suite.addCase(tc)
+			}
+			input.addSuite(suite)
+		}
+		for _, nested := range js.Suites {
+			walk(nested)
+		}
+	}
+	for _, js := range suites {
+		walk(js)
+	}
+	return input, nil
+}
+
+// === pytest-json-report ===
+
+type pytestStage struct {
+	Duration float64 `json:"duration"`
+	Outcome  string  `json:"outcome"`
+	Longrepr string  `json:"longrepr"`
+	Crash    *struct {
+		Message string `json:"message"`
+	} `json:"crash"`
+}
+
+type pytestTest struct {
+	NodeID         string            `json:"nodeid"`
+	Lineno         int               `json:"lineno"`
+	Outcome        string            `json:"outcome"`
+	Setup          *pytestStage      `json:"setup"`
+	Call           *pytestStage      `json:"call"`
+	Teardown       *pytestStage      `json:"teardown"`
+	UserProperties []json.RawMessage `json:"user_properties"`
+}
+
+type pytestReport struct {
+	Created  float64      `json:"created"`
+	Duration float64      `json:"duration"`
+	Tests    []pytestTest `json:"tests"`
+}
+
+// score returns the test's "score" user property recorded with record_property, which the report
+// writes either as {"score": value} or as a [name, value] pair
+func (t pytestTest) score() json.RawMessage {
+	for _, raw := range t.UserProperties {
+		var named map[string]json.RawMessage
+		if json.Unmarshal(raw, &named) == nil {
+			if v, ok := named["score"]; ok {
+				return v
+			}
+			continue
+		}
+		var pair []json.RawMessage
+		if json.Unmarshal(raw, &pair) == nil && len(pair) == 2 && strings.Trim(string(pair[0]), `"`) == "score" {
+			return pair[1]
+		}
+	}
+	return nil
+}
+
+// failure returns the message of the first stage that did not pass
+func (t pytestTest) failure() string {
+	for _, stage := range []*pytestStage{t.Setup, t.Call, t.Teardown} {
+		if stage == nil || stage.Outcome == "passed" || stage.Outcome == "" {
+			continue
+		}
+		if stage.Longrepr != "" {
+			return stage.Longrepr
+		}
+		if stage.Crash != nil {
+			return stage.Crash.Message
+		}
+	}
+	return t.Outcome
+}
+
+// parsePytest converts a pytest-json-report report. Tests are grouped into suites by their node ID
+// without the test name: tests/test_list.py::TestAppend::test_empty belongs to tests/test_list.py::TestAppend.
+func parsePytest(data []byte) (InputJSON, error) {
+	var report pytestReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return InputJSON{}, fmt.Errorf("failed to parse pytest JSON report: %v", err)
+	}
+
+	input := InputJSON{Name: "AllTests", Time: formatSeconds(report.Duration)}
+	suites := make(map[string]*TestSuite)
+	var order []string
+	for _, t := range report.Tests {
+		suiteName, name := t.NodeID, t.NodeID
+		if i := strings.LastIndex(t.NodeID, "::"); i >= 0 {
+			suiteName, name = t.NodeID[:i], t.NodeID[i+2:]
+		}
+		suite, exists := suites[suiteName]
+		if !exists {
+			suite = &TestSuite{Name: suiteName}
+			suites[suiteName] = suite
+			order = append(order, suiteName)
+		}
+
+		file, _, _ := strings.Cut(t.NodeID, "::")
+		tc := newCase(name, suiteName)
+		tc.File, tc.Line, tc.Score = file, t.Lineno, t.score()
+		if t.Call != nil {
+			tc.Time = formatSeconds(t.Call.Duration)
+		}
+		switch t.Outcome {
+		case "failed":
+			tc.Failures = []Failure{{Failure: t.failure(), Type: ""}}
+		case "error":
+			tc.Errors = []Error{{Error: t.failure(), Type: ""}}
+		case "skipped", "xfailed":
+			tc.Status, tc.Result = "NOTRUN", "SKIPPED"
+		}
+		suite.addCase(tc)
+	}
+	for _, name := range order {
+		input.addSuite(*suites[name])
+	}
+	return input, nil
+}
+
+// === TAP ===
+
+var (
+	tapTestPoint = regexp.MustCompile(`^(not ok|ok)\b\s*(\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(.*))?$`)
+	tapSubtest   = regexp.MustCompile(`^#\s*Subtest:\s*(.*)$`)
+	tapPlan      = regexp.MustCompile(`^1\.\.(\d+)`)
+)
+
+// addMissingTAPPoints adds a failing case for every test point the plan promised beyond the
+// reported ones, so output cut short by a crash or a timeout does not score as complete
+func (suite *TestSuite) addMissingTAPPoints(reported, plan int) {
+	for i := reported + 1; i <= plan; i++ {
+		tc := newCase(fmt.Sprintf("test point %d", i), suite.Name)
+		tc.Status, tc.Result = "NOTRUN", "MISSING"
+		tc.Failures = []Failure{{Failure: fmt.Sprintf("Test point %d of the plan 1..%d was never reported; the tests may have crashed or timed out", i, plan), Type: ""}}
+		suite.addCase(tc)
+	}
+}
+
+// tapCase converts a TAP test point
+func tapCase(ok bool, description, directive, className string) TestCase {
+	tc := newCase(description, className)
+	upper := strings.ToUpper(directive)
+	switch {
+	case strings.HasPrefix(upper, "SKIP"), strings.HasPrefix(upper, "TODO"):
+		// TODO 的失敗依 TAP 規範不算失敗
+		tc.Status, tc.Result = "NOTRUN", "SKIPPED"
+	case !ok:
+		tc.Failures = []Failure{{Failure: "not ok", Type: ""}}
+	}
+	return tc
+}
+
+// parseTAP converts a TAP report. Each subtest (an indented block, optionally introduced by
+// "# Subtest: name") becomes a test suite named after the subtest; test points outside subtests
+// belong to the suite "TAP". YAML diagnostics after a failing test point become its failure message.
+// Test points that a plan (1..N) promises but that were never reported count as failures.
+func parseTAP(data []byte) (InputJSON, error) {
+	input := InputJSON{Name: "AllTests"}
+	topLevel := TestSuite{Name: tapSuiteName}
+	var subtest *TestSuite
+	var last *TestCase
+	var yaml []string
+	inYAML, seen := false, false
+	// 計畫的測試點數量與頂層已回報的測試點數量（包含結束子測試的測試點）
+	topPlan, subPlan, topReported := 0, 0, 0
+
+	// 子測試結束時補上缺少的測試點並成為一個 suite
+	finishSubtest := func() {
+		if subtest.Name == "" {
+			subtest.Name = tapSuiteName
+		}
+		for i := range subtest.TestSuite {
+			subtest.TestSuite[i].ClassName = subtest.Name
+		}
+		subtest.addMissingTAPPoints(len(subtest.TestSuite), subPlan)
+		input.addSuite(*subtest)
+		subtest, last, subPlan = nil, nil, 0
+	}
+
+	// 把 YAML 診斷資訊附加到前一個失敗的測試
+	flushYAML := func() {
+		if last != nil && len(last.Failures) > 0 && len(yaml) > 0 {
+			last.Failures[0].Failure = strings.Join(yaml, "\n")
+		}
+		yaml = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if inYAML {
+			if trimmed == "..." {
+				inYAML = false
+				flushYAML()
+			} else {
+				yaml = append(yaml, trimmed)
+			}
+			continue
+		}
+		if trimmed == "---" {
+			inYAML = true
+			continue
+		}
+		if strings.HasPrefix(trimmed, "Bail out!") {
+			tc := newCase("Bail out", tapSuiteName)
+			tc.Failures = []Failure{{Failure: trimmed, Type: ""}}
+			topLevel.addCase(tc)
+			seen = true
+			break
+		}
+
+		if m := tapSubtest.FindStringSubmatch(trimmed); m != nil {
+			if subtest == nil || len(subtest.TestSuite) > 0 {
+				subtest, subPlan = &TestSuite{}, 0
+			}
+			subtest.Name = m[1]
+			continue
+		}
+		if m := tapPlan.FindStringSubmatch(trimmed); m != nil {
+			plan, _ := strconv.Atoi(m[1])
+			if indent >= 4 {
+				if subtest == nil {
+					subtest = &TestSuite{}
+				}
+				subPlan = plan
+			} else {
+				topPlan = plan
+			}
+			seen = true
+			continue
+		}
+		m := tapTestPoint.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+		seen = true
+
+		if indent >= 4 {
+			// 子測試內的測試點
+			if subtest == nil {
+				subtest = &TestSuite{}
+			}
+			subtest.addCase(tapCase(m[1] == "ok", m[3], m[4], subtest.Name))
+			last = &subtest.TestSuite[len(subtest.TestSuite)-1]
+			continue
+		}
+
+		topReported++
+		if subtest != nil && len(subtest.TestSuite) > 0 {
+			// 子測試結束的測試點，子測試本身成為一個 suite
+			if subtest.Name == "" {
+				subtest.Name = m[3]
+			}
+			finishSubtest()
+			continue
+		}
+		subtest, subPlan = nil, 0
+		topLevel.addCase(tapCase(m[1] == "ok", m[3], m[4], tapSuiteName))
+		last = &topLevel.TestSuite[len(topLevel.TestSuite)-1]
+	}
+	if err := scanner.Err(); err != nil {
+		return InputJSON{}, fmt.Errorf("failed to read TAP: %v", err)
+	}
+	if !seen {
+		return InputJSON{}, fmt.Errorf("failed to parse TAP: no test points found")
+	}
+
+	// 輸出中斷時，未結束的子測試與計畫中缺少的測試點都算失敗
+	if subtest != nil && (len(subtest.TestSuite) > 0 || subPlan > 0) {
+		// 未結束的子測試已是一個 suite，不再重複計為缺少的頂層測試點
+		topReported++
+		finishSubtest()
+	}
+	topLevel.addMissingTAPPoints(topReported, topPlan)
+	if len(topLevel.TestSuite) > 0 {
+		input.addSuite(topLevel)
+	}
+	return input, nil
+}
@@ -0,0 +1,183 @@
+package grp_parser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type suiteCounts struct {
+	tests, failures, errors, disabled int
+}
+
+func TestParseReportFixtures(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		format   string
+		suites   map[string]suiteCounts
+		scoreMap []ScoreTestSuite
+		want     float64
+	}{
+		{
+			fixture: "junit.xml",
+			format:  FormatJUnit,
+			suites: map[string]suiteCounts{
+				"ListTest": {tests: 4, failures: 1, disabled: 1},
+				"MapTest":  {tests: 2, errors: 1},
+			},
+			scoreMap: []ScoreTestSuite{{TestSuite: "ListTest", Score: 100}, {TestSuite: "MapTest", Score: 10}},
+			// ListTest: 1 + 0 + 0.5 + 0 (skipped) of 4; MapTest: 1 of 2
+			want: 37.5 + 5,
+		},
+		{
+			fixture: "pytest.json",
+			format:  FormatPytest,
+			suites: map[string]suiteCounts{
+				"tests/test_list.py::TestList": {tests: 4, failures: 1, disabled: 1},
+				"tests/test_map.py":            {tests: 2, errors: 1},
+			},
+			scoreMap: []ScoreTestSuite{{TestSuite: "tests/test_list.py::TestList", Score: 100}, {TestSuite: "tests/test_map.py", Score: 10}},
+			// TestList: 1 + 0 + 0.25 + 0 (skipped) of 4; test_map: 0.5 + 0 of 2
+			want: 31.25 + 2.5,
+		},
+		{
+			fixture: "tap.txt",
+			format:  FormatTAP,
+			suites: map[string]suiteCounts{
+				"ListTest":   {tests: 3, failures: 1, disabled: 1},
+				tapSuiteName: {tests: 3, failures: 1, disabled: 1},
+			},
+			scoreMap: []ScoreTestSuite{{TestSuite: "ListTest", Score: 30}, {TestSuite: tapSuiteName, Score: 30}},
+			// SKIP and TODO points earn nothing but stay in the denominator
+			want: 10 + 10,
+		},
+		{
+			fixture: "tap_truncated.txt",
+			format:  FormatTAP,
+			suites: map[string]suiteCounts{
+				"MapTest":    {tests: 4, failures: 2},
+				tapSuiteName: {tests: 2, failures: 1},
+			},
+			scoreMap: []ScoreTestSuite{{TestSuite: "MapTest", Score: 40}, {TestSuite: tapSuiteName, Score: 10}},
+			// the unreported points of both plans count as failures
+			want: 20 + 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format := DetectFormat(data); format != tt.format {
+				t.Errorf("DetectFormat = %q, want %q", format, tt.format)
+			}
+
+			input, err := ParseReport(data)
+			if err != nil {
+				t.Fatalf("ParseReport: %v", err)
+			}
+			if len(input.TestSuites) != len(tt.suites) {
+				t.Errorf("got %d suites, want %d", len(input.TestSuites), len(tt.suites))
+			}
+			var total suiteCounts
+			for _, suite := range input.TestSuites {
+				want, ok := tt.suites[suite.Name]
+				if !ok {
+					t.Errorf("unexpected suite %q", suite.Name)
+					continue
+				}
+				got := suiteCounts{suite.Tests, suite.Failures, suite.Errors, suite.Disabled}
+				if got != want {
+					t.Errorf("suite %s counts = %+v, want %+v", suite.Name, got, want)
+				}
+				if len(suite.TestSuite) != suite.Tests {
+					t.Errorf("suite %s has %d cases but counts %d tests", suite.Name, len(suite.TestSuite), suite.Tests)
+				}
+				total.tests += got.tests
+				total.failures += got.failures
+				total.errors += got.errors
+				total.disabled += got.disabled
+			}
+			if got := (suiteCounts{input.Tests, input.Failures, input.Errors, input.Disabled}); got != total {
+				t.Errorf("report counts = %+v, want %+v", got, total)
+			}
+
+			scoreMap, _ := json.Marshal(ScoreJSON{TestSuites: tt.scoreMap})
+			parser, err := NewJSONParser(data, scoreMap)
+			if err != nil {
+				t.Fatalf("NewJSONParser: %v", err)
+			}
+			parser.Parse()
+			assertScore(t, parser.GetScore(), tt.want)
+		})
+	}
+}
+
+func TestParseTAPDiagnostics(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "tap.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := parseTAP(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, suite := range input.TestSuites {
+		for _, tc := range suite.TestSuite {
+			if tc.Name == "pop" {
+				if len(tc.Failures) != 1 || tc.Failures[0].Failure != "message: expected 3, got 2" {
+					t.Errorf("pop failures = %+v, want the YAML diagnostics", tc.Failures)
+				}
+				return
+			}
+		}
+	}
+	t.Error("test point pop not found")
+}
+
+func TestParseTAPMissingPoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		tap         string
+		wantMissing map[string]int
+	}{
+		{"complete plan", "1..2\nok 1\nok 2\n", map[string]int{tapSuiteName: 0}},
+		{"short top-level plan", "1..3\nok 1\n", map[string]int{tapSuiteName: 2}},
+		{"plan at the end", "ok 1\nnot ok 2\n1..4\n", map[string]int{tapSuiteName: 2}},
+		{"short subtest plan", "1..1\n    1..3\n    ok 1\nok 1 - Sub\n", map[string]int{"Sub": 2}},
+		{"bail out", "1..3\nok 1\nBail out! database down\n", map[string]int{tapSuiteName: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := parseTAP([]byte(tt.tap))
+			if err != nil {
+				t.Fatal(err)
+			}
+			missing := make(map[string]int)
+			for _, suite := range input.TestSuites {
+				for _, tc := range suite.TestSuite {
+					if tc.Result == "MISSING" {
+						if tc.passed() || !strings.HasPrefix(tc.Name, "test point ") {
+							t.Errorf("missing point %+v should be a failing test point", tc)
+						}
+						missing[suite.Name]++
+					}
+				}
+			}
+			for name, want := range tt.wantMissing {
+				if missing[name] != want {
+					t.Errorf("suite %s has %d missing points, want %d", name, missing[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTAPWithoutTestPoints(t *testing.T) {
+	if _, err := parseTAP([]byte("TAP version 14\n# nothing here\n")); err == nil {
+		t.Error("parseTAP succeeded without test points, want an error")
+	}
+}
@@ -1,3 +0,0 @@
-module grp_parser
-
-go 1.21
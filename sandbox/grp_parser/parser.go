@@ -0,0 +1,392 @@
+// Package grp_parser scores test reports (gtest JSON, JUnit XML, TAP or pytest JSON) against a
+// question's score map. The sandbox-server runs it outside the box, so submissions cannot write
+// their own score.
+package grp_parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var boxPathRegex = regexp.MustCompile(`(?m)/[^:\n]*/box/`)
+
+// Scoring functions of a test suite
+const (
+	ScoreProportional = "proportional"   // the share of passed cases, the default
+	ScoreAllOrNothing = "all_or_nothing" // full points only if every case passes
+	ScoreMinOfCases   = "min"            // the lowest case score
+)
+
+// Feedback levels of a test suite shown to students
+const (
+	VisibilityPublic  = "public"  // every case with its failure messages, the default
+	VisibilitySummary = "summary" // whether each case passed, without failure messages
+	VisibilityHidden  = "hidden"  // counted in the score but not shown before the deadline
+)
+
+// TestSuite represents a test suite structure from the input JSON
+type TestSuite struct {
+	Name      string     `json:"name"`
+	MaxScore  int        `json:"maxscore"`
+	GetScore  float64    `json:"getscore"`
+	Tests     int        `json:"tests"`
+	Failures  int        `json:"failures"`
+	Disabled  int        `json:"disabled"`
+	Errors    int        `json:"errors"`
+	Timestamp string     `json:"timestamp"`
+	Time      string     `json:"time"`
+	TestSuite []TestCase `json:"testsuite"`
+
+	Mode              string   `json:"mode,omitempty"`               // scoring function applied to the suite
+	UnmetDependencies []string `json:"unmet_dependencies,omitempty"` // dependencies that did not pass, zeroing the suite's score
+	Visibility        string   `json:"visibility,omitempty"`         // feedback level shown to students
+}
+
+// TestCase represents individual test case
+type TestCase struct {
+	Name      string    `json:"name"`
+	File      string    `json:"file"`
+	Line      int       `json:"line"`
+	Status    string    `json:"status"`
+	Result    string    `json:"result"`
+	Timestamp string    `json:"timestamp"`
+	Time      string    `json:"time"`
+	ClassName string    `json:"classname"`
+	Failures  []Failure `json:"failures,omitempty"`
+	Errors    []Error   `json:"errors,omitempty"`
+	// Partial credit between 0 and 1 recorded by the test with RecordProperty("score", ...)
+	Score json.RawMessage `json:"score,omitempty"`
+}
+
+// skipped reports whether the test case did not run, e.g. pytest.skip() or a JUnit <skipped>
+func (tc TestCase) skipped() bool {
+	return tc.Result == "SKIPPED"
+}
+
+// passed reports whether the test case ran without failures or errors. Skipped cases do not pass,
+// since the code under test can skip a test on purpose.
+func (tc TestCase) passed() bool {
+	return len(tc.Failures) == 0 && len(tc.Errors) == 0 && !tc.skipped()
+}
+
+// score returns the case's score between 0 and 1: 0 if it was skipped, its recorded score property
+// if any, otherwise 1 if it passed and 0 if not
+func (tc TestCase) score() float64 {
+	if tc.skipped() {
+		return 0
+	}
+	if len(tc.Score) > 0 {
+		// gtest 以字串輸出 property，兩種格式都接受
+		raw := strings.Trim(string(tc.Score), `"`)
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			return min(max(v, 0), 1)
+		}
+	}
+	if tc.passed() {
+		return 1
+	}
+	return 0
+}
+
+// Failure represents a test failure
+type Failure struct {
+	Failure string `json:"failure"`
+	Type    string `json:"type"`
+}
+
+// Error represents a test error
+type Error struct {
+	Error string `json:"error"`
+	Type  string `json:"type"`
+}
+
+// InputJSON represents the structure of the input JSON file
+type InputJSON struct {
+	Tests      int         `json:"tests"`
+	Failures   int         `json:"failures"`
+	Disabled   int         `json:"disabled"`
+	Errors     int         `json:"errors"`
+	Timestamp  string      `json:"timestamp"`
+	Time       string      `json:"time"`
+	Name       string      `json:"name"`
+	TestSuites []TestSuite `json:"testsuites"`
+	// Number of hidden suites left out by Redact; their scores still count
+	HiddenSuites int `json:"hidden_suites,omitempty"`
+}
+
+// ScoreTestSuite represents test suite scoring structure
+type ScoreTestSuite struct {
+	TestSuite  string   `json:"testsuite"`
+	Score      int      `json:"score"`
+	Mode       string   `json:"mode,omitempty"`       // overrides ScoreJSON.Mode for this suite
+	DependsOn  []string `json:"depends_on,omitempty"` // suites that must pass for this one to count
+	Visibility string   `json:"visibility,omitempty"` // overrides ScoreJSON.Visibility for this suite
+}
+
+// ScoreJSON represents the structure of the score JSON file
+type ScoreJSON struct {
+	HomeworkName string           `json:"homework_name"`
+	Semester     string           `json:"semester"`
+	Mode         string           `json:"mode,omitempty"`       // scoring function of every suite, proportional if empty
+	Visibility   string           `json:"visibility,omitempty"` // feedback level of every suite, public if empty
+	TestSuites   []ScoreTestSuite `json:"testsuites"`
+}
+
+// JSONParser handles JSON parsing and score calculation
+type JSONParser struct {
+	scoreFile ScoreJSON
+	inputFile InputJSON
+	score     float64
+	task      map[string]ScoreTestSuite
+}
+
+// NewJSONParser creates a new JSONParser from a test report in any format ParseReport understands
+// and a score map
+func NewJSONParser(input, scoreMap []byte) (*JSONParser, error) {
+	parser := &JSONParser{
+		task: make(map[string]ScoreTestSuite),
+	}
+
+	inputFile, err := ParseReport(input)
+	if err != nil {
+		return nil, err
+	}
+	parser.inputFile = inputFile
+
+	if err := json.Unmarshal(scoreMap, &parser.scoreFile); err != nil {
+		return nil, fmt.Errorf("failed to parse score JSON: %v", err)
+	}
+
+	if err := parser.parseScore(); err != nil {
+		return nil, err
+	}
+	return parser, nil
+}
+
+// parseScore parses the score configuration
+func (jp *JSONParser) parseScore() error {
+	if !validVisibility(jp.scoreFile.Visibility) {
+		return fmt.Errorf("unknown visibility %q", jp.scoreFile.Visibility)
+	}
+	for _, testSuite := range jp.scoreFile.TestSuites {
+		if testSuite.Mode == "" {
+			testSuite.Mode = jp.scoreFile.Mode
+		}
+		switch testSuite.Mode {
+		case "":
+			testSuite.Mode = ScoreProportional
+		case ScoreProportional, ScoreAllOrNothing, ScoreMinOfCases:
+		default:
+			return fmt.Errorf("unknown scoring mode %q for test suite %s", testSuite.Mode, testSuite.TestSuite)
+		}
+		if testSuite.Visibility == "" {
+			testSuite.Visibility = jp.scoreFile.Visibility
+		}
+		if !validVisibility(testSuite.Visibility) {
+			return fmt.Errorf("unknown visibility %q for test suite %s", testSuite.Visibility, testSuite.TestSuite)
+		}
+		jp.task[testSuite.TestSuite] = testSuite
+	}
+	return nil
+}
+
+// validVisibility reports whether v is a feedback level; empty means public
+func validVisibility(v string) bool {
+	switch v {
+	case "", VisibilityPublic, VisibilitySummary, VisibilityHidden:
+		return true
+	}
+	return false
+}
+
+// suiteRatio returns the share of a suite's points its cases earn under the scoring mode
+func suiteRatio(mode string, cases []TestCase) float64 {
+	switch mode {
+	case ScoreAllOrNothing:
+		for _, tc := range cases {
+			if !tc.passed() {
+				return 0
+			}
+		}
+		return 1
+	case ScoreMinOfCases:
+		ratio := 1.0
+		for _, tc := range cases {
+			ratio = min(ratio, tc.score())
+		}
+		return ratio
+	default:
+		total := 0.0
+		for _, tc := range cases {
+			total += tc.score()
+		}
+		return total / float64(len(cases))
+	}
+}
+
+// Parse calculates the score based on test results
+func (jp *JSONParser) Parse() {
+	suites := make(map[string]*TestSuite)
+	for i := range jp.inputFile.TestSuites {
+		suite := &jp.inputFile.TestSuites[i]
+		suites[suite.Name] = suite
+
+		for _, tc := range suite.TestSuite {
+			for j := range tc.Failures {
+				tc.Failures[j].Failure = boxPathRegex.ReplaceAllString(tc.Failures[j].Failure, "")
+			}
+		}
+
+		suite.Visibility = jp.scoreFile.Visibility
+		if task, exists := jp.task[suite.Name]; exists {
+			suite.Visibility = task.Visibility
+			if len(suite.TestSuite) > 0 {
+				suite.MaxScore = task.Score
+				suite.Mode = task.Mode
+				suite.GetScore = suiteRatio(task.Mode, suite.TestSuite) * float64(task.Score)
+			}
+		}
+	}
+
+	// 相依的 suite 全部通過才計分
+	passed := make(map[string]bool)
+	visiting := make(map[string]bool)
+	var passes func(name string) bool
+	passes = func(name string) bool {
+		if result, done := passed[name]; done {
+			return result
+		}
+		suite, exists := suites[name]
+		if !exists || visiting[name] || len(suite.TestSuite) == 0 {
+			return false // 缺少或循環相依視為未通過
+		}
+		visiting[name] = true
+		result := true
+		for _, tc := range suite.TestSuite {
+			if !tc.passed() {
+				result = false
+			}
+		}
+		for _, dep := range jp.task[name].DependsOn {
+			if !passes(dep) {
+				result = false
+			}
+		}
+		visiting[name] = false
+		passed[name] = result
+		return result
+	}
+
+	for i := range jp.inputFile.TestSuites {
+		suite := &jp.inputFile.TestSuites[i]
+		for _, dep := range jp.task[suite.Name].DependsOn {
+			if !passes(dep) {
+				suite.UnmetDependencies = append(suite.UnmetDependencies, dep)
+			}
+		}
+		if len(suite.UnmetDependencies) > 0 {
+			suite.GetScore = 0
+		}
+		jp.score += suite.GetScore
+	}
+}
+
+// GetScore returns the calculated score
+func (jp *JSONParser) GetScore() float64 {
+	return jp.score
+}
+
+// PublicOnly returns the score map of a practice run: only the public suites, without their
+// dependencies on suites that practice runs never run
+func (s ScoreJSON) PublicOnly() ScoreJSON {
+	public := make(map[string]bool)
+	for _, suite := range s.TestSuites {
+		visibility := suite.Visibility
+		if visibility == "" {
+			visibility = s.Visibility
+		}
+		if visibility == "" || visibility == VisibilityPublic {
+			public[suite.TestSuite] = true
+		}
+	}
+
+	practice := s
+	practice.TestSuites = nil
+	for _, suite := range s.TestSuites {
+		if !public[suite.TestSuite] {
+			continue
+		}
+		var deps []string
+		for _, dep := range suite.DependsOn {
+			if public[dep] {
+				deps = append(deps, dep)
+			}
+		}
+		suite.DependsOn = deps
+		practice.TestSuites = append(practice.TestSuites, suite)
+	}
+	return practice
+}
+
+// KeepPublic drops every suite that is not a public suite of the score map from the result and
+// the score. Practice runs use it so students only see, and are scored on, the sample tests even
+// when a test script ignores the suite filter.
+func (jp *JSONParser) KeepPublic() {
+	public := jp.inputFile.TestSuites[:0:0]
+	jp.score = 0
+	for _, suite := range jp.inputFile.TestSuites {
+		if _, exists := jp.task[suite.Name]; !exists {
+			continue
+		}
+		if suite.Visibility != "" && suite.Visibility != VisibilityPublic {
+			continue
+		}
+		public = append(public, suite)
+		jp.score += suite.GetScore
+	}
+	jp.inputFile.TestSuites = public
+}
+
+// Result returns the report with each suite's score filled in
+func (jp *JSONParser) Result() InputJSON {
+	return jp.inputFile
+}
+
+// Redact returns the report as a student may see it. Summary suites lose their failure messages;
+// hidden suites are left out unless showHidden is set, but their scores still count.
+func Redact(all InputJSON, showHidden bool) InputJSON {
+	redacted := all
+	redacted.TestSuites = make([]TestSuite, 0, len(all.TestSuites))
+	for _, suite := range all.TestSuites {
+		switch {
+		case suite.Visibility == VisibilityHidden && !showHidden:
+			redacted.Tests -= suite.Tests
+			redacted.Failures -= suite.Failures
+			redacted.Disabled -= suite.Disabled
+			redacted.Errors -= suite.Errors
+			redacted.HiddenSuites++
+			continue
+		case suite.Visibility == VisibilitySummary:
+			cases := make([]TestCase, len(suite.TestSuite))
+			for i, tc := range suite.TestSuite {
+				// 保留失敗的筆數，只移除訊息內容
+				failures := make([]Failure, len(tc.Failures))
+				for j, f := range tc.Failures {
+					failures[j] = Failure{Type: f.Type}
+				}
+				errs := make([]Error, len(tc.Errors))
+				for j, e := range tc.Errors {
+					errs[j] = Error{Type: e.Type}
+				}
+				tc.Failures, tc.Errors = failures, errs
+				cases[i] = tc
+			}
+			suite.TestSuite = cases
+		}
+		redacted.TestSuites = append(redacted.TestSuites, suite)
+	}
+	return redacted
+}
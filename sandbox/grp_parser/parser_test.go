@@ -0,0 +1,311 @@
+package grp_parser
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// gtestReport builds a gtest JSON report; each suite maps to the results of its cases, where a
+// case is "pass", "fail", "skip" or a recorded score such as "0.5"
+func gtestReport(t *testing.T, suites map[string][]string) []byte {
+	t.Helper()
+	input := InputJSON{Name: "AllTests"}
+	for name, results := range suites {
+		suite := TestSuite{Name: name}
+		for i, result := range results {
+			tc := newCase(name+string(rune('A'+i)), name)
+			switch result {
+			case "pass":
+			case "fail":
+				tc.Failures = []Failure{{Failure: "expected equality"}}
+			case "skip":
+				tc.Status, tc.Result = "NOTRUN", "SKIPPED"
+			default:
+				tc.Score = scoreProperty(result)
+			}
+			suite.addCase(tc)
+		}
+		input.addSuite(suite)
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func parseScore(t *testing.T, report []byte, scoreMap ScoreJSON) (*JSONParser, float64) {
+	t.Helper()
+	raw, err := json.Marshal(scoreMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser, err := NewJSONParser(report, raw)
+	if err != nil {
+		t.Fatalf("NewJSONParser: %v", err)
+	}
+	parser.Parse()
+	return parser, parser.GetScore()
+}
+
+func assertScore(t *testing.T, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("score = %v, want %v", got, want)
+	}
+}
+
+func TestScoringModes(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		cases []string
+		want  float64
+	}{
+		{"proportional by default", "", []string{"pass", "fail", "pass", "fail"}, 50},
+		{"proportional", ScoreProportional, []string{"pass", "pass", "pass", "fail"}, 75},
+		{"proportional partial credit", ScoreProportional, []string{"0.5", "pass"}, 75},
+		{"proportional score is clamped", ScoreProportional, []string{"1.5", "-1"}, 50},
+		{"skipped cases earn nothing", ScoreProportional, []string{"pass", "skip"}, 50},
+		{"skipped cases do not pass", ScoreAllOrNothing, []string{"pass", "skip"}, 0},
+		{"all or nothing passes", ScoreAllOrNothing, []string{"pass", "pass"}, 100},
+		{"all or nothing fails", ScoreAllOrNothing, []string{"pass", "fail"}, 0},
+		{"min of cases", ScoreMinOfCases, []string{"pass", "0.25", "0.5"}, 25},
+		{"min of cases with a failure", ScoreMinOfCases, []string{"pass", "fail"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := gtestReport(t, map[string][]string{"Suite": tt.cases})
+			_, score := parseScore(t, report, ScoreJSON{
+				TestSuites: []ScoreTestSuite{{TestSuite: "Suite", Score: 100, Mode: tt.mode}},
+			})
+			assertScore(t, score, tt.want)
+		})
+	}
+}
+
+func TestScoringModeDefaultsToScoreMap(t *testing.T) {
+	report := gtestReport(t, map[string][]string{"A": {"pass", "fail"}, "B": {"pass", "fail"}})
+	_, score := parseScore(t, report, ScoreJSON{
+		Mode: ScoreAllOrNothing,
+		TestSuites: []ScoreTestSuite{
+			{TestSuite: "A", Score: 10},
+			{TestSuite: "B", Score: 10, Mode: ScoreProportional},
+		},
+	})
+	assertScore(t, score, 5)
+}
+
+func TestInvalidScoreMap(t *testing.T) {
+	report := gtestReport(t, map[string][]string{"Suite": {"pass"}})
+	tests := []struct {
+		name     string
+		scoreMap ScoreJSON
+	}{
+		{"unknown mode", ScoreJSON{TestSuites: []ScoreTestSuite{{TestSuite: "Suite", Score: 1, Mode: "best"}}}},
+		{"unknown default mode", ScoreJSON{Mode: "best", TestSuites: []ScoreTestSuite{{TestSuite: "Suite", Score: 1}}}},
+		{"unknown visibility", ScoreJSON{TestSuites: []ScoreTestSuite{{TestSuite: "Suite", Score: 1, Visibility: "secret"}}}},
+		{"unknown default visibility", ScoreJSON{Visibility: "secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _ := json.Marshal(tt.scoreMap)
+			if _, err := NewJSONParser(report, raw); err == nil {
+				t.Error("NewJSONParser succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		report    map[string][]string
+		suites    []ScoreTestSuite
+		want      float64
+		wantUnmet map[string][]string
+	}{
+		{
+			name:   "met dependency",
+			report: map[string][]string{"Basic": {"pass"}, "Advanced": {"pass", "fail"}},
+			suites: []ScoreTestSuite{
+				{TestSuite: "Basic", Score: 10},
+				{TestSuite: "Advanced", Score: 20, DependsOn: []string{"Basic"}},
+			},
+			want: 20,
+		},
+		{
+			name:   "failed dependency",
+			report: map[string][]string{"Basic": {"pass", "fail"}, "Advanced": {"pass"}},
+			suites: []ScoreTestSuite{
+				{TestSuite: "Basic", Score: 10},
+				{TestSuite: "Advanced", Score: 20, DependsOn: []string{"Basic"}},
+			},
+			want:      5,
+			wantUnmet: map[string][]string{"Advanced": {"Basic"}},
+		},
+		{
+			name:   "transitive dependency",
+			report: map[string][]string{"A": {"fail"}, "B": {"pass"}, "C": {"pass"}},
+			suites: []ScoreTestSuite{
+				{TestSuite: "A", Score: 10},
+				{TestSuite: "B", Score: 10, DependsOn: []string{"A"}},
+				{TestSuite: "C", Score: 10, DependsOn: []string{"B"}},
+			},
+			want:      0,
+			wantUnmet: map[string][]string{"B": {"A"}, "C": {"B"}},
+		},
+		{
+			name:   "missing dependency",
+			report: map[string][]string{"Advanced": {"pass"}},
+			suites: []ScoreTestSuite{
+				{TestSuite: "Basic", Score: 10},
+				{TestSuite: "Advanced", Score: 20, DependsOn: []string{"Basic"}},
+			},
+			want:      0,
+			wantUnmet: map[string][]string{"Advanced": {"Basic"}},
+		},
+		{
+			name:   "dependency cycle",
+			report: map[string][]string{"A": {"pass"}, "B": {"pass"}, "C": {"pass"}},
+			suites: []ScoreTestSuite{
+				{TestSuite: "A", Score: 10, DependsOn: []string{"B"}},
+				{TestSuite: "B", Score: 10, DependsOn: []string{"A"}},
+				{TestSuite: "C", Score: 10},
+			},
+			want:      10,
+			wantUnmet: map[string][]string{"A": {"B"}, "B": {"A"}},
+		},
+		{
+			name:   "self dependency",
+			report: map[string][]string{"A": {"pass"}},
+			suites: []ScoreTestSuite{
+				{TestSuite: "A", Score: 10, DependsOn: []string{"A"}},
+			},
+			want:      0,
+			wantUnmet: map[string][]string{"A": {"A"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, score := parseScore(t, gtestReport(t, tt.report), ScoreJSON{TestSuites: tt.suites})
+			assertScore(t, score, tt.want)
+			for _, suite := range parser.Result().TestSuites {
+				want := tt.wantUnmet[suite.Name]
+				if len(suite.UnmetDependencies) != len(want) {
+					t.Errorf("suite %s unmet dependencies = %v, want %v", suite.Name, suite.UnmetDependencies, want)
+					continue
+				}
+				for i := range want {
+					if suite.UnmetDependencies[i] != want[i] {
+						t.Errorf("suite %s unmet dependencies = %v, want %v", suite.Name, suite.UnmetDependencies, want)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestKeepPublicAndRedact(t *testing.T) {
+	report := gtestReport(t, map[string][]string{"Sample": {"pass", "fail"}, "Graded": {"pass"}, "Hidden": {"pass"}})
+	scoreMap := ScoreJSON{TestSuites: []ScoreTestSuite{
+		{TestSuite: "Sample", Score: 10},
+		{TestSuite: "Graded", Score: 10, Visibility: VisibilitySummary},
+		{TestSuite: "Hidden", Score: 10, Visibility: VisibilityHidden},
+	}}
+
+	parser, score := parseScore(t, report, scoreMap)
+	assertScore(t, score, 25)
+
+	redacted := Redact(parser.Result(), false)
+	if len(redacted.TestSuites) != 2 || redacted.HiddenSuites != 1 {
+		t.Fatalf("Redact kept %d suites and hid %d, want 2 and 1", len(redacted.TestSuites), redacted.HiddenSuites)
+	}
+	for _, suite := range redacted.TestSuites {
+		if suite.Visibility != VisibilitySummary {
+			continue
+		}
+		for _, tc := range suite.TestSuite {
+			for _, f := range tc.Failures {
+				if f.Failure != "" {
+					t.Errorf("summary suite %s kept failure message %q", suite.Name, f.Failure)
+				}
+			}
+		}
+	}
+	if shown := Redact(parser.Result(), true); len(shown.TestSuites) != 3 {
+		t.Errorf("Redact with hidden suites shown kept %d suites, want 3", len(shown.TestSuites))
+	}
+
+	parser.KeepPublic()
+	assertScore(t, parser.GetScore(), 5)
+	if suites := parser.Result().TestSuites; len(suites) != 1 || suites[0].Name != "Sample" {
+		t.Errorf("KeepPublic kept %v, want only Sample", suites)
+	}
+}
+
+func TestPublicOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		scoreMap ScoreJSON
+		want     map[string][]string // kept suites and their dependencies
+	}{
+		{
+			name: "public by default",
+			scoreMap: ScoreJSON{TestSuites: []ScoreTestSuite{
+				{TestSuite: "Sample", Score: 10},
+				{TestSuite: "Graded", Score: 10, Visibility: VisibilitySummary},
+				{TestSuite: "Hidden", Score: 10, Visibility: VisibilityHidden},
+			}},
+			want: map[string][]string{"Sample": nil},
+		},
+		{
+			name: "hidden by default",
+			scoreMap: ScoreJSON{Visibility: VisibilityHidden, TestSuites: []ScoreTestSuite{
+				{TestSuite: "Sample", Score: 10, Visibility: VisibilityPublic},
+				{TestSuite: "Hidden", Score: 10},
+			}},
+			want: map[string][]string{"Sample": nil},
+		},
+		{
+			name: "dependencies on suites that are not run",
+			scoreMap: ScoreJSON{TestSuites: []ScoreTestSuite{
+				{TestSuite: "Basic", Score: 10},
+				{TestSuite: "Hidden", Score: 10, Visibility: VisibilityHidden},
+				{TestSuite: "Sample", Score: 10, DependsOn: []string{"Basic", "Hidden"}},
+			}},
+			want: map[string][]string{"Basic": nil, "Sample": {"Basic"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			public := tt.scoreMap.PublicOnly()
+			if len(public.TestSuites) != len(tt.want) {
+				t.Fatalf("PublicOnly kept %v, want %v", public.TestSuites, tt.want)
+			}
+			for _, suite := range public.TestSuites {
+				want, ok := tt.want[suite.TestSuite]
+				if !ok {
+					t.Errorf("PublicOnly kept %s", suite.TestSuite)
+					continue
+				}
+				if strings.Join(suite.DependsOn, ",") != strings.Join(want, ",") {
+					t.Errorf("suite %s depends on %v, want %v", suite.TestSuite, suite.DependsOn, want)
+				}
+			}
+		})
+	}
+}
+
+func TestKeepPublicDropsUnknownSuites(t *testing.T) {
+	report := gtestReport(t, map[string][]string{"Sample": {"pass"}, "Unlisted": {"pass"}})
+	parser, _ := parseScore(t, report, ScoreJSON{TestSuites: []ScoreTestSuite{{TestSuite: "Sample", Score: 10}}})
+	parser.KeepPublic()
+	if suites := parser.Result().TestSuites; len(suites) != 1 || suites[0].Name != "Sample" {
+		t.Errorf("KeepPublic kept %v, want only Sample", suites)
+	}
+	assertScore(t, parser.GetScore(), 10)
+}
@@ -1,5 +1,7 @@
 package sandbox
 
+import "OJ-API/sandbox/grp_parser"
+
 /* runHandler.go
  * JudgeResult: Store judge result such as AC、WA、RE、CE
  * SandboxCompileResult: Compile result when using sandbox judge, execute, calculate score
@@ -21,6 +23,7 @@ const (
 	RUNTIME_ERROR         JudgeResult = "RUNTIME_ERROR"
 	TIME_LIMIT_EXCEEDED   JudgeResult = "TIME_LIMIT_EXCEEDED"
 	MEMORY_LIMIT_EXCEEDED JudgeResult = "MEMORY_LIMIT_EXCEEDED"
+	CANCELLED             JudgeResult = "CANCELLED"
 )
 
 type SandboxJudgeResult struct {
@@ -34,6 +37,8 @@ type SandboxScoreResult struct {
 	Status string  `json:"status"`
 	Score  float64 `json:"score"`
 	Result string  `json:"result"`
+
+	Report *AllTests `json:"-"` // 分數最高的報告，由 sandbox-server 在 box 外計分
 }
 
 type SandboxResult struct {
@@ -44,51 +49,19 @@ type SandboxResult struct {
 
 type CompileTask struct {
 	Target string   `json:"target"`
-	Suite  []string `json:"suite"`
+	Suite  []string `json:"suite"` // target 執行的 suite，練習評測只執行含公開 suite 的 target
 }
 
 type CompileFile struct {
 	Task []CompileTask `json:"task"`
 }
 
-/* result.go */
-type Failure struct {
-	Failure string `json:"failure"`
-	Type    string `json:"type"`
-}
-
-type TestCase struct {
-	Name      string    `json:"name"`
-	File      string    `json:"file"`
-	Line      int       `json:"line"`
-	Status    string    `json:"status"`
-	Result    string    `json:"result"`
-	Timestamp string    `json:"timestamp"`
-	Time      string    `json:"time"`
-	Classname string    `json:"classname"`
-	Failures  []Failure `json:"failures,omitempty"`
-}
-
-type TestSuite struct {
-	Name      string     `json:"name"`
-	MaxScore  int        `json:"maxscore"`
-	GetScore  int        `json:"getscore"`
-	Tests     int        `json:"tests"`
-	Failures  int        `json:"failures"`
-	Disabled  int        `json:"disabled"`
-	Errors    int        `json:"errors"`
-	Timestamp string     `json:"timestamp"`
-	Time      string     `json:"time"`
-	TestSuite []TestCase `json:"testsuite"`
-}
-
-type AllTests struct {
-	Tests      int         `json:"tests"`
-	Failures   int         `json:"failures"`
-	Disabled   int         `json:"disabled"`
-	Errors     int         `json:"errors"`
-	Timestamp  string      `json:"timestamp"`
-	Time       string      `json:"time"`
-	Name       string      `json:"name"`
-	TestSuites []TestSuite `json:"testsuites"`
-}
+/* result.go
+ * 測資結果的格式沿用 grp_parser，由 sandbox-server 在 box 外解析計分
+ */
+type (
+	Failure   = grp_parser.Failure
+	TestCase  = grp_parser.TestCase
+	TestSuite = grp_parser.TestSuite
+	AllTests  = grp_parser.InputJSON
+)
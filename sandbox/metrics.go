@@ -0,0 +1,65 @@
+package sandbox
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	stageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "oj_judge_stage_duration_seconds",
+		Help:    "Duration of each judge stage (compile, execute, score).",
+		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 20, 30, 60},
+	}, []string{"stage"})
+	judgeVerdicts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "oj_judge_verdicts_total",
+		Help: "Finished judge jobs by verdict.",
+	}, []string{"verdict"})
+)
+
+// RegisterMetrics 註冊沙箱 box 狀態的指標，數值在抓取時才計算
+func (s *Sandbox) RegisterMetrics() {
+	boxes := func(state string, value func() float64) {
+		promauto.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "oj_sandbox_boxes",
+			Help:        "Isolate boxes by state.",
+			ConstLabels: prometheus.Labels{"state": state},
+		}, value)
+	}
+	boxes("available", func() float64 { return float64(s.AvailableCount()) })
+	boxes("processing", func() float64 { return float64(s.ProcessingCount()) })
+
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "oj_sandbox_box_utilization_ratio",
+		Help: "Fraction of isolate boxes that are in use.",
+	}, func() float64 {
+		if s.sandboxCount == 0 {
+			return 0
+		}
+		return float64(s.ProcessingCount()) / float64(s.sandboxCount)
+	})
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "oj_sandbox_jobs_waiting",
+		Help: "Jobs received from the scheduler that are waiting for a box.",
+	}, func() float64 {
+		return float64(s.WaitingCount())
+	})
+}
+
+// finalVerdict 依編譯、執行結果與分數判斷整體結果
+func finalVerdict(result SandboxResult, score float64) JudgeResult {
+	for _, r := range result.CompileResult {
+		if r.Status == string(COMPILE_ERROR) {
+			return COMPILE_ERROR
+		}
+	}
+	for _, r := range result.ExecuteResult {
+		if r.Status == string(RUNTIME_ERROR) {
+			return RUNTIME_ERROR
+		}
+	}
+	if score >= 100 {
+		return ACCEPTED
+	}
+	return WRONG_ANSWER
+}
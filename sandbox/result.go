@@ -1,11 +1,14 @@
 package sandbox
 
 import (
+	"OJ-API/sandbox/grp_parser"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
+	"syscall"
 	"time"
 )
 
@@ -29,7 +32,7 @@ func NewErrorResult(errType JudgeResult, errName string, errMsg string) string {
 		Result:    "ERROR",
 		Timestamp: now,
 		Time:      "0s",
-		Classname: "System",
+		ClassName: "System",
 		Failures:  []Failure{failure},
 	}
 
@@ -72,7 +75,7 @@ func generateErrorSuite(target, errName, errMsg string) TestSuite {
 		Result:    "ERROR",
 		Timestamp: now,
 		Time:      "0s",
-		Classname: "System",
+		ClassName: "System",
 		Failures:  []Failure{{Failure: errMsg, Type: ""}},
 	}
 
@@ -88,34 +91,107 @@ func generateErrorSuite(target, errName, errMsg string) TestSuite {
 	}
 }
 
-// --- 主流程：整合 message.txt + score.txt + failed result ---
-func MergeJudgeResults(baseDir string, finalResults []SandboxScoreResult) (AllTests, float64, error) {
-	var all AllTests
-	messagePath := filepath.Join(baseDir, "message.txt")
-	scorePath := filepath.Join(baseDir, "score.txt")
+// boxReportDir box 內存放原始測試報告（gtest JSON、JUnit XML、TAP 或 pytest JSON）的目錄，box 只負責產生報告，計分在 box 外進行。
+// 每個 target 由 sandbox-server 建立新的目錄並 bind mount 到這裡，box 可寫入的路徑不會被 sandbox-server 以 root 操作
+const boxReportDir = "/reports"
+
+// maxReportSize 單一報告的大小上限
+const maxReportSize = 16 << 20
+
+// reportCollectorScript 取代原本複製進 box 的 grp_parser 執行檔，沿用 `utils/grp_parser <report> <score.json>`
+// 的呼叫方式，但只把報告複製到 boxReportDir 供 sandbox-server 解析
+const reportCollectorScript = `#!/bin/sh
+cp "$1" "$(mktemp -p ` + boxReportDir + `)"
+`
+
+// scoreReport 一份在 box 外依 score map 計分的測試報告
+type scoreReport struct {
+	name  string
+	raw   []byte
+	score float64
+	all   AllTests
+}
+
+// readReportFile 以 O_NOFOLLOW 開啟報告，只讀取一般檔案且不超過大小上限
+func readReportFile(path string) ([]byte, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NOFOLLOW|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("not a regular file")
+	}
+	raw, err := io.ReadAll(io.LimitReader(f, maxReportSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxReportSize {
+		return nil, fmt.Errorf("report exceeds %d bytes", maxReportSize)
+	}
+	return raw, nil
+}
 
-	// 讀取 message.txt
-	data, err := os.ReadFile(messagePath)
+// readReports 讀取 box 產生在 sandbox-server 報告目錄中的測試報告並依 score map 計分，練習評測只保留公開的 suite。
+// 只讀取一般檔案，避免 submission 透過 symlink 讓 sandbox-server 讀取 box 外的檔案
+func readReports(dir string, scoreMap []byte, practice bool) ([]scoreReport, []error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		all = AllTests{
-			Tests:      0,
-			Failures:   0,
-			Name:       "AllTests",
-			Timestamp:  time.Now().UTC().Format(time.RFC3339),
-			TestSuites: []TestSuite{},
+		return nil, []error{err}
+	}
+
+	var reports []scoreReport
+	var errs []error
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		raw, err := readReportFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
 		}
-	} else {
-		_ = json.Unmarshal(data, &all)
+		parser, err := grp_parser.NewJSONParser(raw, scoreMap)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		parser.Parse()
+		if practice {
+			parser.KeepPublic()
+		}
+		reports = append(reports, scoreReport{
+			name:  entry.Name(),
+			raw:   raw,
+			score: parser.GetScore(),
+			all:   parser.Result(),
+		})
 	}
+	return reports, errs
+}
 
-	// 嘗試讀取分數（單一浮點數）
+// --- 主流程：整合各 target 最高分的報告 + failed result ---
+func MergeJudgeResults(finalResults []SandboxScoreResult) (AllTests, float64, error) {
+	all := AllTests{
+		Tests:      0,
+		Failures:   0,
+		Name:       "AllTests",
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		TestSuites: []TestSuite{},
+	}
+
+	// 與原本 grp_parser 相同，保留分數最高的報告
 	var totalScore float64 = 0
-	scoreData, err := os.ReadFile(scorePath)
-	if err == nil {
-		scoreStr := strings.TrimSpace(string(scoreData))
-		if s, parseErr := strconv.ParseFloat(scoreStr, 64); parseErr == nil {
-			totalScore = s
+	found := false
+	for _, r := range finalResults {
+		if r.Report == nil || (found && r.Score <= totalScore) {
+			continue
 		}
+		all, totalScore, found = *r.Report, r.Score, true
 	}
 
 	// 已存在 suite 索引（避免重複）
@@ -5,17 +5,22 @@ import (
 	"OJ-API/database"
 	"OJ-API/gitclone"
 	"OJ-API/models"
+	"OJ-API/sandbox/grp_parser"
+	"OJ-API/tracing"
 	"OJ-API/utils"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
 )
 
 const execTimeoutDuration = time.Second * 60
@@ -54,7 +59,7 @@ func (s *Sandbox) assignJob(ctx context.Context) {
 		job := s.ReleaseJob()
 		boxID, ok := s.Reserve(1 * time.Second)
 		if !ok {
-			s.ReserveJob(job.Repo, job.CodePath, job.UQR)
+			s.jobQueue.Enqueue(job)
 			continue
 		}
 		go s.runShellCommandByRepo(ctx, boxID, job)
@@ -67,22 +72,129 @@ type JudgeInfo struct {
 	BoxID          int
 	CodePath       []byte
 	UQR            models.UserQuestionTable
+	Practice       bool            // 練習評測，結果寫回 practice_runs
+	JobCtx         context.Context // 任務被取消時結束
+}
+
+// judgeRow 回傳評測結果要寫入的資料列；練習評測寫入 practice_runs，不影響正式成績
+func judgeRow(userQuestion models.UserQuestionTable, practice bool) *gorm.DB {
+	if practice {
+		return database.DBConn.Model(&models.PracticeRun{ID: userQuestion.ID})
+	}
+	return database.DBConn.Model(&userQuestion)
+}
+
+// updateJudgeRow 以評測欄位更新資料列；練習評測只寫入 PracticeRun 也有的分數、訊息與評測時間
+func updateJudgeRow(userQuestion models.UserQuestionTable, practice bool, updates models.UserQuestionTable) *gorm.DB {
+	if practice {
+		return judgeRow(userQuestion, practice).Updates(models.PracticeRun{
+			Score:     updates.Score,
+			Message:   updates.Message,
+			JudgeTime: updates.JudgeTime,
+		})
+	}
+	return judgeRow(userQuestion, practice).Updates(updates)
+}
+
+// practiceScoreMap 將 score map 限制為公開的 suite：只編譯、執行宣告了公開 suite 的 target，
+// 每個 target 只列出公開的 suite，box 內與計分用的 score map 也不含非公開 suite 的名稱
+func practiceScoreMap(scoreMap string) (CompileFile, string, error) {
+	var scoreFile grp_parser.ScoreJSON
+	if err := json.Unmarshal([]byte(scoreMap), &scoreFile); err != nil {
+		return CompileFile{}, "", err
+	}
+	var compileFile CompileFile
+	if err := json.Unmarshal([]byte(scoreMap), &compileFile); err != nil {
+		return CompileFile{}, "", err
+	}
+
+	public := scoreFile.PublicOnly()
+	publicSuites := make(map[string]bool)
+	for _, suite := range public.TestSuites {
+		publicSuites[suite.TestSuite] = true
+	}
+	var tasks []CompileTask
+	for _, task := range compileFile.Task {
+		var suites []string
+		for _, suite := range task.Suite {
+			if publicSuites[suite] {
+				suites = append(suites, suite)
+			}
+		}
+		if len(suites) == 0 {
+			continue
+		}
+		task.Suite = suites
+		tasks = append(tasks, task)
+	}
+
+	raw, err := json.Marshal(struct {
+		grp_parser.ScoreJSON
+		Task []CompileTask `json:"task"`
+	}{public, tasks})
+	if err != nil {
+		return CompileFile{}, "", err
+	}
+	return CompileFile{Task: tasks}, string(raw), nil
+}
+
+// suiteFilterArgs 回傳限制 target 只執行指定 suite 的環境變數；gtest 直接讀取 GTEST_FILTER，
+// 其他測試框架的 script 可讀取 OJ_TEST_SUITES（以冒號分隔）。正式評測不限制 suite
+func suiteFilterArgs(suites []string) []string {
+	if len(suites) == 0 {
+		return nil
+	}
+	gtestFilter := make([]string, len(suites))
+	for i, suite := range suites {
+		gtestFilter[i] = suite + ".*"
+	}
+	return []string{
+		"--env=GTEST_FILTER=" + strings.Join(gtestFilter, ":"),
+		"--env=OJ_TEST_SUITES=" + strings.Join(suites, ":"),
+	}
 }
 
 func (s *Sandbox) runShellCommand(parentCtx context.Context, judgeinfo JudgeInfo) {
-	db := database.DBConn
 	userQuestion := judgeinfo.UQR
+	practice := judgeinfo.Practice
 	boxID := judgeinfo.BoxID
 	codePath := judgeinfo.CodePath
 	mothercodePath := judgeinfo.MotherCodePath
 	cmd := judgeinfo.QuestionInfo
 	var scoreMap CompileFile
 	json.Unmarshal([]byte(cmd.ScoreMap), &scoreMap)
+	// 練習評測在執行前就只保留公開的 suite，非公開 suite 的測試不會在 box 內執行
+	var practiceErr error
+	if practice {
+		scoreMap, cmd.ScoreMap, practiceErr = practiceScoreMap(cmd.ScoreMap)
+		if practiceErr == nil && len(scoreMap.Task) == 0 {
+			practiceErr = fmt.Errorf("no target declares a public test suite")
+		}
+	}
+	// 每個 target 只執行的 suite；正式評測為 nil，不限制
+	var targetSuites map[string][]string
+	if practice {
+		targetSuites = make(map[string][]string)
+		for _, task := range scoreMap.Task {
+			targetSuites[task.Target] = task.Suite
+		}
+	}
+
+	// 未正常完成評測的任務都記為系統錯誤
+	verdict := SYSTEM_FAILED
+	defer func() {
+		judgeVerdicts.WithLabelValues(string(verdict)).Inc()
+		span := trace.SpanFromContext(judgeinfo.JobCtx)
+		span.SetAttributes(attribute.String("oj.verdict", string(verdict)))
+		if verdict == SYSTEM_FAILED {
+			span.SetStatus(codes.Error, string(verdict))
+		}
+	}()
 
 	// 檢查父 context 是否已經被取消，如果是則不開始新任務
 	select {
 	case <-parentCtx.Done():
-		db.Model(&userQuestion).Updates(models.UserQuestionTable{
+		updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
 			Score:   -2,
 			Message: NewErrorResult(WAITING_TO_JUDGE, "Judge Done", "Job cancelled due to server shutdown"),
 		})
@@ -91,29 +203,40 @@ func (s *Sandbox) runShellCommand(parentCtx context.Context, judgeinfo JudgeInfo
 	default:
 	}
 
-	db.Model(&userQuestion).Updates(models.UserQuestionTable{
+	updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
 		JudgeTime: time.Now().UTC(),
 	})
+	if practiceErr != nil {
+		updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
+			Score:   -2,
+			Message: NewErrorResult(SYSTEM_FAILED, "No public test suites", practiceErr.Error()),
+		})
+		os.RemoveAll(string(codePath))
+		os.RemoveAll(string(mothercodePath))
+		s.Release(boxID)
+		return
+	}
 
 	CopyDir(mothercodePath+"/test", string(codePath)+"/test")
 	boxRoot, _ := CopyCodeToBox(boxID, string(codePath))
 
 	defer s.Release(boxID)
 
-	db.Model(&userQuestion).Updates(models.UserQuestionTable{
+	updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
 		Score:   -1,
 		Message: NewErrorResult(JUDGING, "Judge", "Judging..."),
 	})
 
-	// 使用獨立的 context，不會被父 context 取消影響，讓任務完整執行
-	ctx, cancel := context.WithTimeout(context.Background(), execTimeoutDuration)
+	// 使用獨立的 context，不會被父 context 取消影響，讓任務完整執行；只有取消任務時才會中斷
+	jobCtx := judgeinfo.JobCtx
+	ctx, cancel := context.WithTimeout(jobCtx, execTimeoutDuration)
 	defer cancel()
 
 	// saving code as file
 	compileScript := []byte(cmd.CompileScript)
 	codeID, err := WriteToTempFile(compileScript, boxID)
 	if err != nil {
-		db.Model(&userQuestion).Updates(models.UserQuestionTable{
+		updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
 			Score:   -2,
 			Message: NewErrorResult(SYSTEM_FAILED, "System_Failed", err.Error()),
 		})
@@ -126,15 +249,13 @@ func (s *Sandbox) runShellCommand(parentCtx context.Context, judgeinfo JudgeInfo
 		// make utils dir at code path
 		os.MkdirAll(fmt.Sprintf("%v/%s", string(boxRoot), "utils"), 0755)
 
-		// copy grp_parser to code path using efficient Go file operations
-		srcPath := "./sandbox/grp_parser/grp_parser"
+		// box 內只放收集報告的 script，計分由 sandbox-server 在 box 外進行
 		dstPath := fmt.Sprintf("%v/%s/grp_parser", string(boxRoot), "utils")
-
-		if err := copyFile(srcPath, dstPath); err != nil {
-			utils.Debug(fmt.Sprintf("Failed to copy grp_parser: %v", err))
-			db.Model(&userQuestion).Updates(models.UserQuestionTable{
+		if err := os.WriteFile(dstPath, []byte(reportCollectorScript), 0755); err != nil {
+			utils.Debug(fmt.Sprintf("Failed to write report collector: %v", err))
+			updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
 				Score:   -2,
-				Message: NewErrorResult(SYSTEM_FAILED, "Failed to copy score parser", err.Error()),
+				Message: NewErrorResult(SYSTEM_FAILED, "Failed to write report collector", err.Error()),
 			})
 			return
 		}
@@ -144,13 +265,31 @@ func (s *Sandbox) runShellCommand(parentCtx context.Context, judgeinfo JudgeInfo
 	defer os.RemoveAll(string(codePath))
 	defer os.RemoveAll(string(mothercodePath))
 
+	// 保存各階段的原始輸出，供事後查核；需在 box 被清除前打包。評測產物以 UserQuestionTable ID 索引，練習評測不保存
+	var bundle *artifactBundle
+	if !practice {
+		bundle = newArtifactBundle(userQuestion, cmd.Question.GitRepoURL, boxID)
+	}
+	finalScore := 0.0
+	defer func() {
+		bundle.add("result/score_map.json", []byte(cmd.ScoreMap))
+		storeArtifacts(jobCtx, bundle, verdict, finalScore)
+	}()
+
 	var SandboxJudgeInfo SandboxResult
 
 	/*
 		Compile the code
 	*/
 
-	SandboxJudgeInfo.CompileResult = s.runCompile(boxID, ctx, shellFilename(codeID, boxID), []byte(boxRoot), scoreMap)
+	endStage := startStage(jobCtx, "compile")
+	SandboxJudgeInfo.CompileResult = s.runCompile(boxID, ctx, shellFilename(codeID, boxID), []byte(boxRoot), scoreMap, bundle)
+	endStage()
+	if jobCtx.Err() != nil {
+		verdict = CANCELLED
+		s.recordCancelled(boxID, userQuestion, practice, context.Cause(jobCtx))
+		return
+	}
 
 	/*
 		Execute the code
@@ -158,7 +297,7 @@ func (s *Sandbox) runShellCommand(parentCtx context.Context, judgeinfo JudgeInfo
 
 	execodeID, err := WriteToTempFile([]byte(cmd.ExecuteScript), boxID)
 	if err != nil {
-		db.Model(&userQuestion).Updates(models.UserQuestionTable{
+		updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
 			Score:   -2,
 			Message: NewErrorResult(SYSTEM_FAILED, "Failed to save code as file", err.Error()),
 		})
@@ -167,7 +306,14 @@ func (s *Sandbox) runShellCommand(parentCtx context.Context, judgeinfo JudgeInfo
 
 	defer os.Remove(shellFilename(execodeID, boxID))
 
-	SandboxJudgeInfo.ExecuteResult = s.runExecute(boxID, ctx, cmd, shellFilename(execodeID, boxID), []byte(boxRoot), SandboxJudgeInfo.CompileResult)
+	endStage = startStage(jobCtx, "execute")
+	SandboxJudgeInfo.ExecuteResult = s.runExecute(boxID, ctx, cmd, shellFilename(execodeID, boxID), []byte(boxRoot), SandboxJudgeInfo.CompileResult, targetSuites, bundle)
+	endStage()
+	if jobCtx.Err() != nil {
+		verdict = CANCELLED
+		s.recordCancelled(boxID, userQuestion, practice, context.Cause(jobCtx))
+		return
+	}
 	/*
 	*
 	*	Part for calculate score.
@@ -178,7 +324,7 @@ func (s *Sandbox) runShellCommand(parentCtx context.Context, judgeinfo JudgeInfo
 
 	scoreScriptID, err := WriteToTempFile([]byte(ScoreScript), boxID)
 	if err != nil {
-		db.Model(&userQuestion).Updates(models.UserQuestionTable{
+		updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
 			Score:   -2,
 			Message: NewErrorResult(SYSTEM_FAILED, "Failed to save code as file", err.Error()),
 		})
@@ -187,7 +333,14 @@ func (s *Sandbox) runShellCommand(parentCtx context.Context, judgeinfo JudgeInfo
 	defer os.Remove(shellFilename(execodeID, boxID))
 
 	compileAndExecuteResult := s.mergeCompileAndExecuteResult(SandboxJudgeInfo.CompileResult, SandboxJudgeInfo.ExecuteResult)
-	SandboxJudgeInfo.JudgeScoreResult = s.runScore(boxID, ctx, shellFilename(scoreScriptID, boxID), []byte(boxRoot), compileAndExecuteResult)
+	endStage = startStage(jobCtx, "score")
+	SandboxJudgeInfo.JudgeScoreResult = s.runScore(boxID, ctx, shellFilename(scoreScriptID, boxID), []byte(boxRoot), []byte(cmd.ScoreMap), practice, compileAndExecuteResult, targetSuites, bundle)
+	endStage()
+	if jobCtx.Err() != nil {
+		verdict = CANCELLED
+		s.recordCancelled(boxID, userQuestion, practice, context.Cause(jobCtx))
+		return
+	}
 
 	/*
 
@@ -198,49 +351,91 @@ func (s *Sandbox) runShellCommand(parentCtx context.Context, judgeinfo JudgeInfo
 	utils.Debug("Compilation and execution finished successfully.")
 	utils.Debug("Ready to proceed to the next step or return output.")
 
-	totalResult, score, _ := MergeJudgeResults(boxRoot, SandboxJudgeInfo.JudgeScoreResult)
+	totalResult, score, _ := MergeJudgeResults(SandboxJudgeInfo.JudgeScoreResult)
 
 	jsonBytes, err := json.MarshalIndent(totalResult, "", "  ")
 	if err != nil {
 		utils.Debugf("[runHandler] Failed to marshal totalResult: %v\n", err)
 	} else {
 		result := string(jsonBytes)
-		if err := db.Model(&userQuestion).Updates(models.UserQuestionTable{
-			Score:   score,
-			Message: strings.TrimSpace(string(result)),
-		}).Error; err != nil {
-			db.Model(&userQuestion).Updates(models.UserQuestionTable{
+		bundle.add("result/result.json", jsonBytes)
+		// 逾期繳交照常評測，另存扣分前的原始分數
+		finalScore = models.ApplyLatePenalty(score, userQuestion.LatePenalty)
+		updates := map[string]interface{}{
+			"score":   finalScore,
+			"message": strings.TrimSpace(string(result)),
+		}
+		if !practice {
+			updates["raw_score"] = score
+		}
+		if err := judgeRow(userQuestion, practice).Updates(updates).Error; err != nil {
+			updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
 				Score:   -2,
 				Message: NewErrorResult(SYSTEM_FAILED, "Failed to update score", err.Error()),
 			})
 			return
 		}
+		verdict = finalVerdict(SandboxJudgeInfo, score)
 	}
 
-	utils.Debug("Done for judge!")
+	utils.Ctx(jobCtx).Infof("Judge finished in box %v: verdict=%s", boxID, verdict)
 }
 
 func (s *Sandbox) runShellCommandByRepo(ctx context.Context, boxID int, work *Job) {
+	defer s.jobDone(work.Key())
 
 	db := database.DBConn
+
+	traceCtx := work.Ctx
+	if traceCtx == nil {
+		traceCtx = logContext(work.UQR)
+	}
+	tracing.RecordSpan(traceCtx, "sandbox.wait", work.QueuedAt, time.Now())
+	traceCtx, span := tracing.Start(traceCtx, "sandbox.judge", trace.WithAttributes(
+		attribute.String("oj.job_kind", work.Kind.String()),
+		attribute.Int64("oj.target_id", int64(work.UQR.ID)),
+		attribute.Int("oj.box_id", boxID),
+	))
+	defer span.End()
+
+	jobCtx, reason, ok := s.startJob(traceCtx, work.Key())
+	if !ok {
+		// 任務在開始評測前已被取消
+		updateJudgeRow(work.UQR, work.Practice(), models.UserQuestionTable{
+			Score:   -4,
+			Message: NewErrorResult(CANCELLED, "Cancelled", reason),
+		})
+		judgeVerdicts.WithLabelValues(string(CANCELLED)).Inc()
+		os.RemoveAll(string(work.CodePath))
+		s.Release(boxID)
+		return
+	}
+	defer s.finishJob(work.Key())
+	utils.Ctx(jobCtx).Infof("Judging %s in box %v", work.Repo, boxID)
 	var cmd models.QuestionTestScript
 	if err := db.Joins("Question").
 		Where("git_repo_url = ?", work.Repo).Take(&cmd).Error; err != nil {
-		db.Model(&work.UQR).Updates(models.UserQuestionTable{
+		utils.Ctx(jobCtx).Errorf("Failed to find shell command for %v: %v", work.Repo, err)
+		span.SetStatus(codes.Error, err.Error())
+		updateJudgeRow(work.UQR, work.Practice(), models.UserQuestionTable{
 			Score:   -2,
 			Message: fmt.Sprintf("Failed to find shell command for %v: %v", work.Repo, err),
 		})
+		judgeVerdicts.WithLabelValues(string(SYSTEM_FAILED)).Inc()
 		s.Release(boxID)
 		return
 	}
 	gitURL := config.GetGiteaBaseURL() + "/" + cmd.Question.GitRepoURL
-	mothercodepath, err := gitclone.CloneRepository(cmd.Question.GitRepoURL, gitURL, "", "", "")
+	mothercodepath, err := gitclone.FetchRepository(cmd.Question.GitRepoURL, gitURL, "", "", "")
 
 	if err != nil {
-		db.Model(&work.UQR).Updates(models.UserQuestionTable{
+		utils.Ctx(jobCtx).Errorf("Can't get test info: %v", err)
+		span.SetStatus(codes.Error, err.Error())
+		updateJudgeRow(work.UQR, work.Practice(), models.UserQuestionTable{
 			Score:   -2,
 			Message: fmt.Sprintf("Can't get test info: %v", err),
 		})
+		judgeVerdicts.WithLabelValues(string(SYSTEM_FAILED)).Inc()
 		s.Release(boxID)
 		return
 	}
@@ -251,11 +446,39 @@ func (s *Sandbox) runShellCommandByRepo(ctx context.Context, boxID int, work *Jo
 		BoxID:          boxID,
 		CodePath:       work.CodePath,
 		UQR:            work.UQR,
+		Practice:       work.Practice(),
+		JobCtx:         jobCtx,
 	}
 	s.runShellCommand(ctx, judgeinfo)
 }
 
-func (s *Sandbox) runCompile(box int, ctx context.Context, shellCommand string, codePath []byte, compilefile CompileFile) []SandboxJudgeResult {
+// logContext 回傳帶有提交請求 ID 與 UserQuestionTable ID 的 context，讓沙箱日誌能與 API 日誌串接
+func logContext(userQuestion models.UserQuestionTable) context.Context {
+	ctx := utils.WithRequestID(context.Background(), userQuestion.RequestID)
+	return utils.WithJobID(ctx, uint64(userQuestion.ID))
+}
+
+// startStage 開始評測階段的 span，回傳的函式結束 span 並記錄階段耗時
+func startStage(ctx context.Context, stage string) func() {
+	start := time.Now()
+	_, span := tracing.Start(ctx, "sandbox."+stage)
+	return func() {
+		stageDuration.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+		span.End()
+	}
+}
+
+// recordCancelled 結束 box 內的程序並記錄 CANCELLED 結果
+func (s *Sandbox) recordCancelled(boxID int, userQuestion models.UserQuestionTable, practice bool, cause error) {
+	s.killBox(boxID)
+	utils.Ctx(logContext(userQuestion)).Infof("Job %d cancelled in box %v: %v", userQuestion.ID, boxID, cause)
+	updateJudgeRow(userQuestion, practice, models.UserQuestionTable{
+		Score:   -4,
+		Message: NewErrorResult(CANCELLED, "Cancelled", cause.Error()),
+	})
+}
+
+func (s *Sandbox) runCompile(box int, ctx context.Context, shellCommand string, codePath []byte, compilefile CompileFile, bundle *artifactBundle) []SandboxJudgeResult {
 	var results []SandboxJudgeResult
 	for _, task := range compilefile.Task {
 		cmdArgs := []string{
@@ -276,13 +499,11 @@ func (s *Sandbox) runCompile(box int, ctx context.Context, shellCommand string,
 		scriptFile := shellCommand
 		cmdArgs = append(cmdArgs, "--run", "--", "/usr/bin/sh", scriptFile, task.Target)
 
-		cmd := exec.CommandContext(ctx, "isolate", cmdArgs...)
-
 		result := SandboxJudgeResult{
 			Target: task.Target,
 		}
 
-		out, err := cmd.CombinedOutput()
+		out, err := runIsolate(ctx, bundle, "compile", task.Target, cmdArgs)
 		if err != nil {
 			result.Status = string(COMPILE_ERROR)
 			result.Result = string(out)
@@ -296,7 +517,7 @@ func (s *Sandbox) runCompile(box int, ctx context.Context, shellCommand string,
 	return results
 }
 
-func (s *Sandbox) runExecute(box int, ctx context.Context, qt models.QuestionTestScript, shellCommand string, codePath []byte, compileResult []SandboxJudgeResult) []SandboxJudgeResult {
+func (s *Sandbox) runExecute(box int, ctx context.Context, qt models.QuestionTestScript, shellCommand string, codePath []byte, compileResult []SandboxJudgeResult, targetSuites map[string][]string, bundle *artifactBundle) []SandboxJudgeResult {
 	var results []SandboxJudgeResult
 	for _, target := range compileResult {
 		if target.Status == "FAILED" {
@@ -328,14 +549,13 @@ func (s *Sandbox) runExecute(box int, ctx context.Context, qt models.QuestionTes
 				fmt.Sprintf("--env=CODE_PATH=%v", string(codePath)))
 		}
 
+		cmdArgs = append(cmdArgs, suiteFilterArgs(targetSuites[target.Target])...)
 		cmdArgs = append(cmdArgs, "--run", "--", "/usr/bin/bash", shellCommand, target.Target)
 
-		cmd := exec.CommandContext(ctx, "isolate", cmdArgs...)
-
 		result := SandboxJudgeResult{
 			Target: target.Target,
 		}
-		out, err := cmd.CombinedOutput()
+		out, err := runIsolate(ctx, bundle, "execute", target.Target, cmdArgs)
 		if err != nil {
 			outStr := string(out)
 			if strings.Contains(outStr, "Exited with error status 1") {
@@ -375,7 +595,7 @@ func (s *Sandbox) runExecute(box int, ctx context.Context, qt models.QuestionTes
 	return results
 }
 
-func (s *Sandbox) runScore(box int, ctx context.Context, shellCommand string, codePath []byte, mergeResult []SandboxJudgeResult) []SandboxScoreResult {
+func (s *Sandbox) runScore(box int, ctx context.Context, shellCommand string, codePath []byte, scoreMap []byte, practice bool, mergeResult []SandboxJudgeResult, targetSuites map[string][]string, bundle *artifactBundle) []SandboxScoreResult {
 	var results []SandboxScoreResult
 	for _, target := range mergeResult {
 		if target.Status != "SUCCESS" {
@@ -404,24 +624,54 @@ func (s *Sandbox) runScore(box int, ctx context.Context, shellCommand string, co
 				fmt.Sprintf("--env=CODE_PATH=%v", string(codePath)))
 		}
 
+		// 每個 target 使用 sandbox-server 建立的新報告目錄，mount 到 box 內供寫入，先前階段無法預先放入報告
+		reports, err := os.MkdirTemp("", "oj-reports-")
+		if err != nil {
+			results = append(results, SandboxScoreResult{
+				Target: target.Target,
+				Status: "FAILED",
+				Result: fmt.Sprintf("Failed to create report directory: %v", err),
+			})
+			continue
+		}
+		// box 內的使用者需要寫入報告
+		os.Chmod(reports, 0777)
+		cmdArgs = append(cmdArgs, fmt.Sprintf("--dir=%s=%s:rw", boxReportDir, reports))
+		cmdArgs = append(cmdArgs, suiteFilterArgs(targetSuites[target.Target])...)
+
 		cmdArgs = append(cmdArgs, "--run", "--", "/usr/bin/bash", shellCommand, target.Target)
 
 		utils.Debugf("Command: isolate %s", strings.Join(cmdArgs, " "))
-		cmd := exec.CommandContext(ctx, "isolate", cmdArgs...)
-
-		out, err := cmd.CombinedOutput()
+		out, err := runIsolate(ctx, bundle, "score", target.Target, cmdArgs)
 		result := SandboxScoreResult{
 			Target: target.Target,
+			Result: string(out),
 		}
 		if err != nil {
+			os.RemoveAll(reports)
 			result.Status = "FAILED"
-			result.Result = string(out)
+			results = append(results, result)
+			continue
+		}
 
+		// 在 box 外解析報告，同一 target 有多份報告時取最高分
+		parsed, errs := readReports(reports, scoreMap, practice)
+		os.RemoveAll(reports)
+		for i, report := range parsed {
+			bundle.add(fmt.Sprintf("score/%s.report.%s.json", artifactNameReplacer.Replace(target.Target), report.name), report.raw)
+			if result.Report == nil || report.score > result.Score {
+				result.Score = report.score
+				result.Report = &parsed[i].all
+			}
+		}
+		for _, err := range errs {
+			result.Result += fmt.Sprintf("\nInvalid test report: %v", err)
+		}
+		if result.Report == nil {
+			result.Status = "FAILED"
+			result.Result += "\nNo test report was produced"
 		} else {
-			score, _ := s.extractScore(string(out))
 			result.Status = "SUCCESS"
-			result.Result = string(out)
-			result.Score = score
 		}
 		results = append(results, result)
 	}
@@ -497,19 +747,3 @@ func (s *Sandbox) mergeCompileAndExecuteResult(
 
 	return finalResults
 }
-
-func (s *Sandbox) extractScore(out string) (float64, bool) {
-	re := regexp.MustCompile(`\b\d+(\.\d+)?\b`)
-	lines := strings.Split(out, "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if re.MatchString(line) && !strings.Contains(line, "OK") {
-			numStr := re.FindString(line)
-			if score, err := strconv.ParseFloat(numStr, 64); err == nil {
-				return score, true
-			}
-		}
-	}
-	return 0, false
-}
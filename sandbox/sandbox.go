@@ -2,7 +2,10 @@ package sandbox
 
 import (
 	"OJ-API/models"
+	pb "OJ-API/proto"
 	"OJ-API/utils"
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"sync"
@@ -12,18 +15,42 @@ import (
 )
 
 type Sandbox struct {
-	AvailableBoxIDs     *lockfree.Queue // Sandbox that can use
-	waitingQueue        *lockfree.Queue // Sandbox that executing code
-	jobQueue            *lockfree.Queue // Storing Unjudge job
-	sandboxCount        int             // How many sandbox
-	availableCount      int             // How many sandbox can use
-	availableCountMutex sync.RWMutex    // Mutex for availableCount
+	AvailableBoxIDs     *lockfree.Queue  // Sandbox that can use
+	waitingQueue        *lockfree.Queue  // Sandbox that executing code
+	jobQueue            *lockfree.Queue  // Storing Unjudge job
+	sandboxCount        int              // How many sandbox
+	availableCount      int              // How many sandbox can use
+	availableCountMutex sync.RWMutex     // Mutex for availableCount
+	onJobDone           func(key JobKey) // Called when a job finishes judging
+	cancelMutex         sync.Mutex
+	runningJobs         map[JobKey]context.CancelCauseFunc // Cancel functions of jobs being judged
+	acceptedJobs        map[JobKey]bool                    // Jobs received from the scheduler that have not started
+	cancelledJobs       map[JobKey]string                  // Accepted jobs cancelled before they started, with reason
+}
+
+// JobKey 識別沙箱上的任務。正式評測與練習評測的 ID 分屬不同資料表，需連同種類比對
+type JobKey struct {
+	Kind pb.JobKind
+	ID   uint
 }
 
 type Job struct {
 	Repo     string
 	CodePath []byte
 	UQR      models.UserQuestionTable
+	Kind     pb.JobKind      // 練習評測的 UQR 是由 PracticeRun 轉換而來，結果寫回 practice_runs
+	Ctx      context.Context // 帶有請求 ID 與 trace context，供日誌與追蹤串接
+	QueuedAt time.Time
+}
+
+// Key 回傳任務的識別
+func (j *Job) Key() JobKey {
+	return JobKey{Kind: j.Kind, ID: j.UQR.ID}
+}
+
+// Practice 回傳任務是否為練習評測
+func (j *Job) Practice() bool {
+	return j.Kind == pb.JobKind_JOB_KIND_PRACTICE
 }
 
 func NewSandbox(count int) *Sandbox {
@@ -44,6 +71,9 @@ func NewSandbox(count int) *Sandbox {
 		jobQueue:            lockfree.NewQueue(),
 		availableCount:      count,
 		availableCountMutex: sync.RWMutex{},
+		runningJobs:         make(map[JobKey]context.CancelCauseFunc),
+		acceptedJobs:        make(map[JobKey]bool),
+		cancelledJobs:       make(map[JobKey]string),
 	}
 	return s
 }
@@ -106,12 +136,15 @@ func (s *Sandbox) IsJobEmpty() bool {
 	return s.jobQueue.Length() == 0
 }
 
-func (s *Sandbox) ReserveJob(repo string, codePath []byte, uqtid models.UserQuestionTable) {
+func (s *Sandbox) ReserveJob(ctx context.Context, repo string, codePath []byte, uqtid models.UserQuestionTable, kind pb.JobKind) {
 
 	job := &Job{
 		Repo:     repo,
 		CodePath: codePath,
 		UQR:      uqtid,
+		Kind:     kind,
+		Ctx:      ctx,
+		QueuedAt: time.Now(),
 	}
 	s.jobQueue.Enqueue(job)
 }
@@ -133,6 +166,89 @@ func (s *Sandbox) ReleaseJob() *Job {
 	return job
 }
 
+// SetJobDoneHandler 設定任務評測結束（不論成功與否）時的回呼
+func (s *Sandbox) SetJobDoneHandler(fn func(key JobKey)) {
+	s.onJobDone = fn
+}
+
+func (s *Sandbox) jobDone(key JobKey) {
+	if s.onJobDone != nil {
+		s.onJobDone(key)
+	}
+}
+
+// AcceptJob 登記從調度器收到、尚未開始的任務，之後的取消請求才會被保留到任務開始
+func (s *Sandbox) AcceptJob(key JobKey) {
+	s.cancelMutex.Lock()
+	defer s.cancelMutex.Unlock()
+
+	s.acceptedJobs[key] = true
+}
+
+// DropJob 移除未能加入隊列的任務的登記
+func (s *Sandbox) DropJob(key JobKey) {
+	s.cancelMutex.Lock()
+	defer s.cancelMutex.Unlock()
+
+	delete(s.acceptedJobs, key)
+	delete(s.cancelledJobs, key)
+}
+
+// CancelJob 取消任務：執行中的任務立即中斷，尚未開始的任務在開始前丟棄。
+// 已結束或不在此沙箱的任務不需處理，不做登記
+func (s *Sandbox) CancelJob(key JobKey, reason string) bool {
+	s.cancelMutex.Lock()
+	defer s.cancelMutex.Unlock()
+
+	if cancel, ok := s.runningJobs[key]; ok {
+		cancel(errors.New(reason))
+		return true
+	}
+	if s.acceptedJobs[key] {
+		s.cancelledJobs[key] = reason
+		return true
+	}
+	return false
+}
+
+// startJob 登記執行中的任務，回傳可被 CancelJob 中斷的 context。
+// 若任務在開始前已被取消，ok 為 false 並回傳取消原因
+func (s *Sandbox) startJob(parent context.Context, key JobKey) (ctx context.Context, reason string, ok bool) {
+	s.cancelMutex.Lock()
+	defer s.cancelMutex.Unlock()
+
+	delete(s.acceptedJobs, key)
+	if reason, cancelled := s.cancelledJobs[key]; cancelled {
+		delete(s.cancelledJobs, key)
+		return nil, reason, false
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	s.runningJobs[key] = cancel
+	return ctx, "", true
+}
+
+// finishJob 移除執行中任務的登記
+func (s *Sandbox) finishJob(key JobKey) {
+	s.cancelMutex.Lock()
+	defer s.cancelMutex.Unlock()
+
+	delete(s.cancelledJobs, key)
+	if cancel, ok := s.runningJobs[key]; ok {
+		cancel(nil)
+		delete(s.runningJobs, key)
+	}
+}
+
+// killBox 結束 box 內殘留的 isolate 程序並重新初始化
+func (s *Sandbox) killBox(boxID int) {
+	if err := exec.Command("isolate", fmt.Sprintf("--box-id=%v", boxID), "--cleanup").Run(); err != nil {
+		utils.Warnf("Failed to clean up box %v: %v", boxID, err)
+	}
+	if err := exec.Command("isolate", fmt.Sprintf("--box-id=%v", boxID), "--init").Run(); err != nil {
+		utils.Warnf("Failed to re-init box %v: %v", boxID, err)
+	}
+}
+
 func (s *Sandbox) Cleanup() {
 	for i := 0; i < s.sandboxCount; i++ {
 		cmd := exec.Command("isolate", "-b", fmt.Sprintf("%v", i), "--cleanup")
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"fmt"
+
 	"OJ-API/config"
 	"OJ-API/database"
 	"OJ-API/models"
@@ -24,29 +26,16 @@ func GiteaCheckInit() {
 }
 
 func giteaCheck() {
-	// Find Admin user for Gitea operations
 	db := database.DBConn
-	var adminUser models.User
-	if err := db.First(&adminUser, models.User{
-		IsAdmin: true,
-	}).Error; err != nil {
-		utils.Errorf("Failed to find admin user: %v", err)
-	}
-	giteaToken, err := utils.GetToken(adminUser.ID)
-	if err != nil {
-		utils.Errorf("Failed to get Gitea token: %v", err)
-		return
-	}
-	// gitea connection
-	client, err := gitea.NewClient(config.GetGiteaBaseURL(), gitea.SetToken(giteaToken))
+	client, err := adminGiteaClient()
 	if err != nil {
 		utils.Errorf("Failed to create Gitea client: %v", err)
 		return
 	}
-	// 找出有效的 UQR
+	// 找出有效的 UQR，以及尚未設定 Webhook 密鑰的 UQR（不論題目時間，避免逾期或考試中的推送被拒絕）
 	var uqr []models.UserQuestionRelation
-	if err := db.Preload("User").Joins("JOIN questions ON questions.id = user_question_relations.question_id").
-		Where("questions.start_time <= ? AND questions.end_time >= ?", time.Now(), time.Now()).
+	if err := db.Joins("JOIN questions ON questions.id = user_question_relations.question_id").
+		Where("(questions.start_time <= ? AND questions.end_time >= ?) OR user_question_relations.webhook_secret = ''", time.Now(), time.Now()).
 		Find(&uqr).Error; err != nil {
 		utils.Errorf("Failed to find user question relations: %v", err)
 		return
@@ -61,52 +50,79 @@ func giteaCheck() {
 		}
 		username, reponame := parts[0], parts[1]
 		utils.Debugf("Checking hooks for %s/%s...", username, reponame)
-		hooks, _, err := client.ListRepoHooks(username, reponame, gitea.ListHooksOptions{})
-		if err != nil {
-			utils.Errorf("Failed to list hooks for %s/%s: %v", username, reponame, err)
+		if err := EnsureRepoHook(client, &item); err != nil {
+			utils.Errorf("Failed to set up hook for %s/%s: %v", username, reponame, err)
 			continue
 		}
-		token, err := utils.GenerateAccessToken(item.User.ID, item.User.UserName, item.User.IsAdmin)
-		if err != nil {
-			utils.Errorf("Failed to generate token for %s/%s: %v", username, reponame, err)
-			break
-		}
+		// wait 100 ms
+		time.Sleep(100 * time.Millisecond)
+	}
+}
 
-		hookExists := false
-		for _, hook := range hooks {
-			if hook.Config["url"] == config.GetOJBaseURL()+"/api/gitea" {
-				if hook.AuthorizationHeader != token {
-					// 更新 Authorization Header
-					if _, err := client.EditRepoHook(username, reponame, hook.ID, gitea.EditHookOption{
-						Config: map[string]string{
-							"url":          config.GetOJBaseURL() + "/api/gitea",
-							"content_type": "json",
-						},
-						AuthorizationHeader: "Bearer " + token,
-					}); err != nil {
-						utils.Errorf("Failed to update hook for %s/%s: %v", username, reponame, err)
-					}
-				}
-				hookExists = true
-				break
-			}
+// adminGiteaClient 以管理員的 token 建立 Gitea 連線
+func adminGiteaClient() (*gitea.Client, error) {
+	db := database.DBConn
+	var adminUser models.User
+	if err := db.First(&adminUser, models.User{
+		IsAdmin: true,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to find admin user: %w", err)
+	}
+	giteaToken, err := utils.GetToken(adminUser.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Gitea token: %w", err)
+	}
+	return gitea.NewClient(config.GetGiteaBaseURL(), gitea.SetToken(giteaToken))
+}
+
+// EnsureRepoHook 確保倉庫有推送事件的 Webhook，並以 UQR 的密鑰簽名。
+// Gitea 不會回傳既有 Webhook 的密鑰，因此新產生密鑰或 Webhook 仍使用舊的 Authorization Header 時會重建 Webhook。
+func EnsureRepoHook(client *gitea.Client, uqr *models.UserQuestionRelation) error {
+	parts := strings.Split(uqr.GitUserRepoURL, "/")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid GitUserRepoURL format: %s", uqr.GitUserRepoURL)
+	}
+	username, reponame := parts[0], parts[1]
+
+	secret, created, err := utils.EnsureWebhookSecret(uqr)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook secret: %w", err)
+	}
+	hooks, _, err := client.ListRepoHooks(username, reponame, gitea.ListHooksOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list hooks: %w", err)
+	}
+
+	hookURL := config.GetOJBaseURL() + "/api/gitea"
+	hookExists := false
+	for _, hook := range hooks {
+		if hook.Config["url"] != hookURL {
+			continue
 		}
-		if !hookExists {
-			if _, _, err := client.CreateRepoHook(username, reponame, gitea.CreateHookOption{
-				Type:   "gitea",
-				Active: true,
-				Events: []string{"push"},
-				Config: map[string]string{
-					"url":          config.GetOJBaseURL() + "/api/gitea",
-					"content_type": "json",
-				},
-				AuthorizationHeader: "Bearer " + token,
-			}); err != nil {
-				utils.Errorf("Failed to create hook for %s/%s: %v", username, reponame, err)
-				continue
-			}
+		if !created && hook.AuthorizationHeader == "" && !hookExists {
+			hookExists = true
+			continue
 		}
-		// wait 100 ms
-		time.Sleep(100 * time.Millisecond)
+		// 刪除密鑰已失效或重複的 Webhook
+		if _, err := client.DeleteRepoHook(username, reponame, hook.ID); err != nil {
+			return fmt.Errorf("failed to delete outdated hook: %w", err)
+		}
+	}
+	if hookExists {
+		return nil
+	}
+
+	if _, _, err := client.CreateRepoHook(username, reponame, gitea.CreateHookOption{
+		Type:   "gitea",
+		Active: true,
+		Events: []string{"push"},
+		Config: map[string]string{
+			"url":          hookURL,
+			"content_type": "json",
+			"secret":       secret,
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to create hook: %w", err)
 	}
+	return nil
 }
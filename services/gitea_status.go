@@ -0,0 +1,77 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+
+	"OJ-API/config"
+	"OJ-API/database"
+	"OJ-API/models"
+	"OJ-API/sandbox/grp_parser"
+	"OJ-API/utils"
+)
+
+// commitStatusContext 是 OJ 在 Gitea 提交狀態中使用的名稱，同一提交的狀態會被後續回報覆蓋
+const commitStatusContext = "OJ / judge"
+
+// ReportCommitStatus 將提交的評測狀態回報到 Gitea，讓學生在倉庫頁面就能看到分數。
+// 練習評測與沒有指定提交（重新評分 HEAD）的提交不會回報。
+func ReportCommitStatus(userQuestionTableID uint64) {
+	db := database.DBConn
+	var uqt models.UserQuestionTable
+	if err := db.Preload("UQR").Where("id = ?", userQuestionTableID).Limit(1).Find(&uqt).Error; err != nil {
+		utils.Warnf("Failed to load submission %d for commit status: %v", userQuestionTableID, err)
+		return
+	}
+	if uqt.ID == 0 || uqt.Commit == "" {
+		return
+	}
+	parts := strings.Split(uqt.UQR.GitUserRepoURL, "/")
+	if len(parts) < 2 {
+		utils.Warnf("Invalid GitUserRepoURL format: %s", uqt.UQR.GitUserRepoURL)
+		return
+	}
+
+	client, err := adminGiteaClient()
+	if err != nil {
+		utils.Warnf("Failed to create Gitea client for commit status: %v", err)
+		return
+	}
+	state, description := commitStatus(uqt)
+	if _, _, err := client.CreateStatus(parts[0], parts[1], uqt.Commit, gitea.CreateStatusOption{
+		State:       state,
+		TargetURL:   config.GetResultPageURL(uqt.ID),
+		Description: description,
+		Context:     commitStatusContext,
+	}); err != nil {
+		utils.Warnf("Failed to report commit status of submission %d: %v", uqt.ID, err)
+	}
+}
+
+// commitStatus 依評測結果決定提交狀態：全部測資通過為 success，否則為 failure
+func commitStatus(uqt models.UserQuestionTable) (gitea.StatusState, string) {
+	switch uqt.Score {
+	case -3:
+		return gitea.StatusPending, "Waiting for judging"
+	case -1:
+		return gitea.StatusPending, "Judging"
+	case -2:
+		return gitea.StatusError, "System error"
+	case -4:
+		return gitea.StatusError, "Cancelled"
+	}
+
+	description := fmt.Sprintf("Score: %g", uqt.Score)
+	if uqt.Late && uqt.LatePenalty > 0 {
+		description += fmt.Sprintf(" (late, -%g%%)", uqt.LatePenalty)
+	}
+	var report grp_parser.InputJSON
+	if err := json.Unmarshal([]byte(uqt.Message), &report); err != nil || report.Tests == 0 ||
+		report.Failures > 0 || report.Errors > 0 {
+		return gitea.StatusFailure, description
+	}
+	return gitea.StatusSuccess, description
+}
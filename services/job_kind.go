@@ -0,0 +1,22 @@
+package services
+
+import (
+	"OJ-API/models"
+	pb "OJ-API/proto"
+)
+
+// jobKindName 回傳任務種類在 sandbox_jobs.kind 中使用的名稱
+func jobKindName(kind pb.JobKind) string {
+	if kind == pb.JobKind_JOB_KIND_PRACTICE {
+		return models.SandboxJobPractice
+	}
+	return models.SandboxJobJudge
+}
+
+// jobKindFromName 將 sandbox_jobs.kind 的名稱轉回任務種類
+func jobKindFromName(name string) pb.JobKind {
+	if name == models.SandboxJobPractice {
+		return pb.JobKind_JOB_KIND_PRACTICE
+	}
+	return pb.JobKind_JOB_KIND_JUDGE
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"OJ-API/database"
+	"OJ-API/models"
+	"OJ-API/utils"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// HTTPRequestDuration API 請求耗時（依路由）
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "oj_http_request_duration_seconds",
+		Help: "HTTP request latency by route.",
+	}, []string{"method", "route", "status"})
+	// WebhookRequests 收到的 Gitea webhook 數量（依回應狀態碼）
+	WebhookRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "oj_webhook_requests_total",
+		Help: "Gitea push webhooks received, by response status.",
+	}, []string{"status"})
+
+	dispatchLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "oj_sandbox_dispatch_latency_seconds",
+		Help:    "Time from enqueueing a judge job to assigning it to a sandbox.",
+		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	})
+)
+
+// RegisterMetrics 註冊 API Server 在抓取時才計算的指標
+func RegisterMetrics() {
+	prometheus.MustRegister(queueDepthCollector{
+		desc: prometheus.NewDesc("oj_sandbox_queue_depth",
+			"Judge jobs waiting in the shared queue, by priority.", []string{"priority"}, nil),
+	})
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "oj_sandbox_connected",
+		Help: "Sandboxes connected to this API replica.",
+	}, func() float64 {
+		return float64(GetSandboxScheduler().GetActiveInstanceCount())
+	})
+}
+
+// queueDepthCollector 在抓取時查詢共享隊列，優先權的種類不固定，因此不使用 GaugeVec
+type queueDepthCollector struct {
+	desc *prometheus.Desc
+}
+
+func (c queueDepthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c queueDepthCollector) Collect(ch chan<- prometheus.Metric) {
+	collectQueueDepth(func(value float64, labelValues ...string) {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, value, labelValues...)
+	})
+}
+
+// collectQueueDepth 查詢各優先權的排隊任務數
+func collectQueueDepth(emit func(value float64, labelValues ...string)) {
+	var rows []struct {
+		Priority int
+		Count    int64
+	}
+	if err := database.DBConn.Model(&models.SandboxJob{}).
+		Select("priority, COUNT(*) AS count").
+		Where("status = ?", models.SandboxJobQueued).
+		Group("priority").
+		Scan(&rows).Error; err != nil {
+		utils.Warnf("Failed to collect queue depth: %v", err)
+		return
+	}
+
+	depth := map[string]int64{
+		priorityName(models.SandboxJobPriorityLow):    0,
+		priorityName(models.SandboxJobPriorityNormal): 0,
+	}
+	for _, row := range rows {
+		depth[priorityName(row.Priority)] += row.Count
+	}
+	for _, name := range []string{"low", "normal"} {
+		emit(float64(depth[name]), name)
+		delete(depth, name)
+	}
+	for name, count := range depth {
+		emit(float64(count), name)
+	}
+}
+
+func priorityName(priority int) string {
+	switch priority {
+	case models.SandboxJobPriorityLow:
+		return "low"
+	case models.SandboxJobPriorityNormal:
+		return "normal"
+	}
+	return strconv.Itoa(priority)
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"OJ-API/database"
+	pb "OJ-API/proto"
+	"OJ-API/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// jobsChannel 有新任務或任務重新排隊時通知所有副本
+	jobsChannel = "sandbox_jobs"
+	// commandsChannel 轉送管理指令給持有沙箱連線的副本
+	commandsChannel = "sandbox_commands"
+)
+
+// sandboxCommand 經由 NOTIFY 轉送的指令
+type sandboxCommand struct {
+	ReplicaID string `json:"replica_id"`
+	SandboxID string `json:"sandbox_id"`
+	Message   []byte `json:"message"` // 序列化後的 SchedulerMessage
+}
+
+// notifyJobs 通知所有副本隊列有任務可派發
+func (s *SandboxScheduler) notifyJobs() {
+	s.wakeDispatcher()
+	if err := database.DBConn.Exec("SELECT pg_notify(?, ?)", jobsChannel, s.replicaID).Error; err != nil {
+		utils.Warnf("Failed to notify %s: %v", jobsChannel, err)
+	}
+}
+
+// publishCommand 將指令轉送給指定副本
+func (s *SandboxScheduler) publishCommand(replicaID, sandboxID string, msg *pb.SchedulerMessage) error {
+	message, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(sandboxCommand{
+		ReplicaID: replicaID,
+		SandboxID: sandboxID,
+		Message:   message,
+	})
+	if err != nil {
+		return err
+	}
+
+	utils.Debugf("Forwarding command for sandbox %s to replica %s", sandboxID, replicaID)
+	return database.DBConn.Exec("SELECT pg_notify(?, ?)", commandsChannel, string(payload)).Error
+}
+
+// handleCommand 處理其他副本轉送的指令，只處理發給本副本的部分
+func (s *SandboxScheduler) handleCommand(payload string) {
+	var cmd sandboxCommand
+	if err := json.Unmarshal([]byte(payload), &cmd); err != nil {
+		utils.Warnf("Invalid sandbox command payload: %v", err)
+		return
+	}
+	if cmd.ReplicaID != s.replicaID {
+		return
+	}
+
+	msg := &pb.SchedulerMessage{}
+	if err := proto.Unmarshal(cmd.Message, msg); err != nil {
+		utils.Warnf("Invalid sandbox command message: %v", err)
+		return
+	}
+	if err := s.deliverCommand(cmd.SandboxID, msg); err != nil {
+		utils.Errorf("Failed to deliver command to sandbox %s: %v", cmd.SandboxID, err)
+	}
+}
+
+// listen 以獨立連線 LISTEN 任務與指令通知，斷線時自動重連
+func (s *SandboxScheduler) listen() {
+	for {
+		err := s.listenOnce()
+		select {
+		case <-s.ctx.Done():
+			return
+		default:
+		}
+		utils.Warnf("Sandbox scheduler listener stopped: %v, reconnecting...", err)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (s *SandboxScheduler) listenOnce() error {
+	dsn, err := database.DSN()
+	if err != nil {
+		return err
+	}
+	conn, err := pgx.Connect(s.ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %v", err)
+	}
+	defer conn.Close(context.Background())
+
+	for _, channel := range []string{jobsChannel, commandsChannel} {
+		if _, err := conn.Exec(s.ctx, "LISTEN "+channel); err != nil {
+			return fmt.Errorf("failed to listen %s: %v", channel, err)
+		}
+	}
+	utils.Debugf("Sandbox scheduler listening on %s and %s", jobsChannel, commandsChannel)
+
+	for {
+		notification, err := conn.WaitForNotification(s.ctx)
+		if err != nil {
+			return err
+		}
+		switch notification.Channel {
+		case jobsChannel:
+			s.wakeDispatcher()
+		case commandsChannel:
+			s.handleCommand(notification.Payload)
+		}
+	}
+}
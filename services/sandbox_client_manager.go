@@ -2,6 +2,7 @@ package services
 
 import (
 	pb "OJ-API/proto"
+	"context"
 	"sync"
 )
 
@@ -26,8 +27,13 @@ func GetSandboxClientManager() *SandboxClientManager {
 }
 
 // ReserveJob 添加任務到沙箱隊列
-func (m *SandboxClientManager) ReserveJob(parentGitFullName string, gitRepoURL string, gitFullName string, gitAfterHash string, gitUsername string, gitToken string, userQuestionTableID uint64) error {
-	return m.scheduler.ReserveJob(parentGitFullName, gitRepoURL, gitFullName, gitAfterHash, gitUsername, gitToken, userQuestionTableID)
+func (m *SandboxClientManager) ReserveJob(ctx context.Context, parentGitFullName string, gitRepoURL string, gitFullName string, gitAfterHash string, gitUsername string, gitToken string, userQuestionTableID uint64, priority int) error {
+	return m.scheduler.ReserveJob(ctx, parentGitFullName, gitRepoURL, gitFullName, gitAfterHash, gitUsername, gitToken, userQuestionTableID, priority)
+}
+
+// ReservePracticeJob 添加練習評測到沙箱隊列
+func (m *SandboxClientManager) ReservePracticeJob(ctx context.Context, parentGitFullName string, gitRepoURL string, gitFullName string, gitAfterHash string, gitUsername string, gitToken string, practiceRunID uint64) error {
+	return m.scheduler.ReservePracticeJob(ctx, parentGitFullName, gitRepoURL, gitFullName, gitAfterHash, gitUsername, gitToken, practiceRunID)
 }
 
 // GetStatus 獲取沙箱狀態
@@ -1,34 +1,84 @@
 package services
 
 import (
+	"OJ-API/config"
+	"OJ-API/models"
 	pb "OJ-API/proto"
+	"OJ-API/tracing"
 	"OJ-API/utils"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"sync"
 	"time"
 
-	"golang.design/x/lockfree"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// SandboxInstance 表示一個沙箱實例
+// SandboxInstance 表示一個連接到本副本的沙箱實例
 type SandboxInstance struct {
-	ID       string
-	Capacity int32
-	Status   *pb.SandboxStatusResponse
-	LastSeen time.Time
-	Active   bool
-	Stream   pb.SchedulerService_SandboxStreamServer // 雙向流連接
-	JobChan  chan *pb.AddJobRequest                  // 任務通道
+	ID          string
+	Capacity    int32
+	Status      *pb.SandboxStatusResponse
+	LastSeen    time.Time
+	ConnectedAt time.Time
+	Active      bool
+	Cordoned    bool                                    // 隔離：不再派發新任務
+	Draining    bool                                    // 排空：不再派發新任務，等待執行中的任務完成
+	Labels      map[string]string                       // 節點標籤
+	Version     string                                  // sandbox-server 版本
+	Stream      pb.SchedulerService_SandboxStreamServer // 雙向流連接
+	JobChan     chan *pb.AddJobRequest                  // 任務通道
+	sendMutex   sync.Mutex                              // gRPC stream 不允許併發 Send
+	closed      bool                                    // JobChan 是否已關閉
 }
 
-// SandboxScheduler 管理多個沙箱實例的調度
+// send 發送消息到沙箱，確保同一時間只有一個 goroutine 寫入 stream
+func (i *SandboxInstance) send(msg *pb.SchedulerMessage) error {
+	i.sendMutex.Lock()
+	defer i.sendMutex.Unlock()
+	return i.Stream.Send(msg)
+}
+
+// SandboxInstanceInfo 沙箱實例的快照，供管理 API 使用
+type SandboxInstanceInfo struct {
+	ID              string            `json:"id"`
+	ReplicaID       string            `json:"replica_id"`
+	Capacity        int32             `json:"capacity"`
+	AvailableCount  int32             `json:"available_count"`
+	WaitingCount    int32             `json:"waiting_count"`
+	ProcessingCount int32             `json:"processing_count"`
+	LastSeen        time.Time         `json:"last_seen"`
+	ConnectedAt     time.Time         `json:"connected_at"`
+	Active          bool              `json:"active"`
+	Cordoned        bool              `json:"cordoned"`
+	Draining        bool              `json:"draining"`
+	Labels          map[string]string `json:"labels"`
+	Version         string            `json:"version"`
+	CurrentJobs     []uint64          `json:"current_jobs"`     // UserQuestionTable IDs being judged
+	CurrentPractice []uint64          `json:"current_practice"` // PracticeRun IDs being judged
+}
+
+// ErrSandboxNotFound 指定的沙箱實例不存在
+var ErrSandboxNotFound = errors.New("sandbox instance not found")
+
+// SandboxScheduler 管理沙箱的調度。
+// 任務隊列、沙箱註冊表與任務分配都存放在 Postgres，因此多個 API 副本可以同時運作：
+// 任何副本都能接收任務，沙箱可以連接到任何副本，由持有該沙箱連線的副本派發任務
 type SandboxScheduler struct {
 	pb.UnimplementedSchedulerServiceServer
-	instances map[string]*SandboxInstance
+	replicaID string
+	instances map[string]*SandboxInstance // 連接到本副本的沙箱
 	mutex     sync.RWMutex
-	jobQueue  *lockfree.Queue // Storing Unjudge job
+	wake      chan struct{} // 有新任務時喚醒派發循環
+	ctx       context.Context
+	cancel    context.CancelFunc
 }
 
 var (
@@ -39,18 +89,39 @@ var (
 // GetSandboxScheduler 獲取全局調度器實例
 func GetSandboxScheduler() *SandboxScheduler {
 	schedulerOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
 		globalScheduler = &SandboxScheduler{
+			replicaID: newReplicaID(),
 			instances: make(map[string]*SandboxInstance),
-			jobQueue:  lockfree.NewQueue(),
+			wake:      make(chan struct{}, 1),
+			ctx:       ctx,
+			cancel:    cancel,
 		}
+		utils.Infof("Sandbox scheduler started as replica %s", globalScheduler.replicaID)
 		// 啟動清理 goroutine
 		go globalScheduler.cleanupInactiveInstances()
 		// 啟動任務隊列處理 goroutine
 		go globalScheduler.processJobQueue()
+		// 監聽其他副本的通知
+		go globalScheduler.listen()
+		// 重新排隊失聯沙箱的任務（只有取得 advisory lock 的副本會執行）
+		go globalScheduler.maintain()
 	})
 	return globalScheduler
 }
 
+// newReplicaID 產生本副本的 ID，優先使用 REPLICA_ID（例如 k8s pod 名稱）
+func newReplicaID() string {
+	if id := config.Config("REPLICA_ID"); id != "" {
+		return id
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "replica"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8])
+}
+
 // SandboxStream 處理沙箱雙向流連接
 func (s *SandboxScheduler) SandboxStream(stream pb.SchedulerService_SandboxStreamServer) error {
 	var instance *SandboxInstance
@@ -58,11 +129,7 @@ func (s *SandboxScheduler) SandboxStream(stream pb.SchedulerService_SandboxStrea
 
 	defer func() {
 		if instance != nil {
-			s.mutex.Lock()
-			instance.Active = false
-			close(instance.JobChan)
-			delete(s.instances, sandboxID)
-			s.mutex.Unlock()
+			s.removeInstance(instance)
 			utils.Infof("Sandbox %s disconnected", sandboxID)
 		}
 	}()
@@ -83,19 +150,34 @@ func (s *SandboxScheduler) SandboxStream(stream pb.SchedulerService_SandboxStrea
 			connectReq := msgType.Connect
 			sandboxID = connectReq.SandboxId
 
+			// Postgres 只保存到微秒，截斷後才能用 connected_at 比對是否為同一次連線
+			connectedAt := time.Now().Truncate(time.Microsecond)
 			instance = &SandboxInstance{
-				ID:       sandboxID,
-				Capacity: connectReq.Capacity,
-				LastSeen: time.Now(),
-				Active:   true,
-				Stream:   stream,
-				JobChan:  make(chan *pb.AddJobRequest, 100),
+				ID:          sandboxID,
+				Capacity:    connectReq.Capacity,
+				LastSeen:    connectedAt,
+				ConnectedAt: connectedAt,
+				Active:      true,
+				Cordoned:    connectReq.Cordoned,
+				Draining:    connectReq.Draining,
+				Labels:      connectReq.Labels,
+				Version:     connectReq.Version,
+				Stream:      stream,
+				JobChan:     make(chan *pb.AddJobRequest, 100),
 			}
 
 			s.mutex.Lock()
+			// 同一個沙箱重新連線時，舊的連線作廢
+			if old, ok := s.instances[sandboxID]; ok {
+				s.closeInstance(old)
+			}
 			s.instances[sandboxID] = instance
 			s.mutex.Unlock()
 
+			if err := s.registerNode(instance); err != nil {
+				utils.Errorf("Failed to register sandbox %s: %v", sandboxID, err)
+			}
+
 			// 發送連接響應
 			response := &pb.SchedulerMessage{
 				SandboxId: sandboxID,
@@ -107,12 +189,12 @@ func (s *SandboxScheduler) SandboxStream(stream pb.SchedulerService_SandboxStrea
 				},
 			}
 
-			if err := stream.Send(response); err != nil {
+			if err := instance.send(response); err != nil {
 				utils.Errorf("Failed to send connect response: %v", err)
 				return err
 			}
 
-			utils.Infof("Sandbox %s connected successfully", sandboxID)
+			utils.Infof("Sandbox %s (version: %s) connected successfully", sandboxID, connectReq.Version)
 
 			// 立即請求狀態更新
 			statusRequest := &pb.SchedulerMessage{
@@ -122,7 +204,7 @@ func (s *SandboxScheduler) SandboxStream(stream pb.SchedulerService_SandboxStrea
 				},
 			}
 
-			if err := stream.Send(statusRequest); err != nil {
+			if err := instance.send(statusRequest); err != nil {
 				utils.Errorf("Failed to send initial status request: %v", err)
 			} else {
 				utils.Debugf("Requested initial status from sandbox %s", sandboxID)
@@ -134,8 +216,13 @@ func (s *SandboxScheduler) SandboxStream(stream pb.SchedulerService_SandboxStrea
 		case *pb.SandboxMessage_Status:
 			// 處理狀態更新
 			if instance != nil {
+				s.mutex.Lock()
 				instance.Status = msgType.Status
 				instance.LastSeen = time.Now()
+				s.mutex.Unlock()
+				if err := s.updateNodeStatus(instance, msgType.Status); err != nil {
+					utils.Warnf("Failed to update status of sandbox %s: %v", sandboxID, err)
+				}
 				utils.Debugf("Received status from sandbox %s - Available: %d, Waiting: %d, Processing: %d, Total: %d",
 					sandboxID, msgType.Status.AvailableCount, msgType.Status.WaitingCount,
 					msgType.Status.ProcessingCount, msgType.Status.TotalCount)
@@ -146,6 +233,17 @@ func (s *SandboxScheduler) SandboxStream(stream pb.SchedulerService_SandboxStrea
 			jobResp := msgType.JobResponse
 			utils.Infof("Job response from sandbox %s: Success=%t, Message=%s",
 				sandboxID, jobResp.Success, jobResp.Message)
+
+		case *pb.SandboxMessage_JobFinished:
+			// 處理任務完成通知
+			finished := msgType.JobFinished
+			if err := finishJob(finished.Kind, finished.TargetId); err != nil {
+				utils.Warnf("Failed to mark %s job %d finished: %v", jobKindName(finished.Kind), finished.TargetId, err)
+			}
+			if finished.Kind == pb.JobKind_JOB_KIND_JUDGE {
+				go ReportCommitStatus(finished.TargetId)
+			}
+			utils.Debugf("Sandbox %s finished %s job %d", sandboxID, jobKindName(finished.Kind), finished.TargetId)
 		}
 	}
 
@@ -154,7 +252,19 @@ func (s *SandboxScheduler) SandboxStream(stream pb.SchedulerService_SandboxStrea
 
 // sendJobsToSandbox 發送任務到沙箱
 func (s *SandboxScheduler) sendJobsToSandbox(instance *SandboxInstance) {
+	failed := false
 	for jobReq := range instance.JobChan {
+		// 連線已失效，剩下的任務放回隊列讓其他沙箱處理
+		if failed {
+			s.requeue(jobReq)
+			continue
+		}
+
+		// 沙箱端的 span 以發送的 span 為父節點
+		ctx, span := tracing.Start(jobContext(jobReq), "sandbox.send", trace.WithSpanKind(trace.SpanKindProducer),
+			trace.WithAttributes(attribute.String("oj.sandbox_id", instance.ID)))
+		jobReq.TraceContext = tracing.Inject(ctx)
+
 		message := &pb.SchedulerMessage{
 			SandboxId: instance.ID,
 			MessageType: &pb.SchedulerMessage_JobRequest{
@@ -162,21 +272,36 @@ func (s *SandboxScheduler) sendJobsToSandbox(instance *SandboxInstance) {
 			},
 		}
 
-		if err := instance.Stream.Send(message); err != nil {
-			utils.Errorf("Failed to send job to sandbox %s: %v", instance.ID, err)
-			// 將任務放回隊列或標記為失敗
-			break
+		if err := instance.send(message); err != nil {
+			utils.Ctx(ctx).Errorf("Failed to send job to sandbox %s: %v", instance.ID, err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.End()
+			s.requeue(jobReq)
+			failed = true
+			continue
 		}
 
-		utils.Debugf("Sent job to sandbox %s", instance.ID)
+		utils.Ctx(ctx).Debugf("Sent job to sandbox %s", instance.ID)
+		span.End()
 	}
 }
 
-// GetBestSandbox 根據負載選擇最佳的沙箱實例
+// requeue 將任務放回隊列並喚醒所有副本的派發循環
+func (s *SandboxScheduler) requeue(jobReq *pb.AddJobRequest) {
+	if err := requeueJob(jobReq.Kind, jobReq.TargetId); err != nil {
+		utils.Errorf("Failed to requeue %s job %d: %v", jobKindName(jobReq.Kind), jobReq.TargetId, err)
+		return
+	}
+	s.notifyJobs()
+}
+
+// GetBestSandbox 根據負載選擇本副本上最佳的沙箱實例，呼叫前需持有 mutex
 func (s *SandboxScheduler) GetBestSandbox() *SandboxInstance {
 	var candidates []*SandboxInstance
 	for _, instance := range s.instances {
-		if instance.Active && instance.Status != nil && instance.Status.AvailableCount > 0 {
+		if instance.Active && !instance.Cordoned && !instance.Draining &&
+			instance.Status != nil && instance.Status.AvailableCount > 0 {
 			candidates = append(candidates, instance)
 		}
 	}
@@ -193,51 +318,79 @@ func (s *SandboxScheduler) GetBestSandbox() *SandboxInstance {
 	return candidates[0]
 }
 
-// ReserveJob 添加任務到最佳沙箱或隊列
-func (s *SandboxScheduler) ReserveJob(parentGitFullName string, gitRepoURL string, gitFullName string, gitAfterHash string, gitUsername string, gitToken string, userQuestionTableID uint64) error {
-	jobReq := &pb.AddJobRequest{
-		ParentGitFullName:   parentGitFullName,
-		GitRepoUrl:          gitRepoURL,
-		GitFullName:         gitFullName,
-		GitAfterHash:        gitAfterHash,
-		GitUsername:         gitUsername,
-		GitToken:            gitToken,
-		UserQuestionTableId: userQuestionTableID,
-	}
+// jobContext 回傳帶有任務請求 ID、評測目標 ID 與 trace context 的 context，供日誌與追蹤串接
+func jobContext(jobReq *pb.AddJobRequest) context.Context {
+	ctx := utils.WithRequestID(context.Background(), jobReq.RequestId)
+	ctx = utils.WithJobID(ctx, jobReq.TargetId)
+	return tracing.Extract(ctx, jobReq.TraceContext)
+}
 
-	// 將任務加入全局隊列
-	s.jobQueue.Enqueue(jobReq)
+// ReserveJob 將任務加入共享隊列，由任一持有空閒沙箱的副本依優先權派發。
+// ctx 的請求 ID 與 trace context 會隨任務送到沙箱
+func (s *SandboxScheduler) ReserveJob(ctx context.Context, parentGitFullName string, gitRepoURL string, gitFullName string, gitAfterHash string, gitUsername string, gitToken string, userQuestionTableID uint64, priority int) error {
+	if err := s.reserve(ctx, &pb.AddJobRequest{
+		ParentGitFullName: parentGitFullName,
+		GitRepoUrl:        gitRepoURL,
+		GitFullName:       gitFullName,
+		GitAfterHash:      gitAfterHash,
+		GitUsername:       gitUsername,
+		GitToken:          gitToken,
+		TargetId:          userQuestionTableID,
+		Kind:              pb.JobKind_JOB_KIND_JUDGE,
+	}, priority); err != nil {
+		return err
+	}
+	// 任務已排入隊列，在 Gitea 上標記為 pending
+	go ReportCommitStatus(userQuestionTableID)
 	return nil
 }
 
-// GetGlobalStatus 獲取所有沙箱的全局狀態
-func (s *SandboxScheduler) GetGlobalStatus() *pb.SandboxStatusResponse {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+// ReservePracticeJob 將練習評測加入共享隊列，排在正式評測之後
+func (s *SandboxScheduler) ReservePracticeJob(ctx context.Context, parentGitFullName string, gitRepoURL string, gitFullName string, gitAfterHash string, gitUsername string, gitToken string, practiceRunID uint64) error {
+	return s.reserve(ctx, &pb.AddJobRequest{
+		ParentGitFullName: parentGitFullName,
+		GitRepoUrl:        gitRepoURL,
+		GitFullName:       gitFullName,
+		GitAfterHash:      gitAfterHash,
+		GitUsername:       gitUsername,
+		GitToken:          gitToken,
+		TargetId:          practiceRunID,
+		Kind:              pb.JobKind_JOB_KIND_PRACTICE,
+	}, models.SandboxJobPriorityPractice)
+}
 
-	var totalAvailable, totalWaiting, totalProcessing, totalCount int32
+func (s *SandboxScheduler) reserve(ctx context.Context, jobReq *pb.AddJobRequest, priority int) error {
+	ctx, span := tracing.Start(ctx, "sandbox.enqueue", trace.WithAttributes(
+		attribute.String("oj.job_kind", jobReq.Kind.String()),
+		attribute.Int64("oj.target_id", int64(jobReq.TargetId)),
+		attribute.Int("oj.priority", priority),
+	))
+	defer span.End()
 
-	for _, instance := range s.instances {
-		if instance.Active && instance.Status != nil {
-			totalAvailable += instance.Status.AvailableCount
-			totalWaiting += instance.Status.WaitingCount
-			totalProcessing += instance.Status.ProcessingCount
-			totalCount += instance.Status.TotalCount
-		}
-	}
+	jobReq.RequestId = utils.RequestIDFromContext(ctx)
+	jobReq.TraceContext = tracing.Inject(ctx)
 
-	// 加上隊列中的任務數量到等待計數
-	totalWaiting += int32(s.jobQueue.Length())
+	// 將任務加入全局隊列
+	if err := enqueueJob(jobReq, priority); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	s.notifyJobs()
+	return nil
+}
 
-	return &pb.SandboxStatusResponse{
-		AvailableCount:  totalAvailable,
-		WaitingCount:    totalWaiting,
-		ProcessingCount: totalProcessing,
-		TotalCount:      totalCount,
+// GetGlobalStatus 獲取所有副本上沙箱的全局狀態
+func (s *SandboxScheduler) GetGlobalStatus() *pb.SandboxStatusResponse {
+	status, err := globalStatus()
+	if err != nil {
+		utils.Errorf("Failed to get global sandbox status: %v", err)
+		return &pb.SandboxStatusResponse{}
 	}
+	return status
 }
 
-// GetActiveInstanceCount 獲取活躍實例數量
+// GetActiveInstanceCount 獲取本副本的活躍實例數量
 func (s *SandboxScheduler) GetActiveInstanceCount() int {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -251,12 +404,169 @@ func (s *SandboxScheduler) GetActiveInstanceCount() int {
 	return count
 }
 
+// IsRegistered 檢查沙箱是否已連接到任一副本
+func (s *SandboxScheduler) IsRegistered(sandboxID string) bool {
+	if sandboxID == "" {
+		return false
+	}
+	registered, err := isNodeActive(sandboxID)
+	if err != nil {
+		utils.Errorf("Failed to check sandbox %s: %v", sandboxID, err)
+		return false
+	}
+	return registered
+}
+
+// ListInstances 列出所有副本上已連接的沙箱實例
+func (s *SandboxScheduler) ListInstances() ([]SandboxInstanceInfo, error) {
+	return listNodes()
+}
+
+// CordonSandbox 隔離或解除隔離沙箱，隔離後不再派發新任務
+func (s *SandboxScheduler) CordonSandbox(sandboxID string, cordoned bool) error {
+	if err := updateNodeFlag(sandboxID, "cordoned", cordoned); err != nil {
+		return err
+	}
+
+	utils.Infof("Sandbox %s cordoned=%t", sandboxID, cordoned)
+	return s.sendCommand(sandboxID, &pb.SchedulerMessage{
+		SandboxId: sandboxID,
+		MessageType: &pb.SchedulerMessage_Cordon{
+			Cordon: &pb.CordonRequest{Cordoned: cordoned},
+		},
+	})
+}
+
+// DrainSandbox 排空或解除排空沙箱：排空後停止派發新任務，已派發的任務繼續執行
+func (s *SandboxScheduler) DrainSandbox(sandboxID string, draining bool) error {
+	if err := updateNodeFlag(sandboxID, "draining", draining); err != nil {
+		return err
+	}
+
+	utils.Infof("Sandbox %s draining=%t", sandboxID, draining)
+	return s.sendCommand(sandboxID, &pb.SchedulerMessage{
+		SandboxId: sandboxID,
+		MessageType: &pb.SchedulerMessage_Drain{
+			Drain: &pb.DrainRequest{Draining: draining},
+		},
+	})
+}
+
+// ShutdownSandbox 要求沙箱在完成執行中的任務後優雅關機
+func (s *SandboxScheduler) ShutdownSandbox(sandboxID string, reason string) error {
+	if err := updateNodeFlag(sandboxID, "draining", true); err != nil {
+		return err
+	}
+
+	utils.Infof("Requesting shutdown of sandbox %s: %s", sandboxID, reason)
+	return s.sendCommand(sandboxID, &pb.SchedulerMessage{
+		SandboxId: sandboxID,
+		MessageType: &pb.SchedulerMessage_Shutdown{
+			Shutdown: &pb.ShutdownRequest{Reason: reason},
+		},
+	})
+}
+
+// CancelJob 取消任務。任務已派發時轉送 CancelJob 給負責的沙箱並回傳 true；
+// 仍在隊列中時直接移出隊列並回傳 false，由呼叫端記錄結果
+func (s *SandboxScheduler) CancelJob(userQuestionTableID uint64, reason string) (bool, error) {
+	removed, sandboxID, err := cancelQueuedJob(pb.JobKind_JOB_KIND_JUDGE, userQuestionTableID)
+	if err != nil {
+		return false, err
+	}
+	if removed || sandboxID == "" {
+		utils.Infof("Cancelled queued job %d", userQuestionTableID)
+		return false, nil
+	}
+
+	utils.Infof("Forwarding cancel of job %d to sandbox %s", userQuestionTableID, sandboxID)
+	return true, s.sendCommand(sandboxID, &pb.SchedulerMessage{
+		SandboxId: sandboxID,
+		MessageType: &pb.SchedulerMessage_CancelJob{
+			CancelJob: &pb.CancelJob{
+				TargetId: userQuestionTableID,
+				Reason:   reason,
+				Kind:     pb.JobKind_JOB_KIND_JUDGE,
+			},
+		},
+	})
+}
+
+// sendCommand 將管理指令送到沙箱：連接在本副本時直接發送，否則經由 NOTIFY 轉給持有連線的副本
+func (s *SandboxScheduler) sendCommand(sandboxID string, msg *pb.SchedulerMessage) error {
+	s.mutex.RLock()
+	_, local := s.instances[sandboxID]
+	s.mutex.RUnlock()
+	if local {
+		return s.deliverCommand(sandboxID, msg)
+	}
+
+	replicaID, err := nodeReplica(sandboxID)
+	if err != nil {
+		return err
+	}
+	return s.publishCommand(replicaID, sandboxID, msg)
+}
+
+// deliverCommand 套用指令到本副本的沙箱狀態並發送
+func (s *SandboxScheduler) deliverCommand(sandboxID string, msg *pb.SchedulerMessage) error {
+	s.mutex.Lock()
+	instance, ok := s.instances[sandboxID]
+	if !ok || !instance.Active {
+		s.mutex.Unlock()
+		return ErrSandboxNotFound
+	}
+	applyCommand(instance, msg)
+	s.mutex.Unlock()
+
+	return instance.send(msg)
+}
+
+// applyCommand 依管理指令更新實例的派發狀態，呼叫前需持有 mutex
+func applyCommand(instance *SandboxInstance, msg *pb.SchedulerMessage) {
+	switch msgType := msg.MessageType.(type) {
+	case *pb.SchedulerMessage_Cordon:
+		instance.Cordoned = msgType.Cordon.Cordoned
+	case *pb.SchedulerMessage_Drain:
+		instance.Draining = msgType.Drain.Draining
+	case *pb.SchedulerMessage_Shutdown:
+		instance.Draining = true
+	}
+}
+
+// closeInstance 將實例標記為失效並關閉任務通道，呼叫前需持有 mutex
+func (s *SandboxScheduler) closeInstance(instance *SandboxInstance) {
+	instance.Active = false
+	if !instance.closed {
+		instance.closed = true
+		close(instance.JobChan)
+	}
+}
+
+// removeInstance 移除斷線的實例（若已被新的連線取代則只關閉通道）
+func (s *SandboxScheduler) removeInstance(instance *SandboxInstance) {
+	s.mutex.Lock()
+	s.closeInstance(instance)
+	current := s.instances[instance.ID] == instance
+	if current {
+		delete(s.instances, instance.ID)
+	}
+	s.mutex.Unlock()
+
+	if current {
+		if err := unregisterNode(instance); err != nil {
+			utils.Warnf("Failed to unregister sandbox %s: %v", instance.ID, err)
+		}
+	}
+}
+
 // cleanupInactiveInstances 清理不活躍的實例
 func (s *SandboxScheduler) cleanupInactiveInstances() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
 	for range ticker.C {
+		var removed []*SandboxInstance
 		s.mutex.Lock()
 		now := time.Now()
 		for id, instance := range s.instances {
@@ -270,24 +580,39 @@ func (s *SandboxScheduler) cleanupInactiveInstances() {
 				// 如果超過 5 分鐘沒有狀態更新，完全移除
 				if now.Sub(instance.LastSeen) > 5*time.Minute {
 					utils.Infof("Removing inactive sandbox %s", id)
-					close(instance.JobChan)
+					s.closeInstance(instance)
 					delete(s.instances, id)
+					removed = append(removed, instance)
 				}
 			}
 		}
 		s.mutex.Unlock()
+
+		for _, instance := range removed {
+			if err := unregisterNode(instance); err != nil {
+				utils.Warnf("Failed to unregister sandbox %s: %v", instance.ID, err)
+			}
+		}
 	}
 }
 
 // Close 關閉調度器
 func (s *SandboxScheduler) Close() {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.cancel()
 
-	for _, instance := range s.instances {
-		close(instance.JobChan)
+	s.mutex.Lock()
+	instances := s.instances
+	for _, instance := range instances {
+		s.closeInstance(instance)
 	}
 	s.instances = make(map[string]*SandboxInstance)
+	s.mutex.Unlock()
+
+	for _, instance := range instances {
+		if err := unregisterNode(instance); err != nil {
+			utils.Warnf("Failed to unregister sandbox %s: %v", instance.ID, err)
+		}
+	}
 }
 
 // processJobQueue 處理任務隊列中的任務
@@ -295,63 +620,75 @@ func (s *SandboxScheduler) processJobQueue() {
 	ticker := time.NewTicker(300 * time.Millisecond)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		// 檢查是否有待處理的任務
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		// 持續派發直到隊列為空或本副本沒有空閒的沙箱
 		for {
-			jobReq := s.jobQueue.Dequeue()
-			if jobReq == nil {
-				break // 隊列為空，退出內層循環
+			assigned, err := s.assignJobToSandbox()
+			if err != nil {
+				utils.Debugf("Failed to assign job: %v", err)
+				break
 			}
-
-			// 轉換回正確的類型
-			if addJobReq, ok := jobReq.(*pb.AddJobRequest); ok {
-				// 嘗試分配任務到可用的沙箱
-				if err := s.assignJobToSandbox(addJobReq); err != nil {
-					// 如果無法分配，重新放回隊列
-					s.jobQueue.Enqueue(addJobReq)
-
-					// utils.Debugf("Job reassigned to queue: %v", err)
-					break // 退出內層循環，等待下次檢查
-				} else {
-					utils.Infof("Job from queue assigned successfully (parentGitFullName: %s, userQuestionTableId: %d)",
-						addJobReq.ParentGitFullName, addJobReq.UserQuestionTableId)
-				}
+			if !assigned {
+				break
 			}
 		}
 	}
 }
 
-// assignJobToSandbox 將任務分配給可用的沙箱
-func (s *SandboxScheduler) assignJobToSandbox(jobReq *pb.AddJobRequest) error {
-	s.mutex.Lock()
+// assignJobToSandbox 從共享隊列取出一個任務並分配給本副本可用的沙箱
+func (s *SandboxScheduler) assignJobToSandbox() (bool, error) {
+	s.mutex.RLock()
 	instance := s.GetBestSandbox()
+	s.mutex.RUnlock()
 	if instance == nil {
-		s.mutex.Unlock()
-		return fmt.Errorf("no available sandbox instances")
+		return false, nil
 	}
 
-	// 更新沙箱狀態
-	if instance.Status != nil {
-		instance.Status.WaitingCount++
-		instance.Status.AvailableCount--
-		utils.Debugf("Assigned job from queue to sandbox %s (waiting: %d, available: %d)",
-			instance.ID, instance.Status.WaitingCount, instance.Status.AvailableCount)
+	jobReq, err := claimJob(s.replicaID, instance.ID)
+	if err != nil {
+		return false, err
+	}
+	if jobReq == nil {
+		return false, nil
+	}
+
+	s.mutex.Lock()
+	if !instance.Active || instance.closed {
+		s.mutex.Unlock()
+		s.requeue(jobReq)
+		return false, fmt.Errorf("sandbox %s disconnected", instance.ID)
 	}
-	s.mutex.Unlock()
 
 	// 非阻塞發送到任務通道
 	select {
 	case instance.JobChan <- jobReq:
-		utils.Debugf("Job from queue assigned to sandbox %s", instance.ID)
-		return nil
-	default:
-		// 如果任務無法加入隊列，需要回滾之前的假設
-		s.mutex.Lock()
+		// 更新沙箱狀態，直到下一次狀態回報
 		if instance.Status != nil {
-			instance.Status.WaitingCount--
-			instance.Status.AvailableCount++
+			instance.Status.WaitingCount++
+			instance.Status.AvailableCount--
 		}
 		s.mutex.Unlock()
-		return fmt.Errorf("sandbox %s job queue is full", instance.ID)
+		utils.Ctx(jobContext(jobReq)).Infof("Job assigned to sandbox %s (parentGitFullName: %s, %s: %d)",
+			instance.ID, jobReq.ParentGitFullName, jobKindName(jobReq.Kind), jobReq.TargetId)
+		return true, nil
+	default:
+		s.mutex.Unlock()
+		s.requeue(jobReq)
+		return false, fmt.Errorf("sandbox %s job queue is full", instance.ID)
+	}
+}
+
+// wakeDispatcher 喚醒派發循環
+func (s *SandboxScheduler) wakeDispatcher() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
 	}
 }
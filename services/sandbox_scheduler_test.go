@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	pb "OJ-API/proto"
+)
+
+func TestApplyCommand(t *testing.T) {
+	cordon := func(cordoned bool) *pb.SchedulerMessage {
+		return &pb.SchedulerMessage{MessageType: &pb.SchedulerMessage_Cordon{Cordon: &pb.CordonRequest{Cordoned: cordoned}}}
+	}
+	drain := func(draining bool) *pb.SchedulerMessage {
+		return &pb.SchedulerMessage{MessageType: &pb.SchedulerMessage_Drain{Drain: &pb.DrainRequest{Draining: draining}}}
+	}
+	shutdown := &pb.SchedulerMessage{MessageType: &pb.SchedulerMessage_Shutdown{Shutdown: &pb.ShutdownRequest{}}}
+	cancel := &pb.SchedulerMessage{MessageType: &pb.SchedulerMessage_CancelJob{CancelJob: &pb.CancelJob{TargetId: 1}}}
+
+	tests := []struct {
+		name         string
+		commands     []*pb.SchedulerMessage
+		wantCordoned bool
+		wantDraining bool
+	}{
+		{"cordon", []*pb.SchedulerMessage{cordon(true)}, true, false},
+		{"uncordon", []*pb.SchedulerMessage{cordon(true), cordon(false)}, false, false},
+		{"drain", []*pb.SchedulerMessage{drain(true)}, false, true},
+		{"undrain", []*pb.SchedulerMessage{drain(true), drain(false)}, false, false},
+		{"uncordon keeps draining", []*pb.SchedulerMessage{cordon(true), drain(true), cordon(false)}, false, true},
+		{"undrain keeps cordon", []*pb.SchedulerMessage{cordon(true), drain(true), drain(false)}, true, false},
+		{"shutdown drains", []*pb.SchedulerMessage{shutdown}, false, true},
+		{"other commands keep flags", []*pb.SchedulerMessage{cordon(true), drain(true), cancel}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &SandboxInstance{}
+			for _, msg := range tt.commands {
+				applyCommand(instance, msg)
+			}
+			if instance.Cordoned != tt.wantCordoned || instance.Draining != tt.wantDraining {
+				t.Errorf("cordoned=%t draining=%t, want cordoned=%t draining=%t",
+					instance.Cordoned, instance.Draining, tt.wantCordoned, tt.wantDraining)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"OJ-API/config"
+	"OJ-API/database"
+	"OJ-API/models"
+	pb "OJ-API/proto"
+	"OJ-API/tracing"
+	"OJ-API/utils"
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// nodeActiveTimeout 超過此時間沒有狀態更新的沙箱視為不活躍
+	nodeActiveTimeout = time.Minute
+	// nodeExpireTimeout 超過此時間沒有狀態更新的沙箱從註冊表移除（例如副本當機未清理）
+	nodeExpireTimeout = 5 * time.Minute
+	// orphanJobTimeout 已分配但沙箱不在註冊表中超過此時間的任務會重新排隊
+	orphanJobTimeout = time.Minute
+	// maintenanceLockKey 定期維護使用的 advisory lock，確保同一時間只有一個副本執行
+	maintenanceLockKey = 7_300_001
+)
+
+// enqueueJob 將任務寫入共享隊列，同一個評測目標重複加入時重新排隊
+func enqueueJob(jobReq *pb.AddJobRequest, priority int) error {
+	token := jobReq.GitToken
+	if token != "" {
+		encrypted, err := utils.EncryptToken(token, config.Config("ENCRYPTION_KEY"))
+		if err != nil {
+			return err
+		}
+		token = encrypted
+	}
+
+	job := models.SandboxJob{
+		Kind:              jobKindName(jobReq.Kind),
+		TargetID:          uint(jobReq.TargetId),
+		ParentGitFullName: jobReq.ParentGitFullName,
+		GitRepoURL:        jobReq.GitRepoUrl,
+		GitFullName:       jobReq.GitFullName,
+		GitAfterHash:      jobReq.GitAfterHash,
+		GitUsername:       jobReq.GitUsername,
+		GitToken:          token,
+		RequestID:         jobReq.RequestId,
+		TraceContext:      jobReq.TraceContext,
+		Status:            models.SandboxJobQueued,
+		Priority:          priority,
+	}
+	return database.DBConn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kind"}, {Name: "target_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"parent_git_full_name", "git_repo_url", "git_full_name", "git_after_hash", "git_username", "git_token", "request_id", "trace_context", "status", "priority", "sandbox_id", "replica_id", "assigned_at", "updated_at"}),
+	}).Create(&job).Error
+}
+
+// claimJob 以 SKIP LOCKED 取出優先權最高、最早的排隊任務並分配給指定沙箱，隊列為空時回傳 nil
+func claimJob(replicaID, sandboxID string) (*pb.AddJobRequest, error) {
+	var job models.SandboxJob
+	now := time.Now().UTC()
+	err := database.DBConn.Raw(`UPDATE sandbox_jobs SET status = ?, sandbox_id = ?, replica_id = ?, assigned_at = ?, updated_at = ?
+		WHERE id = (SELECT id FROM sandbox_jobs WHERE status = ? ORDER BY priority DESC, id FOR UPDATE SKIP LOCKED LIMIT 1)
+		RETURNING *`,
+		models.SandboxJobAssigned, sandboxID, replicaID, now, now, models.SandboxJobQueued).Scan(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	if job.ID == 0 {
+		return nil, nil
+	}
+	dispatchLatency.Observe(time.Since(job.CreatedAt).Seconds())
+	tracing.RecordSpan(tracing.Extract(context.Background(), job.TraceContext), "sandbox.queue", job.CreatedAt, now,
+		attribute.String("oj.sandbox_id", sandboxID),
+		attribute.Int("oj.priority", job.Priority))
+
+	token := job.GitToken
+	if token != "" {
+		decrypted, err := utils.DecryptToken(token, config.Config("ENCRYPTION_KEY"))
+		if err != nil {
+			// 無法解密的任務無法執行，移出隊列避免重複嘗試
+			database.DBConn.Delete(&job)
+			return nil, err
+		}
+		token = decrypted
+	}
+
+	return &pb.AddJobRequest{
+		ParentGitFullName: job.ParentGitFullName,
+		GitRepoUrl:        job.GitRepoURL,
+		GitFullName:       job.GitFullName,
+		GitAfterHash:      job.GitAfterHash,
+		GitUsername:       job.GitUsername,
+		GitToken:          token,
+		TargetId:          uint64(job.TargetID),
+		RequestId:         job.RequestID,
+		TraceContext:      job.TraceContext,
+		Kind:              jobKindFromName(job.Kind),
+	}, nil
+}
+
+// requeueJob 將已分配的任務放回隊列
+func requeueJob(kind pb.JobKind, targetID uint64) error {
+	return database.DBConn.Model(&models.SandboxJob{}).
+		Where("kind = ? AND target_id = ?", jobKindName(kind), targetID).
+		Updates(map[string]interface{}{
+			"status":      models.SandboxJobQueued,
+			"sandbox_id":  "",
+			"replica_id":  "",
+			"assigned_at": nil,
+		}).Error
+}
+
+// finishJob 任務完成後移出隊列
+func finishJob(kind pb.JobKind, targetID uint64) error {
+	return database.DBConn.Where("kind = ? AND target_id = ?", jobKindName(kind), targetID).
+		Delete(&models.SandboxJob{}).Error
+}
+
+// cancelQueuedJob 移除仍在排隊的任務；已分配時回傳負責的沙箱 ID
+func cancelQueuedJob(kind pb.JobKind, targetID uint64) (bool, string, error) {
+	db := database.DBConn
+	result := db.Where("kind = ? AND target_id = ? AND status = ?", jobKindName(kind), targetID, models.SandboxJobQueued).
+		Delete(&models.SandboxJob{})
+	if result.Error != nil {
+		return false, "", result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, "", nil
+	}
+
+	var job models.SandboxJob
+	if err := db.Where("kind = ? AND target_id = ?", jobKindName(kind), targetID).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+	return false, job.SandboxID, nil
+}
+
+// registerNode 將連接到本副本的沙箱寫入註冊表
+func (s *SandboxScheduler) registerNode(instance *SandboxInstance) error {
+	node := models.SandboxNode{
+		ID:          instance.ID,
+		ReplicaID:   s.replicaID,
+		Capacity:    instance.Capacity,
+		Cordoned:    instance.Cordoned,
+		Draining:    instance.Draining,
+		Labels:      instance.Labels,
+		Version:     instance.Version,
+		LastSeen:    instance.LastSeen,
+		ConnectedAt: instance.ConnectedAt,
+	}
+	return database.DBConn.Clauses(clause.OnConflict{UpdateAll: true}).Create(&node).Error
+}
+
+// updateNodeStatus 更新沙箱的負載與最後回報時間
+func (s *SandboxScheduler) updateNodeStatus(instance *SandboxInstance, status *pb.SandboxStatusResponse) error {
+	return database.DBConn.Model(&models.SandboxNode{}).
+		Where("id = ? AND replica_id = ?", instance.ID, s.replicaID).
+		Updates(map[string]interface{}{
+			"available_count":  status.AvailableCount,
+			"waiting_count":    status.WaitingCount,
+			"processing_count": status.ProcessingCount,
+			"last_seen":        time.Now(),
+		}).Error
+}
+
+// unregisterNode 從註冊表移除沙箱，若沙箱已重新連接到其他副本則不影響新的紀錄
+func unregisterNode(instance *SandboxInstance) error {
+	return database.DBConn.Where("id = ? AND connected_at = ?", instance.ID, instance.ConnectedAt).
+		Delete(&models.SandboxNode{}).Error
+}
+
+// updateNodeFlag 更新沙箱的 cordoned/draining 狀態
+func updateNodeFlag(sandboxID string, column string, value bool) error {
+	result := database.DBConn.Model(&models.SandboxNode{}).Where("id = ?", sandboxID).Update(column, value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSandboxNotFound
+	}
+	return nil
+}
+
+// nodeReplica 查詢沙箱連接的副本
+func nodeReplica(sandboxID string) (string, error) {
+	var node models.SandboxNode
+	if err := database.DBConn.Select("replica_id").Where("id = ?", sandboxID).First(&node).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrSandboxNotFound
+		}
+		return "", err
+	}
+	return node.ReplicaID, nil
+}
+
+// isNodeActive 檢查沙箱是否已註冊且最近有狀態更新
+func isNodeActive(sandboxID string) (bool, error) {
+	var count int64
+	err := database.DBConn.Model(&models.SandboxNode{}).
+		Where("id = ? AND last_seen > ?", sandboxID, time.Now().Add(-nodeActiveTimeout)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// listNodes 列出註冊表中的沙箱與其執行中的任務
+func listNodes() ([]SandboxInstanceInfo, error) {
+	db := database.DBConn
+
+	var nodes []models.SandboxNode
+	if err := db.Order("id").Find(&nodes).Error; err != nil {
+		return nil, err
+	}
+
+	var jobs []models.SandboxJob
+	if err := db.Select("kind", "target_id", "sandbox_id").
+		Where("status = ?", models.SandboxJobAssigned).
+		Order("target_id").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	currentJobs := make(map[string][]uint64)
+	currentPractice := make(map[string][]uint64)
+	for _, job := range jobs {
+		if job.Kind == models.SandboxJobPractice {
+			currentPractice[job.SandboxID] = append(currentPractice[job.SandboxID], uint64(job.TargetID))
+		} else {
+			currentJobs[job.SandboxID] = append(currentJobs[job.SandboxID], uint64(job.TargetID))
+		}
+	}
+
+	now := time.Now()
+	infos := make([]SandboxInstanceInfo, 0, len(nodes))
+	for _, node := range nodes {
+		jobs := currentJobs[node.ID]
+		if jobs == nil {
+			jobs = []uint64{}
+		}
+		practiceRuns := currentPractice[node.ID]
+		if practiceRuns == nil {
+			practiceRuns = []uint64{}
+		}
+		infos = append(infos, SandboxInstanceInfo{
+			ID:              node.ID,
+			ReplicaID:       node.ReplicaID,
+			Capacity:        node.Capacity,
+			AvailableCount:  node.AvailableCount,
+			WaitingCount:    node.WaitingCount,
+			ProcessingCount: node.ProcessingCount,
+			LastSeen:        node.LastSeen,
+			ConnectedAt:     node.ConnectedAt,
+			Active:          now.Sub(node.LastSeen) <= nodeActiveTimeout,
+			Cordoned:        node.Cordoned,
+			Draining:        node.Draining,
+			Labels:          node.Labels,
+			Version:         node.Version,
+			CurrentJobs:     jobs,
+			CurrentPractice: practiceRuns,
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos, nil
+}
+
+// globalStatus 匯總所有活躍沙箱的狀態，並將排隊中的任務計入等待數
+func globalStatus() (*pb.SandboxStatusResponse, error) {
+	db := database.DBConn
+
+	var status struct {
+		Available  int32
+		Waiting    int32
+		Processing int32
+	}
+	if err := db.Model(&models.SandboxNode{}).
+		Select("COALESCE(SUM(available_count), 0) AS available, COALESCE(SUM(waiting_count), 0) AS waiting, COALESCE(SUM(processing_count), 0) AS processing").
+		Where("last_seen > ?", time.Now().Add(-nodeActiveTimeout)).
+		Scan(&status).Error; err != nil {
+		return nil, err
+	}
+
+	var queued int64
+	if err := db.Model(&models.SandboxJob{}).Where("status = ?", models.SandboxJobQueued).Count(&queued).Error; err != nil {
+		return nil, err
+	}
+
+	return &pb.SandboxStatusResponse{
+		AvailableCount:  status.Available,
+		WaitingCount:    status.Waiting + int32(queued),
+		ProcessingCount: status.Processing,
+		TotalCount:      status.Available + status.Processing,
+	}, nil
+}
+
+// maintain 定期清理過期的沙箱並重新排隊失聯沙箱的任務
+func (s *SandboxScheduler) maintain() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var requeued int64
+		err := database.DBConn.Transaction(func(tx *gorm.DB) error {
+			var leader bool
+			if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", maintenanceLockKey).Scan(&leader).Error; err != nil {
+				return err
+			}
+			if !leader {
+				return nil
+			}
+
+			now := time.Now()
+			if err := tx.Where("last_seen < ?", now.Add(-nodeExpireTimeout)).Delete(&models.SandboxNode{}).Error; err != nil {
+				return err
+			}
+
+			result := tx.Model(&models.SandboxJob{}).
+				Where("status = ? AND updated_at < ? AND sandbox_id NOT IN (?)",
+					models.SandboxJobAssigned, now.Add(-orphanJobTimeout),
+					tx.Model(&models.SandboxNode{}).Select("id")).
+				Updates(map[string]interface{}{
+					"status":      models.SandboxJobQueued,
+					"sandbox_id":  "",
+					"replica_id":  "",
+					"assigned_at": nil,
+				})
+			requeued = result.RowsAffected
+			return result.Error
+		})
+		if err != nil {
+			utils.Errorf("Sandbox scheduler maintenance failed: %v", err)
+			continue
+		}
+		if requeued > 0 {
+			utils.Warnf("Requeued %d jobs from disconnected sandboxes", requeued)
+			s.notifyJobs()
+		}
+	}
+}
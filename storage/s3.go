@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Options S3 相容服務（AWS S3、MinIO 等）的連線設定
+type S3Options struct {
+	Endpoint  string // host:port，不含 scheme
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Region    string
+	UseSSL    bool
+}
+
+// S3Store 將物件存放在 S3 相容服務的 bucket
+type S3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Store 建立 S3Store，bucket 不存在時自動建立
+func NewS3Store(opts S3Options) (*S3Store, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" {
+		return nil, errors.New("ARTIFACT_S3_ENDPOINT and ARTIFACT_S3_BUCKET are required")
+	}
+
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure: opts.UseSSL,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, opts.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, opts.Bucket, minio.MakeBucketOptions{Region: opts.Region}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &S3Store{client: client, bucket: opts.Bucket}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, data, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// GetObject 不會立即發出請求，先 Stat 以確認物件存在
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return object, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,65 @@
+// Package storage 提供評測產物（artifact）的物件儲存，支援本地檔案系統與 S3 相容服務
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"OJ-API/config"
+)
+
+// ErrNotFound 物件不存在
+var ErrNotFound = errors.New("object not found")
+
+// Store 以 key 存取物件
+type Store interface {
+	// Put 寫入物件，已存在時覆寫
+	Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error
+	// Get 讀取物件，不存在時回傳 ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 刪除物件，不存在時不回傳錯誤
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	defaultStore Store
+	defaultErr   error
+	defaultOnce  sync.Once
+)
+
+// Default 回傳依環境變數設定的 Store（只建立一次）
+//
+//	ARTIFACT_STORAGE   local（預設）或 s3
+//	ARTIFACT_DIR       local 的根目錄，預設 ./artifacts
+//	ARTIFACT_S3_*      s3 的 ENDPOINT、BUCKET、ACCESS_KEY、SECRET_KEY、REGION、USE_SSL
+func Default() (Store, error) {
+	defaultOnce.Do(func() {
+		defaultStore, defaultErr = New(config.Config("ARTIFACT_STORAGE"))
+	})
+	return defaultStore, defaultErr
+}
+
+// New 建立指定種類的 Store
+func New(kind string) (Store, error) {
+	switch kind {
+	case "", "local":
+		dir := config.Config("ARTIFACT_DIR")
+		if dir == "" {
+			dir = "./artifacts"
+		}
+		return NewLocalStore(dir)
+	case "s3":
+		return NewS3Store(S3Options{
+			Endpoint:  config.Config("ARTIFACT_S3_ENDPOINT"),
+			Bucket:    config.Config("ARTIFACT_S3_BUCKET"),
+			AccessKey: config.Config("ARTIFACT_S3_ACCESS_KEY"),
+			SecretKey: config.Config("ARTIFACT_S3_SECRET_KEY"),
+			Region:    config.Config("ARTIFACT_S3_REGION"),
+			UseSSL:    config.Config("ARTIFACT_S3_USE_SSL") != "false",
+		})
+	}
+	return nil, fmt.Errorf("unknown artifact storage %q", kind)
+}
@@ -0,0 +1,99 @@
+// Package tracing 設定 OpenTelemetry 追蹤，以 OTLP/gRPC 匯出到 collector，
+// 並在 gRPC 訊息之間以 W3C trace context 傳遞追蹤資訊
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"OJ-API/config"
+	"OJ-API/utils"
+)
+
+const instrumentationName = "OJ-API"
+
+// Init 初始化全域 TracerProvider。未設定 OTEL_EXPORTER_OTLP_ENDPOINT 時不匯出，
+// span 僅用於傳遞 context。回傳的函式會在結束前送出尚未匯出的 span
+func Init(ctx context.Context, serviceName, version string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if config.Config("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		utils.Debug("OTEL_EXPORTER_OTLP_ENDPOINT not set, tracing export disabled")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// endpoint、TLS 與 header 由 OTEL_EXPORTER_OTLP_* 環境變數設定
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 環境變數 OTEL_SERVICE_NAME、OTEL_RESOURCE_ATTRIBUTES 可覆寫預設值
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", version),
+		),
+		resource.WithHost(),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// 取樣方式由 OTEL_TRACES_SAMPLER、OTEL_TRACES_SAMPLER_ARG 設定
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	utils.Infof("Tracing enabled, exporting to %s", config.Config("OTEL_EXPORTER_OTLP_ENDPOINT"))
+
+	return provider.Shutdown, nil
+}
+
+// Tracer 回傳本專案使用的 Tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start 建立子 span
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// RecordSpan 記錄一段已經結束的區間（例如任務在隊列中等待的時間）
+func RecordSpan(ctx context.Context, name string, start, end time.Time, attrs ...attribute.KeyValue) {
+	_, span := Tracer().Start(ctx, name, trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+	span.End(trace.WithTimestamp(end))
+}
+
+// Inject 將 ctx 的 trace context 寫入 map，供放進 gRPC 訊息或資料庫
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract 從 map 還原 trace context，作為後續 span 的父節點
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
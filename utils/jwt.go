@@ -2,6 +2,7 @@ package utils
 
 import (
 	"OJ-API/config"
+	"OJ-API/models"
 	"sync"
 	"time"
 
@@ -25,19 +26,37 @@ func getJWTSecret() string {
 }
 
 type JWTClaims struct {
-	UserID    uint   `json:"user_id"`
-	Username  string `json:"username"`
-	IsAdmin   bool   `json:"is_admin"`
-	TokenType string `json:"token_type"` // "access" or "refresh"
+	UserID    uint        `json:"user_id"`
+	Username  string      `json:"username"`
+	IsAdmin   bool        `json:"is_admin"`
+	Role      models.Role `json:"role"`
+	TokenType string      `json:"token_type"` // "access" or "refresh"
 	jwt.RegisteredClaims
 }
 
+// EffectiveRole returns the role carried by the token; tokens issued before roles existed fall back to IsAdmin
+func (c *JWTClaims) EffectiveRole() models.Role {
+	if c.Role.Valid() {
+		return c.Role
+	}
+	if c.IsAdmin {
+		return models.RoleAdmin
+	}
+	return models.RoleStudent
+}
+
+// Can reports whether the token's role grants the permission; nil claims (anonymous) have no permissions
+func (c *JWTClaims) Can(p models.Permission) bool {
+	return c != nil && c.EffectiveRole().Can(p)
+}
+
 // GenerateAccessToken generates a short-lived access token (15 minutes)
-func GenerateAccessToken(userID uint, username string, isAdmin bool) (string, error) {
+func GenerateAccessToken(userID uint, username string, role models.Role) (string, error) {
 	claims := JWTClaims{
 		UserID:    userID,
 		Username:  username,
-		IsAdmin:   isAdmin,
+		IsAdmin:   role == models.RoleAdmin,
+		Role:      role,
 		TokenType: "access",
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
@@ -50,11 +69,12 @@ func GenerateAccessToken(userID uint, username string, isAdmin bool) (string, er
 }
 
 // GenerateRefreshToken generates a long-lived refresh token (7 days)
-func GenerateRefreshToken(userID uint, username string, isAdmin bool) (string, error) {
+func GenerateRefreshToken(userID uint, username string, role models.Role) (string, error) {
 	claims := JWTClaims{
 		UserID:    userID,
 		Username:  username,
-		IsAdmin:   isAdmin,
+		IsAdmin:   role == models.RoleAdmin,
+		Role:      role,
 		TokenType: "refresh",
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
@@ -67,13 +87,13 @@ func GenerateRefreshToken(userID uint, username string, isAdmin bool) (string, e
 }
 
 // GenerateTokens generates both access and refresh tokens
-func GenerateTokens(userID uint, username string, isAdmin bool) (accessToken, refreshToken string, err error) {
-	accessToken, err = GenerateAccessToken(userID, username, isAdmin)
+func GenerateTokens(userID uint, username string, role models.Role) (accessToken, refreshToken string, err error) {
+	accessToken, err = GenerateAccessToken(userID, username, role)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err = GenerateRefreshToken(userID, username, isAdmin)
+	refreshToken, err = GenerateRefreshToken(userID, username, role)
 	if err != nil {
 		return "", "", err
 	}
@@ -82,8 +102,8 @@ func GenerateTokens(userID uint, username string, isAdmin bool) (accessToken, re
 }
 
 // GenerateJWT generates a JWT token (deprecated, use GenerateAccessToken instead)
-func GenerateJWT(userID uint, username string, isAdmin bool) (string, error) {
-	return GenerateAccessToken(userID, username, isAdmin)
+func GenerateJWT(userID uint, username string, role models.Role) (string, error) {
+	return GenerateAccessToken(userID, username, role)
 }
 
 // ParseJWT parses a JWT token
@@ -2,9 +2,14 @@ package utils
 
 import (
 	"OJ-API/config"
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"runtime"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type LogLevel int
@@ -19,15 +24,66 @@ const (
 var currentLogLevel LogLevel
 var serverSource string = "main"
 
-// Logger wrapper
-type Logger struct {
-	debugLogger *log.Logger
-	infoLogger  *log.Logger
-	warnLogger  *log.Logger
-	errorLogger *log.Logger
+var logger = slog.New(newContextHandler(slog.NewTextHandler(os.Stdout, nil)))
+
+type logContextKey string
+
+const (
+	requestIDKey logContextKey = "request_id"
+	jobIDKey     logContextKey = "job_id"
+)
+
+// contextHandler adds the server source, the correlation IDs and the trace context stored in the context to every record
+type contextHandler struct {
+	slog.Handler
 }
 
-var logger *Logger
+func newContextHandler(h slog.Handler) *contextHandler {
+	return &contextHandler{Handler: h}
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(slog.String("server", serverSource))
+	if ctx != nil {
+		if requestID, ok := ctx.Value(requestIDKey).(string); ok && requestID != "" {
+			r.AddAttrs(slog.String("request_id", requestID))
+		}
+		if jobID, ok := ctx.Value(jobIDKey).(uint64); ok && jobID != 0 {
+			r.AddAttrs(slog.Uint64("job_id", jobID))
+		}
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			r.AddAttrs(
+				slog.String("trace_id", spanContext.TraceID().String()),
+				slog.String("span_id", spanContext.SpanID().String()),
+			)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// WithRequestID returns a context whose log records carry the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in the context
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithJobID returns a context whose log records carry the judge job (UserQuestionTable) ID
+func WithJobID(ctx context.Context, jobID uint64) context.Context {
+	return context.WithValue(ctx, jobIDKey, jobID)
+}
 
 func GetCurrentLogLevel() LogLevel {
 	return currentLogLevel
@@ -36,13 +92,6 @@ func GetCurrentLogLevel() LogLevel {
 // SetServerSource sets the server source for logging
 func SetServerSource(source string) {
 	serverSource = source
-	// Update logger prefixes if logger is already initialized
-	if logger != nil {
-		logger.debugLogger.SetPrefix(fmt.Sprintf("[%s][DEBUG] ", serverSource))
-		logger.infoLogger.SetPrefix(fmt.Sprintf("[%s][INFO] ", serverSource))
-		logger.warnLogger.SetPrefix(fmt.Sprintf("[%s][WARN] ", serverSource))
-		logger.errorLogger.SetPrefix(fmt.Sprintf("[%s][ERROR] ", serverSource))
-	}
 }
 
 // GetServerSource returns the current server source
@@ -50,101 +99,143 @@ func GetServerSource() string {
 	return serverSource
 }
 
+// Logger returns the structured logger, for callers that log attributes instead of formatted text
+func Logger() *slog.Logger {
+	return logger
+}
+
 // InitLog initializes the logger with the specified log level.
+// LOG_FORMAT selects "json" (default) or "text" output.
 func InitLog() {
 	logLevel := config.Config("LOG_LEVEL")
 
-	// Create loggers with different prefixes including server source
-	logger = &Logger{
-		debugLogger: log.New(os.Stdout, fmt.Sprintf("[%s][DEBUG] ", serverSource), log.LstdFlags|log.Lshortfile),
-		infoLogger:  log.New(os.Stdout, fmt.Sprintf("[%s][INFO] ", serverSource), log.LstdFlags),
-		warnLogger:  log.New(os.Stdout, fmt.Sprintf("[%s][WARN] ", serverSource), log.LstdFlags),
-		errorLogger: log.New(os.Stderr, fmt.Sprintf("[%s][ERROR] ", serverSource), log.LstdFlags|log.Lshortfile),
-	}
-
+	var level slog.Level
 	switch logLevel {
 	case "debug":
 		currentLogLevel = DEBUG
-		Info("Log level set to DEBUG")
+		level = slog.LevelDebug
 	case "info":
 		currentLogLevel = INFO
-		Info("Log level set to INFO")
+		level = slog.LevelInfo
 	case "warn":
 		currentLogLevel = WARN
-		Info("Log level set to WARN")
+		level = slog.LevelWarn
 	case "error":
 		currentLogLevel = ERROR
-		Info("Log level set to ERROR")
+		level = slog.LevelError
 	default:
-		logger.errorLogger.Output(2, fmt.Sprintf("Unknown log level: %s", logLevel))
+		fmt.Fprintf(os.Stderr, "Unknown log level: %s\n", logLevel)
 		os.Exit(1)
 	}
+
+	options := &slog.HandlerOptions{
+		Level:     level,
+		AddSource: currentLogLevel == DEBUG,
+	}
+	var handler slog.Handler
+	if config.Config("LOG_FORMAT") == "text" {
+		handler = slog.NewTextHandler(os.Stdout, options)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, options)
+	}
+	logger = slog.New(newContextHandler(handler))
+	slog.SetDefault(logger)
+
+	Infof("Log level set to %s", level)
+}
+
+// output writes a record attributing it to the caller of the exported logging function
+func output(ctx context.Context, level slog.Level, msg string) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip runtime.Callers, output and the exported wrapper
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	_ = logger.Handler().Handle(ctx, r)
 }
 
 // Debug logs debug messages
 func Debug(v ...interface{}) {
-	if currentLogLevel <= DEBUG {
-		logger.debugLogger.Output(2, fmt.Sprint(v...))
-	}
+	output(nil, slog.LevelDebug, fmt.Sprint(v...))
 }
 
 // Debugf logs debug messages with formatting
 func Debugf(format string, v ...interface{}) {
-	if currentLogLevel <= DEBUG {
-		logger.debugLogger.Output(2, fmt.Sprintf(format, v...))
-	}
+	output(nil, slog.LevelDebug, fmt.Sprintf(format, v...))
 }
 
 // Info logs info messages
 func Info(v ...interface{}) {
-	if currentLogLevel <= INFO {
-		logger.infoLogger.Output(2, fmt.Sprint(v...))
-	}
+	output(nil, slog.LevelInfo, fmt.Sprint(v...))
 }
 
 // Infof logs info messages with formatting
 func Infof(format string, v ...interface{}) {
-	if currentLogLevel <= INFO {
-		logger.infoLogger.Output(2, fmt.Sprintf(format, v...))
-	}
+	output(nil, slog.LevelInfo, fmt.Sprintf(format, v...))
 }
 
 // Warn logs warning messages
 func Warn(v ...interface{}) {
-	if currentLogLevel <= WARN {
-		logger.warnLogger.Output(2, fmt.Sprint(v...))
-	}
+	output(nil, slog.LevelWarn, fmt.Sprint(v...))
 }
 
 // Warnf logs warning messages with formatting
 func Warnf(format string, v ...interface{}) {
-	if currentLogLevel <= WARN {
-		logger.warnLogger.Output(2, fmt.Sprintf(format, v...))
-	}
+	output(nil, slog.LevelWarn, fmt.Sprintf(format, v...))
 }
 
 // Error logs error messages
 func Error(v ...interface{}) {
-	if currentLogLevel <= ERROR {
-		logger.errorLogger.Output(2, fmt.Sprint(v...))
-	}
+	output(nil, slog.LevelError, fmt.Sprint(v...))
 }
 
 // Errorf logs error messages with formatting
 func Errorf(format string, v ...interface{}) {
-	if currentLogLevel <= ERROR {
-		logger.errorLogger.Output(2, fmt.Sprintf(format, v...))
-	}
+	output(nil, slog.LevelError, fmt.Sprintf(format, v...))
 }
 
 // Fatal logs fatal messages and exits
 func Fatal(v ...interface{}) {
-	logger.errorLogger.Output(2, fmt.Sprint(v...))
+	output(nil, slog.LevelError, fmt.Sprint(v...))
 	os.Exit(1)
 }
 
 // Fatalf logs fatal messages with formatting and exits
 func Fatalf(format string, v ...interface{}) {
-	logger.errorLogger.Output(2, fmt.Sprintf(format, v...))
+	output(nil, slog.LevelError, fmt.Sprintf(format, v...))
 	os.Exit(1)
 }
+
+// ContextLogger logs with the request and job IDs stored in a context
+type ContextLogger struct {
+	ctx context.Context
+}
+
+// Ctx returns a logger that adds the correlation IDs of ctx to every record
+func Ctx(ctx context.Context) ContextLogger {
+	return ContextLogger{ctx: ctx}
+}
+
+// Debugf logs debug messages with formatting
+func (l ContextLogger) Debugf(format string, v ...interface{}) {
+	output(l.ctx, slog.LevelDebug, fmt.Sprintf(format, v...))
+}
+
+// Infof logs info messages with formatting
+func (l ContextLogger) Infof(format string, v ...interface{}) {
+	output(l.ctx, slog.LevelInfo, fmt.Sprintf(format, v...))
+}
+
+// Warnf logs warning messages with formatting
+func (l ContextLogger) Warnf(format string, v ...interface{}) {
+	output(l.ctx, slog.LevelWarn, fmt.Sprintf(format, v...))
+}
+
+// Errorf logs error messages with formatting
+func (l ContextLogger) Errorf(format string, v ...interface{}) {
+	output(l.ctx, slog.LevelError, fmt.Sprintf(format, v...))
+}
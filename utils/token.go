@@ -3,8 +3,11 @@ package utils
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -137,6 +140,55 @@ func GetTokenByUsername(username string) (string, error) {
 	return DecryptToken(user.GiteaToken, getEncryptionKey())
 }
 
+// ErrNoWebhookSecret is returned by WebhookSecret for repositories whose hook has not been given a secret yet
+var ErrNoWebhookSecret = errors.New("repository has no webhook secret")
+
+// WebhookSecret returns the decrypted secret that signs push events of the relation's repository.
+// It never creates one, so a missing secret is reported as ErrNoWebhookSecret.
+func WebhookSecret(uqr models.UserQuestionRelation) (string, error) {
+	if uqr.WebhookSecret == "" {
+		return "", ErrNoWebhookSecret
+	}
+	return DecryptToken(uqr.WebhookSecret, getEncryptionKey())
+}
+
+// EnsureWebhookSecret provisions the secret that signs push events of the relation's repository: it
+// returns the decrypted secret, generating and storing one if the relation has none. created reports
+// whether a new secret was stored, in which case the repository's hook has to be updated.
+func EnsureWebhookSecret(uqr *models.UserQuestionRelation) (secret string, created bool, err error) {
+	if uqr.WebhookSecret != "" {
+		secret, err = WebhookSecret(*uqr)
+		return secret, false, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, raw); err != nil {
+		return "", false, err
+	}
+	secret = hex.EncodeToString(raw)
+	encrypted, err := EncryptToken(secret, getEncryptionKey())
+	if err != nil {
+		return "", false, err
+	}
+	db := database.DBConn
+	if err := db.Model(&models.UserQuestionRelation{}).Where("id = ?", uqr.ID).Update("webhook_secret", encrypted).Error; err != nil {
+		return "", false, err
+	}
+	uqr.WebhookSecret = encrypted
+	return secret, true, nil
+}
+
+// ValidWebhookSignature checks a hex HMAC-SHA256 signature of a webhook body, as sent by Gitea in X-Gitea-Signature
+func ValidWebhookSignature(body []byte, signature, secret string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || secret == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
 func GenerateResetToken(userID uint) (string, error) {
 	nonce := uuid.New().String()
 	token := fmt.Sprintf("%d:%d:%s", userID, time.Now().Unix(), nonce)
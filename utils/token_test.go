@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"OJ-API/models"
+)
+
+func sign(body, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidWebhookSignature(t *testing.T) {
+	const body = `{"ref":"refs/heads/main"}`
+	const secret = "s3cret"
+	valid := sign(body, secret)
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		secret    string
+		want      bool
+	}{
+		{"valid", body, valid, secret, true},
+		{"uppercase hex", body, strings.ToUpper(valid), secret, true},
+		{"wrong secret", body, sign(body, "other"), secret, false},
+		{"tampered body", body + " ", valid, secret, false},
+		{"empty signature", body, "", secret, false},
+		{"invalid hex", body, "zz" + valid[2:], secret, false},
+		{"truncated signature", body, valid[:32], secret, false},
+		{"sha256= prefix", body, "sha256=" + valid, secret, false},
+		{"empty secret", body, sign(body, ""), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidWebhookSignature([]byte(tt.body), tt.signature, tt.secret); got != tt.want {
+				t.Errorf("ValidWebhookSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookSecretMissing(t *testing.T) {
+	if _, err := WebhookSecret(models.UserQuestionRelation{ID: 1}); !errors.Is(err, ErrNoWebhookSecret) {
+		t.Errorf("WebhookSecret() error = %v, want ErrNoWebhookSecret", err)
+	}
+}